@@ -1,10 +1,7 @@
 package handlers
 
 import (
-	"costly/core/errs"
-	"costly/core/ports/logger"
 	"costly/core/usecases/ingredients"
-	"errors"
 	"net/http"
 	"strconv"
 )
@@ -14,24 +11,17 @@ func AddIngredientStockHandler(ingredientStockAdder ingredients.IngredientStockA
 		ingredientIDstr := r.PathValue("ingredientID")
 		ingredientID, err := strconv.ParseInt(ingredientIDstr, 10, 64)
 		if err != nil {
-			RespondJSON(w, http.StatusBadRequest, ErrBadID)
+			RespondError(w, r, ErrBadID)
 			return
 		}
 		ingredientStockOptions := ingredients.IngredientStockOptions{}
 		if err := UnmarshallJSONBody(r, &ingredientStockOptions); err != nil {
-			RespondJSON(w, http.StatusBadRequest, ErrBadJson)
+			RespondError(w, r, ErrBadJson)
 			return
 		}
 		ingredientStock, err := ingredientStockAdder.AddStock(r.Context(), int64(ingredientID), ingredientStockOptions)
-		if errors.Is(err, errs.ErrBadOpts) {
-			RespondJSON(w, http.StatusBadRequest, NewInvalidInputResponseError(err.Error()))
-			return
-		} else if err == errs.ErrNotFound {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		} else if err != nil {
-			logger.Error(r.Context(), err, "error adding ingredient stock")
-			w.WriteHeader(http.StatusInternalServerError)
+		if err != nil {
+			RespondError(w, r, err)
 			return
 		}
 
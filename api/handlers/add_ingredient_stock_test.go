@@ -51,7 +51,7 @@ func TestHandleAddIngredientStock(t *testing.T) {
 				"units": 5,
 				"price": 12.5
 			}`,
-			expected:   "",
+			expected:   notFoundProblem,
 			statusCode: http.StatusNotFound,
 		},
 		{
@@ -62,10 +62,12 @@ func TestHandleAddIngredientStock(t *testing.T) {
 				"price": 12.5
 			}`,
 			expected: `{
-				"error": {
-					"code":"INVALID_INPUT",
-					"message":"units should be more than 0"
-				}
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "units should be more than 0",
+				"errors": [{"field": "units", "message": "units should be more than 0"}]
 			}`,
 			statusCode: http.StatusBadRequest,
 		},
@@ -76,10 +78,12 @@ func TestHandleAddIngredientStock(t *testing.T) {
 				"units": 5
 			}`,
 			expected: `{
-				"error": {
-					"code":"INVALID_INPUT",
-					"message":"price is invalid"
-				}
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "price is invalid",
+				"errors": [{"field": "price", "message": "price is invalid"}]
 			}`,
 			statusCode: http.StatusBadRequest,
 		},
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"bufio"
+	"costly/core/errs"
+	"costly/core/usecases/attachments"
+	"net/http"
+	"strconv"
+)
+
+const maxAttachmentSize = 10 << 20
+
+var ErrBadFile = errs.NewValidationError("file", "file is missing or too large")
+
+func AddRecipeAttachmentHandler(attachmentCreator attachments.AttachmentCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipeIDStr := r.PathValue("recipeID")
+		recipeID, err := strconv.ParseInt(recipeIDStr, 10, 64)
+		if err != nil {
+			RespondError(w, r, ErrBadID)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxAttachmentSize)
+		file, fileHeader, err := r.FormFile("file")
+		if err != nil {
+			RespondError(w, r, ErrBadFile)
+			return
+		}
+		defer file.Close()
+		// the content type is sniffed instead of trusting the one sent by the client
+		content := bufio.NewReaderSize(file, 512)
+		head, _ := content.Peek(512)
+		attachment, err := attachmentCreator.Create(r.Context(), recipeID, attachments.AttachmentOptions{
+			FileName:    fileHeader.Filename,
+			ContentType: http.DetectContentType(head),
+			Content:     content,
+		})
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, http.StatusCreated, attachment)
+	}
+}
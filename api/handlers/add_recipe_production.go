@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"costly/core/usecases/recipes"
+	"net/http"
+	"strconv"
+)
+
+func AddRecipeProductionHandler(recipeProducer recipes.RecipeProducer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipeIDStr := r.PathValue("recipeID")
+		recipeID, err := strconv.ParseInt(recipeIDStr, 10, 64)
+		if err != nil {
+			RespondError(w, r, ErrBadID)
+			return
+		}
+		productionOpts := recipes.ProductionOptions{}
+		if err := UnmarshallJSONBody(r, &productionOpts); err != nil {
+			RespondError(w, r, ErrBadJson)
+			return
+		}
+		production, err := recipeProducer.Produce(r.Context(), recipeID, productionOpts)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, http.StatusCreated, production)
+	}
+}
@@ -0,0 +1,149 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"costly/core/mocks"
+	"costly/core/model"
+	"costly/core/usecases"
+	"costly/core/usecases/ingredients"
+	"costly/core/usecases/recipes"
+	"net/http"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleAddRecipeProduction(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	testCases := []struct {
+		name        string
+		recipeIDstr string
+		payload     string
+		expected    string
+		statusCode  int
+	}{
+		{
+			name:        "should add recipe production",
+			recipeIDstr: "1",
+			payload: `{
+				"ingredient_id": 2,
+				"batches": 2,
+				"units": 500
+			}`,
+			expected: `{
+				"id": 1,
+				"recipe_id": 1,
+				"ingredient_id": 2,
+				"batches": 2,
+				"units": 500,
+				"price": 5,
+				"created_at": "1970-01-01T00:00:12.345Z"
+			}`,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:        "should get error if unexistent recipe",
+			recipeIDstr: "123",
+			payload: `{
+				"ingredient_id": 2,
+				"batches": 1,
+				"units": 500
+			}`,
+			expected:   notFoundProblem,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:        "should get error if batches is invalid",
+			recipeIDstr: "1",
+			payload: `{
+				"ingredient_id": 2,
+				"batches": 0,
+				"units": 500
+			}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "batches should be more than 0",
+				"errors": [{"field": "batches", "message": "batches should be more than 0"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "should get error if recipe consumes the produced ingredient",
+			recipeIDstr: "1",
+			payload: `{
+				"ingredient_id": 1,
+				"batches": 1,
+				"units": 500
+			}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "recipe can not consume the ingredient it produces",
+				"errors": [{"field": "ingredient_id", "message": "recipe can not consume the ingredient it produces"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "should get error if there is not enough stock of an ingredient",
+			recipeIDstr: "1",
+			payload: `{
+				"ingredient_id": 2,
+				"batches": 3,
+				"units": 1500
+			}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Conflict",
+				"status": 409,
+				"code": "INSUFFICIENT_STOCK",
+				"detail": "ingredient 1 has 1000 units in stock but 1500 are required"
+			}`,
+			statusCode: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/recipes/"+tc.recipeIDstr+"/productions", bytes.NewBufferString(tc.payload))
+			require.NoError(t, err)
+			rr := makeRequest(t, clock, func(useCases *usecases.UseCases) error {
+				_, err := useCases.Ingredients.Create(context.Background(), ingredients.CreateIngredientOptions{
+					Name:  "tomato",
+					Price: 2.5,
+					Unit:  model.Gram,
+				})
+				require.NoError(t, err)
+				_, err = useCases.Ingredients.AddStock(context.Background(), 1, ingredients.IngredientStockOptions{Units: 1000, Price: 2.5})
+				require.NoError(t, err)
+				_, err = useCases.Ingredients.Create(context.Background(), ingredients.CreateIngredientOptions{
+					Name:  "tomato sauce",
+					Price: 1.0,
+					Unit:  model.Gram,
+				})
+				require.NoError(t, err)
+				_, err = useCases.Recipes.Create(context.Background(), recipes.CreateRecipeOptions{
+					Name:        "tomato sauce batch",
+					Ingredients: []model.RecipeIngredient{{ID: 1, Units: 500}},
+				})
+				require.NoError(t, err)
+				return nil
+			}, req)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			if tc.expected != rr.Body.String() {
+				assert.JSONEq(t, tc.expected, rr.Body.String(), "Response body differs")
+			}
+		})
+	}
+}
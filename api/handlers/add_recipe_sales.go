@@ -1,10 +1,7 @@
 package handlers
 
 import (
-	"costly/core/errs"
-	"costly/core/ports/logger"
 	"costly/core/usecases/recipes"
-	"errors"
 	"net/http"
 	"strconv"
 )
@@ -18,24 +15,17 @@ func AddRecipeSalesHandler(recipeSalesAddres recipes.RecipeSalesAdder) http.Hand
 		recipeIDStr := r.PathValue("recipeID")
 		recipeID, err := strconv.ParseInt(recipeIDStr, 10, 64)
 		if err != nil {
-			RespondJSON(w, http.StatusBadRequest, ErrBadID)
+			RespondError(w, r, ErrBadID)
 			return
 		}
 		opts := recipeSalesOpts{}
 		if err := UnmarshallJSONBody(r, &opts); err != nil {
-			RespondJSON(w, http.StatusBadRequest, ErrBadJson)
+			RespondError(w, r, ErrBadJson)
 			return
 		}
 		ingredientStock, err := recipeSalesAddres.AddSales(r.Context(), recipeID, opts.SoldUnits)
-		if errors.Is(err, errs.ErrBadOpts) {
-			RespondJSON(w, http.StatusBadRequest, NewInvalidInputResponseError(err.Error()))
-			return
-		} else if err == errs.ErrNotFound {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		} else if err != nil {
-			logger.Error(r.Context(), err, "error adding ingredient stock")
-			w.WriteHeader(http.StatusInternalServerError)
+		if err != nil {
+			RespondError(w, r, err)
 			return
 		}
 		RespondJSON(w, http.StatusCreated, ingredientStock)
@@ -0,0 +1,146 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"costly/core/mocks"
+	"costly/core/model"
+	"costly/core/usecases"
+	"costly/core/usecases/ingredients"
+	"costly/core/usecases/recipes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// prepareArchive adds stock and a production to the spec examples, so that
+// every kind of entity is archived.
+func prepareArchive(t *testing.T) func(useCases *usecases.UseCases) error {
+	return func(useCases *usecases.UseCases) error {
+		ctx := context.Background()
+		require.NoError(t, prepareSpecExamples(t)(useCases))
+		_, err := useCases.Ingredients.AddStock(ctx, 1, ingredients.IngredientStockOptions{Units: 1000, Price: 0.012})
+		require.NoError(t, err)
+		_, err = useCases.Recipes.Produce(ctx, 1, recipes.ProductionOptions{IngredientID: 2, Batches: 1, Units: 250})
+		return err
+	}
+}
+
+func exportArchive(t *testing.T, prepare func(useCases *usecases.UseCases) error) []byte {
+	clock := new(mocks.ClockMock)
+	clock.On("Now").Return(time.UnixMilli(12345).UTC())
+	req, err := http.NewRequest("GET", "/admin/export", nil)
+	require.NoError(t, err)
+	rr := makeRequest(t, clock, prepare, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	return rr.Body.Bytes()
+}
+
+func TestHandleExportArchive(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	clock.On("Now").Return(time.UnixMilli(12345).UTC())
+	req, err := http.NewRequest("GET", "/admin/export", nil)
+	require.NoError(t, err)
+
+	rr := makeRequest(t, clock, prepareArchive(t), req)
+
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Equal(t, "attachment; filename=\"costly-19700101-000012.json\"", rr.Header().Get("Content-Disposition"))
+	archive := model.Archive{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &archive))
+	assert.Equal(t, model.ArchiveVersion, archive.Version)
+	assert.Equal(t, model.ArchiveSummary{Categories: 5, Ingredients: 2, Recipes: 3, Menus: 1, Stock: 3, Sales: 1, Productions: 1}, archive.Summary())
+	assert.Len(t, archive.RecipeRevisions, 4)
+	assert.Len(t, archive.IngredientPrices, 5)
+	assert.Equal(t, []model.RecipeIngredient{{ID: 1, Units: 250}}, archive.Recipes[0].Ingredients)
+	assert.Equal(t, 2, archive.Recipes[0].Version)
+}
+
+func TestHandleRestoreArchive(t *testing.T) {
+	exported := exportArchive(t, prepareArchive(t))
+	noData := func(useCases *usecases.UseCases) error { return nil }
+
+	testCases := []struct {
+		name       string
+		payload    []byte
+		prepare    func(useCases *usecases.UseCases) error
+		expected   string
+		statusCode int
+	}{
+		{
+			name:       "should restore the archive into an empty database",
+			payload:    exported,
+			prepare:    noData,
+			expected:   `{"categories": 5, "ingredients": 2, "recipes": 3, "menus": 1, "stock": 3, "sales": 1, "productions": 1}`,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:    "should not restore into a database with data",
+			payload: exported,
+			prepare: prepareClassifiedRecipes(t),
+			expected: `{
+				"type": "about:blank",
+				"title": "Conflict",
+				"status": 409,
+				"code": "CONFLICT",
+				"detail": "database should have no ingredients, recipes nor menus to restore an archive"
+			}`,
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:    "should not restore archives of unknown versions",
+			payload: []byte(`{"version": 3}`),
+			prepare: noData,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "archive version is not supported",
+				"errors": [{"field": "version", "message": "archive version is not supported"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "should not restore recipes of missing ingredients",
+			payload: []byte(`{"version": 1, "recipes": [{"id": 1, "name": "steak", "portions": 1, "ingredients": [{"id": 1, "units": 300}], "steps": [], "tags": [], "created_at": "2024-01-01T00:00:00Z", "last_modified": "2024-01-01T00:00:00Z"}]}`),
+			prepare: noData,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "archive refers to missing entities or repeats them"
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := new(mocks.ClockMock)
+			clock.On("Now").Return(time.UnixMilli(12345).UTC())
+			req, err := http.NewRequest("POST", "/admin/import", bytes.NewReader(tc.payload))
+			require.NoError(t, err)
+
+			rr := makeRequest(t, clock, tc.prepare, req)
+
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.JSONEq(t, tc.expected, rr.Body.String())
+		})
+	}
+
+	t.Run("should export the same archive once restored", func(t *testing.T) {
+		archive := model.Archive{}
+		require.NoError(t, json.Unmarshal(exported, &archive))
+		restored := exportArchive(t, func(useCases *usecases.UseCases) error {
+			_, err := useCases.Archives.Restore(context.Background(), archive)
+			return err
+		})
+		assert.JSONEq(t, string(exported), string(restored))
+	})
+}
@@ -0,0 +1,50 @@
+package handlers_test
+
+import (
+	"context"
+	"costly/core/mocks"
+	"costly/core/model"
+	"costly/core/usecases"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCreateBackup(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+	req, err := http.NewRequest("POST", "/admin/backups", nil)
+	require.NoError(t, err)
+
+	rr := makeRequest(t, clock, prepareClassifiedRecipes(t), req)
+
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	backup := model.Backup{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &backup))
+	assert.Equal(t, "costly-19700101-000012.345.db", backup.Name)
+	assert.Equal(t, now, backup.CreatedAt)
+	assert.Positive(t, backup.Size)
+}
+
+func TestHandleGetBackups(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	clock.On("Now").Return(time.UnixMilli(12345).UTC())
+	req, err := http.NewRequest("GET", "/admin/backups", nil)
+	require.NoError(t, err)
+
+	rr := makeRequest(t, clock, func(useCases *usecases.UseCases) error {
+		_, err := useCases.Backups.Create(context.Background())
+		return err
+	}, req)
+
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	backups := []model.Backup{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &backups))
+	require.Len(t, backups, 1)
+	assert.Equal(t, "costly-19700101-000012.345.db", backups[0].Name)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"costly/core/ports/logger"
+	"costly/core/usecases/batch"
+	"errors"
+	"net/http"
+)
+
+// BatchRequest is the body of the batch endpoints, with the mode of the batch
+// and the same payload of the single item endpoint for every item.
+type BatchRequest[T any] struct {
+	Mode  batch.Mode `json:"mode"`
+	Items []T        `json:"items"`
+}
+
+type BatchResponse struct {
+	Mode    batch.Mode        `json:"mode"`
+	Results []BatchItemResult `json:"results"`
+}
+
+// BatchItemResult is the result of the item of the batch at Index. ID is only
+// given when the item was saved, and Error only when it failed.
+type BatchItemResult struct {
+	Index  int      `json:"index"`
+	Status string   `json:"status"`
+	ID     int64    `json:"id,omitempty"`
+	Error  *Problem `json:"error,omitempty"`
+}
+
+// RespondBatch responds with the result of every item of the batch. It
+// responds okStatus when every item succeeded, 207 when only some did and
+// 422 when the batch was rolled back.
+func RespondBatch(w http.ResponseWriter, r *http.Request, mode batch.Mode, okStatus int, results []batch.Result, err error) {
+	rolledBack := errors.Is(err, batch.ErrRolledBack)
+	if err != nil && !rolledBack {
+		RespondError(w, r, err)
+		return
+	}
+	if mode == "" {
+		mode = batch.AllOrNothing
+	}
+	status := okStatus
+	response := BatchResponse{Mode: mode, Results: make([]BatchItemResult, len(results))}
+	for i, result := range results {
+		itemResult := BatchItemResult{Index: i}
+		switch {
+		case result.Err != nil:
+			problem, known := problemOf(result.Err)
+			if !known {
+				logger.Error(r.Context(), result.Err, "error handling item of "+r.Method+" "+r.URL.Path)
+			}
+			itemResult.Status = "failed"
+			itemResult.Error = problem
+			status = http.StatusMultiStatus
+		case rolledBack:
+			itemResult.Status = "rolled_back"
+		case okStatus == http.StatusCreated:
+			itemResult.Status = "created"
+			itemResult.ID = result.ID
+		default:
+			itemResult.Status = "updated"
+			itemResult.ID = result.ID
+		}
+		response.Results[i] = itemResult
+	}
+	if rolledBack {
+		status = http.StatusUnprocessableEntity
+	}
+	RespondJSON(w, status, response)
+}
@@ -0,0 +1,192 @@
+package handlers_test
+
+import (
+	"bytes"
+	"costly/core/mocks"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleBatch(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		payload    string
+		expected   string
+		statusCode int
+	}{
+		{
+			name:    "should create every ingredient",
+			method:  "POST",
+			path:    "/ingredients/batch",
+			payload: `{"items": [{"name": "salt", "unit": "gr", "price": 0.001}, {"name": "oil", "unit": "gr", "price": 0.005}]}`,
+			expected: `{
+				"mode": "all_or_nothing",
+				"results": [
+					{"index": 0, "status": "created", "id": 3},
+					{"index": 1, "status": "created", "id": 4}
+				]
+			}`,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:    "should roll back every ingredient if one fails",
+			method:  "POST",
+			path:    "/ingredients/batch",
+			payload: `{"mode": "all_or_nothing", "items": [{"name": "salt", "unit": "gr", "price": 0.001}, {"name": "oil", "unit": "gr", "price": 0}]}`,
+			expected: `{
+				"mode": "all_or_nothing",
+				"results": [
+					{"index": 0, "status": "rolled_back"},
+					{
+						"index": 1,
+						"status": "failed",
+						"error": {
+							"type": "about:blank",
+							"title": "Bad Request",
+							"status": 400,
+							"code": "INVALID_INPUT",
+							"detail": "price is invalid",
+							"errors": [{"field": "price", "message": "price is invalid"}]
+						}
+					}
+				]
+			}`,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "should keep the ingredients that succeed in best effort mode",
+			method:  "POST",
+			path:    "/ingredients/batch",
+			payload: `{"mode": "best_effort", "items": [{"name": "salt", "unit": "gr", "price": 0.001}, {"name": "oil", "unit": "gr", "price": 0}]}`,
+			expected: `{
+				"mode": "best_effort",
+				"results": [
+					{"index": 0, "status": "created", "id": 3},
+					{
+						"index": 1,
+						"status": "failed",
+						"error": {
+							"type": "about:blank",
+							"title": "Bad Request",
+							"status": 400,
+							"code": "INVALID_INPUT",
+							"detail": "price is invalid",
+							"errors": [{"field": "price", "message": "price is invalid"}]
+						}
+					}
+				]
+			}`,
+			statusCode: http.StatusMultiStatus,
+		},
+		{
+			name:    "should update the recipes that exist in best effort mode",
+			method:  "PUT",
+			path:    "/recipes/batch",
+			payload: `{"mode": "best_effort", "items": [{"id": 123, "version": 1, "name": "burger", "ingredients": [{"id": 1, "units": 150}]}, {"id": 2, "version": 1, "name": "creme caramel", "ingredients": [{"id": 2, "units": 250}]}]}`,
+			expected: `{
+				"mode": "best_effort",
+				"results": [
+					{
+						"index": 0,
+						"status": "failed",
+						"error": {
+							"type": "about:blank",
+							"title": "Not Found",
+							"status": 404,
+							"code": "NOT_FOUND",
+							"detail": "entity not found"
+						}
+					},
+					{"index": 1, "status": "updated", "id": 2}
+				]
+			}`,
+			statusCode: http.StatusMultiStatus,
+		},
+		{
+			name:    "should refuse to update the ingredients changed since the given version",
+			method:  "PUT",
+			path:    "/ingredients/batch",
+			payload: `{"mode": "best_effort", "items": [{"id": 1, "version": 1, "name": "beef", "unit": "gr", "price": 0.02}, {"id": 1, "version": 1, "name": "minced beef", "unit": "gr", "price": 0.02}]}`,
+			expected: `{
+				"mode": "best_effort",
+				"results": [
+					{"index": 0, "status": "updated", "id": 1},
+					{
+						"index": 1,
+						"status": "failed",
+						"error": {
+							"type": "about:blank",
+							"title": "Precondition Failed",
+							"status": 412,
+							"code": "PRECONDITION_FAILED",
+							"detail": "ingredient has been modified since it was read"
+						}
+					}
+				]
+			}`,
+			statusCode: http.StatusMultiStatus,
+		},
+		{
+			name:    "should return error if the version of an item is missing",
+			method:  "PUT",
+			path:    "/recipes/batch",
+			payload: `{"items": [{"id": 2, "name": "creme caramel", "ingredients": [{"id": 2, "units": 250}]}]}`,
+			expected: `{
+				"mode": "all_or_nothing",
+				"results": [
+					{
+						"index": 0,
+						"status": "failed",
+						"error": {
+							"type": "about:blank",
+							"title": "Bad Request",
+							"status": 400,
+							"code": "INVALID_INPUT",
+							"detail": "version should be more than 0",
+							"errors": [{"field": "version", "message": "version should be more than 0"}]
+						}
+					}
+				]
+			}`,
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:    "should return error if mode and items are invalid",
+			method:  "POST",
+			path:    "/recipes/batch",
+			payload: `{"mode": "sometimes", "items": []}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "mode should be all_or_nothing or best_effort; items should have between 1 and 1000 entries",
+				"errors": [
+					{"field": "mode", "message": "mode should be all_or_nothing or best_effort"},
+					{"field": "items", "message": "items should have between 1 and 1000 entries"}
+				]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.payload))
+			require.NoError(t, err)
+			rr := makeRequest(t, clock, prepareClassifiedRecipes(t), req)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.JSONEq(t, tc.expected, rr.Body.String(), "Response body differs")
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"costly/core/mocks"
+	"costly/core/model"
+	"costly/core/usecases"
+	"costly/core/usecases/categories"
+	"costly/core/usecases/ingredients"
+	"costly/core/usecases/recipes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+// prepareClassifiedRecipes makes a "grills" subcategory of mains holding a
+// steak sold out of stock, a dessert and an uncategorized recipe.
+func prepareClassifiedRecipes(t *testing.T) func(useCases *usecases.UseCases) error {
+	return func(useCases *usecases.UseCases) error {
+		ctx := context.Background()
+		grills, err := useCases.Categories.Create(ctx, categories.CreateCategoryOptions{Name: "Grills", ParentID: int64Ptr(2)})
+		require.NoError(t, err)
+		_, err = useCases.Ingredients.Create(ctx, ingredients.CreateIngredientOptions{Name: "meat", Price: 0.01, Unit: model.Gram, Tags: []string{"protein"}})
+		require.NoError(t, err)
+		_, err = useCases.Ingredients.Create(ctx, ingredients.CreateIngredientOptions{Name: "sugar", Price: 0.002, Unit: model.Gram, CategoryID: int64Ptr(3)})
+		require.NoError(t, err)
+		_, err = useCases.Recipes.Create(ctx, recipes.CreateRecipeOptions{
+			Name: "steak", Price: 10, CategoryID: &grills.ID, Tags: []string{"gluten-free"},
+			Ingredients: []model.RecipeIngredient{{ID: 1, Units: 300}},
+		})
+		require.NoError(t, err)
+		_, err = useCases.Recipes.Create(ctx, recipes.CreateRecipeOptions{
+			Name: "flan", Price: 4, CategoryID: int64Ptr(3), Tags: []string{"gluten-free", "vegetarian"},
+			Ingredients: []model.RecipeIngredient{{ID: 2, Units: 200}},
+		})
+		require.NoError(t, err)
+		_, err = useCases.Recipes.Create(ctx, recipes.CreateRecipeOptions{
+			Name:        "staff meal",
+			Ingredients: []model.RecipeIngredient{{ID: 1, Units: 100}},
+		})
+		require.NoError(t, err)
+		_, err = useCases.Ingredients.AddStock(ctx, 1, ingredients.IngredientStockOptions{Units: 2000, Price: 0.01})
+		require.NoError(t, err)
+		_, err = useCases.Recipes.AddSales(ctx, 1, 3)
+		return err
+	}
+}
+
+func TestHandleCreateCategory(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	testCases := []struct {
+		name       string
+		payload    string
+		expected   string
+		statusCode int
+	}{
+		{
+			name:    "should create subcategory if payload is valid",
+			payload: `{"name": " Tapas ", "parent_id": 1}`,
+			expected: `{
+				"id": 6,
+				"name": "tapas",
+				"parent_id": 1,
+				"created_at": "1970-01-01T00:00:12.345Z"
+			}`,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:    "should get error if parent does not exist",
+			payload: `{"name": "tapas", "parent_id": 123}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "category does not exist",
+				"errors": [{"field": "parent_id", "message": "category does not exist"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "should get error if name is invalid",
+			payload: `{"name": ""}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "name is invalid",
+				"errors": [{"field": "name", "message": "name is invalid"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/categories", bytes.NewBufferString(tc.payload))
+			require.NoError(t, err)
+			rr := makeRequest(t, clock, prepareClassifiedRecipes(t), req)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			if tc.expected != rr.Body.String() {
+				assert.JSONEq(t, tc.expected, rr.Body.String(), "Response body differs")
+			}
+		})
+	}
+}
+
+func TestHandleGetCategories(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	t.Run("should list the menu sections and their subcategories", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/categories", nil)
+		require.NoError(t, err)
+		rr := makeRequest(t, clock, prepareClassifiedRecipes(t), req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var categories []model.Category
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &categories))
+		names := []string{}
+		for _, category := range categories {
+			names = append(names, category.Name)
+		}
+		assert.Equal(t, []string{"starters", "mains", "desserts", "drinks", "grills"}, names)
+		assert.Equal(t, int64Ptr(2), categories[4].ParentID)
+	})
+
+	t.Run("should aggregate food cost and sales of every category", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/categories/aggregates", nil)
+		require.NoError(t, err)
+		rr := makeRequest(t, clock, prepareClassifiedRecipes(t), req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var aggregates []model.CategoryAggregate
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &aggregates))
+		require.Len(t, aggregates, 5)
+		mains, desserts, grills := aggregates[1], aggregates[2], aggregates[4]
+		assert.Equal(t, 1, mains.Recipes)
+		assert.InDelta(t, 30.0, mains.AverageFoodCostPercentage, 0.0001)
+		assert.Equal(t, 3, mains.SoldUnits)
+		assert.InDelta(t, 30.0, mains.Revenue, 0.0001)
+		assert.Equal(t, mains.Recipes, grills.Recipes)
+		assert.Equal(t, 1, desserts.Recipes)
+		assert.InDelta(t, 10.0, desserts.AverageFoodCostPercentage, 0.0001)
+		assert.Equal(t, 0, desserts.SoldUnits)
+		assert.Equal(t, 0, aggregates[0].Recipes)
+	})
+}
+
+func TestHandleFilterByCategoryAndTags(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	testCases := []struct {
+		name       string
+		path       string
+		expected   []string
+		statusCode int
+	}{
+		{
+			name:       "should get recipes of a category and its subcategories",
+			path:       "/recipes?category=2",
+			expected:   []string{"steak"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "should get recipes having all the tags",
+			path:       "/recipes?tag=Gluten-Free&tag=vegetarian",
+			expected:   []string{"flan"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "should get ingredients of a category",
+			path:       "/ingredients?category=3",
+			expected:   []string{"sugar"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "should get ingredients having a tag",
+			path:       "/ingredients?tag=protein",
+			expected:   []string{"meat"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "should get error if category is invalid",
+			path:       "/recipes?category=mains",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tc.path, nil)
+			require.NoError(t, err)
+			rr := makeRequest(t, clock, prepareClassifiedRecipes(t), req)
+			require.Equal(t, tc.statusCode, rr.Code)
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+			var found model.Page[struct{ Name string }]
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &found))
+			names := []string{}
+			for _, f := range found.Data {
+				names = append(names, f.Name)
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+}
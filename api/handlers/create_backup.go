@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"costly/core/usecases/backups"
+	"net/http"
+)
+
+func CreateBackupHandler(backupCreator backups.BackupCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		backup, err := backupCreator.Create(r.Context())
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, http.StatusCreated, backup)
+	}
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"costly/core/usecases/categories"
+	"net/http"
+)
+
+func CreateCategoryHandler(categoryCreator categories.CategoryCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		createCategoryOpts := categories.CreateCategoryOptions{}
+		if err := UnmarshallJSONBody(r, &createCategoryOpts); err != nil {
+			RespondError(w, r, ErrBadJson)
+			return
+		}
+		category, err := categoryCreator.Create(r.Context(), createCategoryOpts)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, http.StatusCreated, category)
+	}
+}
@@ -1,10 +1,7 @@
 package handlers
 
 import (
-	"costly/core/errs"
-	"costly/core/ports/logger"
 	"costly/core/usecases/ingredients"
-	"errors"
 	"net/http"
 )
 
@@ -13,16 +10,12 @@ func CreateIngredientHandler(ingredientCreator ingredients.IngredientCreator) ht
 		defer r.Body.Close()
 		createIngredientOpts := ingredients.CreateIngredientOptions{}
 		if err := UnmarshallJSONBody(r, &createIngredientOpts); err != nil {
-			RespondJSON(w, http.StatusBadRequest, ErrBadJson)
+			RespondError(w, r, ErrBadJson)
 			return
 		}
 		ingredient, err := ingredientCreator.Create(r.Context(), createIngredientOpts)
-		if errors.Is(err, errs.ErrBadOpts) {
-			RespondJSON(w, http.StatusBadRequest, NewInvalidInputResponseError(err.Error()))
-			return
-		} else if err != nil {
-			logger.Error(r.Context(), err, "error getting ingredient")
-			w.WriteHeader(http.StatusInternalServerError)
+		if err != nil {
+			RespondError(w, r, err)
 			return
 		}
 		RespondJSON(w, http.StatusCreated, ingredient)
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"costly/core/usecases/ingredients"
+	"net/http"
+)
+
+func CreateIngredientBatchHandler(ingredientBatcher ingredients.IngredientBatcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		batchRequest := BatchRequest[ingredients.CreateIngredientOptions]{}
+		if err := UnmarshallJSONBody(r, &batchRequest); err != nil {
+			RespondError(w, r, ErrBadJson)
+			return
+		}
+		results, err := ingredientBatcher.CreateBatch(r.Context(), batchRequest.Mode, batchRequest.Items)
+		RespondBatch(w, r, batchRequest.Mode, http.StatusCreated, results, err)
+	}
+}
@@ -48,19 +48,80 @@ func TestHandleCreateIngredient(t *testing.T) {
 				"unit":"gr",
 				"price":12.43,
 				"units_in_stock":0,
+				"allergens":[],
+				"nutrition":{"energy_kcal": 0, "protein": 0, "fat": 0, "saturated_fat": 0, "carbohydrate": 0, "sugar": 0, "salt": 0, "fibre": 0},
+				"category_id": null,
+				"tags": [],
 				"created_at":"1970-01-01T00:00:12.345Z",
 				"last_modified":"1970-01-01T00:00:12.345Z"
 			}`,
 			statusCode: http.StatusCreated,
 		},
+		{
+			name: "should create ingredient with normalized allergens",
+			payload: `{
+				"name": "bread",
+				"price": 2.5,
+				"unit": "gr",
+				"allergens": ["Gluten", "sesame", "gluten"]
+			}`,
+			expected: `{
+				"id":1,
+				"name":"bread",
+				"unit":"gr",
+				"price":2.5,
+				"units_in_stock":0,
+				"allergens":["gluten", "sesame"],
+				"nutrition":{"energy_kcal": 0, "protein": 0, "fat": 0, "saturated_fat": 0, "carbohydrate": 0, "sugar": 0, "salt": 0, "fibre": 0},
+				"category_id": null,
+				"tags": [],
+				"created_at":"1970-01-01T00:00:12.345Z",
+				"last_modified":"1970-01-01T00:00:12.345Z"
+			}`,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:    "should return error at every empty allergen",
+			payload: `{"name": "validName", "price": 12.43, "unit": "gr", "allergens": [" ", "milk", ""]}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "allergen is invalid; allergen is invalid",
+				"errors": [
+					{"field": "allergens[0]", "message": "allergen is invalid"},
+					{"field": "allergens[2]", "message": "allergen is invalid"}
+				]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "should return error at every invalid nutrition value",
+			payload: `{"name": "validName", "price": 12.43, "unit": "gr", "nutrition": {"protein": -1, "fat": 1, "saturated_fat": 2}}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "nutrition is invalid; nutrition is invalid",
+				"errors": [
+					{"field": "nutrition.protein", "message": "nutrition is invalid"},
+					{"field": "nutrition.saturated_fat", "message": "nutrition is invalid"}
+				]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
 		{
 			name:    "should return error if unit is invalid",
 			payload: `{"name": "validName", "price": 12.43, "unit": "notAtGr"}`,
 			expected: `{
-				"error": {
-					"code":"INVALID_INPUT",
-					"message":"unit is invalid"
-				}
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "unit is invalid",
+				"errors": [{"field": "unit", "message": "unit is invalid"}]
 			}`,
 			statusCode: http.StatusBadRequest,
 		},
@@ -68,10 +129,12 @@ func TestHandleCreateIngredient(t *testing.T) {
 			name:    "should return error if name is invalid",
 			payload: `{"name": "", "price": 12.43, "unit": "gr"}`,
 			expected: `{
-				"error": {
-					"code":"INVALID_INPUT",
-					"message":"name is invalid"
-				}
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "name is invalid",
+				"errors": [{"field": "name", "message": "name is invalid"}]
 			}`,
 			statusCode: http.StatusBadRequest,
 		},
@@ -79,10 +142,11 @@ func TestHandleCreateIngredient(t *testing.T) {
 			name:    "should return error if payload is invalid json",
 			payload: "invalid payload",
 			expected: `{
-				"error": {
-					"code":"INVALID_JSON",
-					"message":"error unmarshalling request body"
-				}
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_JSON",
+				"detail": "error unmarshalling request body"
 			}`,
 			statusCode: http.StatusBadRequest,
 		},
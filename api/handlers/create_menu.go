@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"costly/core/usecases/menus"
+	"net/http"
+)
+
+func CreateMenuHandler(menuCreator menus.MenuCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		createMenuOpts := menus.CreateMenuOptions{}
+		if err := UnmarshallJSONBody(r, &createMenuOpts); err != nil {
+			RespondError(w, r, ErrBadJson)
+			return
+		}
+		menu, err := menuCreator.Create(r.Context(), createMenuOpts)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, http.StatusCreated, menu)
+	}
+}
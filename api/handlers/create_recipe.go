@@ -1,10 +1,7 @@
 package handlers
 
 import (
-	"costly/core/errs"
-	"costly/core/ports/logger"
 	"costly/core/usecases/recipes"
-	"errors"
 	"net/http"
 )
 
@@ -14,16 +11,12 @@ func CreateRecipeHandler(recipeCreator recipes.RecipeCreator) http.HandlerFunc {
 
 		createRecipeOptions := recipes.CreateRecipeOptions{}
 		if err := UnmarshallJSONBody(r, &createRecipeOptions); err != nil {
-			RespondJSON(w, http.StatusBadRequest, ErrBadJson)
+			RespondError(w, r, ErrBadJson)
 			return
 		}
 		recipe, err := recipeCreator.Create(r.Context(), createRecipeOptions)
-		if errors.Is(err, errs.ErrBadOpts) {
-			RespondJSON(w, http.StatusBadRequest, NewInvalidInputResponseError(err.Error()))
-			return
-		} else if err != nil {
-			logger.Error(r.Context(), err, "error creating recipe")
-			w.WriteHeader(http.StatusInternalServerError)
+		if err != nil {
+			RespondError(w, r, err)
 			return
 		}
 		RespondJSON(w, 201, recipe)
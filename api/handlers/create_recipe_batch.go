@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"costly/core/usecases/recipes"
+	"net/http"
+)
+
+func CreateRecipeBatchHandler(recipeBatcher recipes.RecipeBatcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		batchRequest := BatchRequest[recipes.CreateRecipeOptions]{}
+		if err := UnmarshallJSONBody(r, &batchRequest); err != nil {
+			RespondError(w, r, ErrBadJson)
+			return
+		}
+		results, err := recipeBatcher.CreateBatch(r.Context(), batchRequest.Mode, batchRequest.Items)
+		RespondBatch(w, r, batchRequest.Mode, http.StatusCreated, results, err)
+	}
+}
@@ -6,12 +6,21 @@ import (
 	"costly/api"
 	"costly/core/mocks"
 	"costly/core/model"
+	"costly/core/ports/backup"
 	"costly/core/ports/clock"
 	"costly/core/ports/database"
 	"costly/core/ports/logger"
+	"costly/core/ports/storage"
 	"costly/core/usecases"
+	"costly/core/usecases/archives"
+	"costly/core/usecases/attachments"
+	"costly/core/usecases/backups"
+	"costly/core/usecases/categories"
 	"costly/core/usecases/ingredients"
+	"costly/core/usecases/menus"
 	"costly/core/usecases/recipes"
+	"costly/core/usecases/scenarios"
+	"costly/core/usecases/search"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -19,8 +28,18 @@ import (
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// notFoundProblem is the body responded when an entity does not exist.
+const notFoundProblem = `{
+	"type": "about:blank",
+	"title": "Not Found",
+	"status": 404,
+	"code": "NOT_FOUND",
+	"detail": "entity not found"
+}`
+
 var dummyHandler = api.Middleware(func(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		h.ServeHTTP(w, r)
@@ -29,14 +48,22 @@ var dummyHandler = api.Middleware(func(h http.Handler) http.Handler {
 
 func makeRequest(t *testing.T, clock clock.Clock, prepare func(useCases *usecases.UseCases) error, req *http.Request) *httptest.ResponseRecorder {
 	logger, _ := logger.New("debug")
-	db, _ := database.NewFromDatasource(":memory:", logger)
+	db, err := database.NewFromDatasource(":memory:", logger)
+	require.NoError(t, err)
 	ingredientUseCases := ingredients.New(db, clock)
 	recipeUseCases := recipes.New(db, clock, logger, ingredientUseCases)
 	useCases := &usecases.UseCases{
 		Ingredients: ingredientUseCases,
 		Recipes:     recipeUseCases,
+		Attachments: attachments.New(db, clock, storage.New(t.TempDir())),
+		Categories:  categories.New(db, clock),
+		Menus:       menus.New(db, clock),
+		Scenarios:   scenarios.New(db, clock),
+		Search:      search.New(db),
+		Archives:    archives.New(db, clock),
+		Backups:     backups.New(backup.New(db, t.TempDir(), 7), clock, logger),
 	}
-	err := prepare(useCases)
+	err = prepare(useCases)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -77,6 +104,8 @@ func TestHandleCreateRecipe(t *testing.T) {
 			expected: `{
 				"id": 1,
 				"name": "recipe1",
+				"price": 0,
+				"portions": 1,
 				"ingredients": [
 					{
 						"id": 1,
@@ -87,11 +116,130 @@ func TestHandleCreateRecipe(t *testing.T) {
 						"units": 500
 					}
 				],
+				"steps": [],
+				"plating_notes": "",
+				"prep_minutes": 0,
+				"cook_minutes": 0,
+				"category_id": null,
+				"tags": [],
 				"created_at": "1970-01-01T00:00:12.345Z",
 				"last_modified": "1970-01-01T00:00:12.345Z"
 			}`,
 			statusCode: http.StatusCreated,
 		},
+		{
+			name: "should create recipe with its method",
+			payload: `{
+				"name": "recipe1",
+				"price": 12.5,
+				"portions": 2,
+				"ingredients": [
+					{
+						"id": 1,
+						"units": 5
+					}
+				],
+				"steps": ["chop the onion", " fry it "],
+				"plating_notes": "serve hot",
+				"prep_minutes": 10,
+				"cook_minutes": 25,
+				"category_id": 2,
+				"tags": [" Vegan ", "spicy", "vegan"]
+			}`,
+			expected: `{
+				"id": 1,
+				"name": "recipe1",
+				"price": 12.5,
+				"portions": 2,
+				"ingredients": [
+					{
+						"id": 1,
+						"units": 5
+					}
+				],
+				"steps": ["chop the onion", "fry it"],
+				"plating_notes": "serve hot",
+				"prep_minutes": 10,
+				"cook_minutes": 25,
+				"category_id": 2,
+				"tags": ["spicy", "vegan"],
+				"created_at": "1970-01-01T00:00:12.345Z",
+				"last_modified": "1970-01-01T00:00:12.345Z"
+			}`,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name: "should return error if a step is empty",
+			payload: `{
+				"name": "recipe1",
+				"ingredients": [{"id": 1, "units": 5}],
+				"steps": ["chop the onion", ""]
+			}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "steps can not be empty",
+				"errors": [{"field": "steps[1]", "message": "steps can not be empty"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should return every invalid field at once",
+			payload: `{
+				"name": "",
+				"ingredients": [{"id": 1, "units": 5}, {"id": 2, "units": 0}, {"id": 1, "units": 3}],
+				"price": -1
+			}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "name is invalid; units should be more than 0; ingredient is repeated in the recipe; price is invalid",
+				"errors": [
+					{"field": "name", "message": "name is invalid"},
+					{"field": "ingredients[1].units", "message": "units should be more than 0"},
+					{"field": "ingredients[2].id", "message": "ingredient is repeated in the recipe"},
+					{"field": "price", "message": "price is invalid"}
+				]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should return error if category does not exist",
+			payload: `{
+				"name": "recipe1",
+				"ingredients": [{"id": 1, "units": 5}],
+				"category_id": 123
+			}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "category does not exist",
+				"errors": [{"field": "category_id", "message": "category does not exist"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "should return error if an ingredient does not exist",
+			payload: `{
+				"name": "recipe1",
+				"ingredients": [{"id": 123, "units": 5}]
+			}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Unprocessable Entity",
+				"status": 422,
+				"code": "INVALID_REFERENCE",
+				"detail": "ingredient does not exist",
+				"errors": [{"field": "ingredients[0].id", "message": "ingredient does not exist"}]
+			}`,
+			statusCode: http.StatusUnprocessableEntity,
+		},
 		{
 			name: "should return error if name is invalid",
 			payload: `{
@@ -108,10 +256,12 @@ func TestHandleCreateRecipe(t *testing.T) {
 				]
 			}`,
 			expected: `{
-				"error": {
-					"code":"INVALID_INPUT",
-					"message":"name is invalid"
-				}
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "name is invalid",
+				"errors": [{"field": "name", "message": "name is invalid"}]
 			}`,
 			statusCode: http.StatusBadRequest,
 		},
@@ -121,10 +271,12 @@ func TestHandleCreateRecipe(t *testing.T) {
 				"name": "validName"
 			}`,
 			expected: `{
-				"error": {
-					"code":"INVALID_INPUT",
-					"message":"recipe must have at least one ingredient"
-				}
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "recipe must have at least one ingredient",
+				"errors": [{"field": "ingredients", "message": "recipe must have at least one ingredient"}]
 			}`,
 			statusCode: http.StatusBadRequest,
 		},
@@ -135,10 +287,12 @@ func TestHandleCreateRecipe(t *testing.T) {
 				"ingredients": []
 			}`,
 			expected: `{
-				"error": {
-					"code":"INVALID_INPUT",
-					"message":"recipe must have at least one ingredient"
-				}
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "recipe must have at least one ingredient",
+				"errors": [{"field": "ingredients", "message": "recipe must have at least one ingredient"}]
 			}`,
 			statusCode: http.StatusBadRequest,
 		},
@@ -146,10 +300,11 @@ func TestHandleCreateRecipe(t *testing.T) {
 			name:    "should return error if payload is invalid json",
 			payload: "invalid payload",
 			expected: `{
-				"error": {
-					"code":"INVALID_JSON",
-					"message":"error unmarshalling request body"
-				}
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_JSON",
+				"detail": "error unmarshalling request body"
 			}`,
 			statusCode: http.StatusBadRequest,
 		},
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"costly/core/usecases/attachments"
+	"net/http"
+	"strconv"
+)
+
+func DeleteRecipeAttachmentHandler(attachmentDeleter attachments.AttachmentDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipeID, err := strconv.ParseInt(r.PathValue("recipeID"), 10, 64)
+		if err != nil {
+			RespondError(w, r, ErrBadID)
+			return
+		}
+		attachmentID, err := strconv.ParseInt(r.PathValue("attachmentID"), 10, 64)
+		if err != nil {
+			RespondError(w, r, ErrBadID)
+			return
+		}
+		err = attachmentDeleter.Delete(r.Context(), recipeID, attachmentID)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -1,10 +1,7 @@
 package handlers
 
 import (
-	"costly/core/errs"
-	"costly/core/ports/logger"
 	"costly/core/usecases/ingredients"
-	"errors"
 	"net/http"
 	"strconv"
 )
@@ -14,24 +11,22 @@ func EditIngredientHandler(ingredientEditor ingredients.IngredientEditor) http.H
 		ingredientIDstr := r.PathValue("ingredientID")
 		ingredientID, err := strconv.ParseInt(ingredientIDstr, 10, 64)
 		if err != nil {
-			RespondJSON(w, http.StatusBadRequest, ErrBadID)
+			RespondError(w, r, ErrBadID)
+			return
+		}
+		version, err := parseIfMatch(r)
+		if err != nil {
+			RespondError(w, r, err)
 			return
 		}
 		editIngredientOpts := ingredients.CreateIngredientOptions{}
 		if err := UnmarshallJSONBody(r, &editIngredientOpts); err != nil {
-			RespondJSON(w, http.StatusBadRequest, ErrBadJson)
+			RespondError(w, r, ErrBadJson)
 			return
 		}
-		err = ingredientEditor.Update(r.Context(), int64(ingredientID), editIngredientOpts)
-		if errors.Is(err, errs.ErrBadOpts) {
-			RespondJSON(w, http.StatusBadRequest, NewInvalidInputResponseError(err.Error()))
-			return
-		} else if err == errs.ErrNotFound {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		} else if err != nil {
-			logger.Error(r.Context(), err, "error getting ingredient")
-			w.WriteHeader(http.StatusInternalServerError)
+		err = ingredientEditor.Update(r.Context(), int64(ingredientID), version, editIngredientOpts)
+		if err != nil {
+			RespondError(w, r, err)
 			return
 		}
 
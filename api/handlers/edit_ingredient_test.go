@@ -24,6 +24,7 @@ func TestHandleEditIngredient(t *testing.T) {
 	testCases := []struct {
 		name            string
 		ingredientIDstr string
+		ifMatch         string
 		payload         string
 		expected        string
 		statusCode      int
@@ -31,6 +32,7 @@ func TestHandleEditIngredient(t *testing.T) {
 		{
 			name:            "should edit ingredient if existent",
 			ingredientIDstr: "1",
+			ifMatch:         `"1"`,
 			payload: `{
 				"name": "green tea",
 				"unit": "gr",
@@ -42,89 +44,191 @@ func TestHandleEditIngredient(t *testing.T) {
 		{
 			name:            "should get error if editing unexistent ingredient",
 			ingredientIDstr: "123",
+			ifMatch:         `"1"`,
 			payload: `{
 				"name": "green tea",
 				"unit": "gr",
 				"price": 10.0
 			}`,
-			expected:   "",
+			expected:   notFoundProblem,
 			statusCode: http.StatusNotFound,
 		},
+		{
+			name:            "should edit ingredient whatever its version",
+			ingredientIDstr: "1",
+			ifMatch:         "*",
+			payload: `{
+				"name": "green tea",
+				"unit": "gr",
+				"price": 10.0
+			}`,
+			expected:   "",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:            "should get error if the version is not given",
+			ingredientIDstr: "1",
+			payload: `{
+				"name": "green tea",
+				"unit": "gr",
+				"price": 10.0
+			}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Precondition Required",
+				"status": 428,
+				"code": "PRECONDITION_REQUIRED",
+				"detail": "If-Match header with the ETag of the entity is required"
+			}`,
+			statusCode: http.StatusPreconditionRequired,
+		},
+		{
+			name:            "should get error if ingredient was edited since it was read",
+			ingredientIDstr: "1",
+			ifMatch:         `"2"`,
+			payload: `{
+				"name": "green tea",
+				"unit": "gr",
+				"price": 10.0
+			}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Precondition Failed",
+				"status": 412,
+				"code": "PRECONDITION_FAILED",
+				"detail": "ingredient has been modified since it was read"
+			}`,
+			statusCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:            "should get error if the version is not an ETag of the ingredient",
+			ingredientIDstr: "1",
+			ifMatch:         `W/"1"`,
+			payload: `{
+				"name": "green tea",
+				"unit": "gr",
+				"price": 10.0
+			}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Precondition Failed",
+				"status": 412,
+				"code": "PRECONDITION_FAILED",
+				"detail": "If-Match does not match the current version"
+			}`,
+			statusCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:            "should get error if name is taken by another ingredient",
+			ingredientIDstr: "1",
+			ifMatch:         `"1"`,
+			payload: `{
+				"name": "black tea",
+				"unit": "gr",
+				"price": 10.0
+			}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Conflict",
+				"status": 409,
+				"code": "CONFLICT",
+				"detail": "an ingredient with this name already exists",
+				"errors": [{"field": "name", "message": "an ingredient with this name already exists"}]
+			}`,
+			statusCode: http.StatusConflict,
+		},
 		{
 			name:            "should get error if name is valid and unit and price are not present",
 			ingredientIDstr: "1",
+			ifMatch:         `"1"`,
 			payload: `{
 				"name": "aValidName"
 			}`,
 			expected: `{
-				"error": {
-					"code":"INVALID_INPUT",
-					"message":"unit is invalid"
-				}
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "unit is invalid; price is invalid",
+				"errors": [
+					{"field": "unit", "message": "unit is invalid"},
+					{"field": "price", "message": "price is invalid"}
+				]
 			}`,
 			statusCode: http.StatusBadRequest,
 		},
 		{
 			name:            "should get error if name is valid and unit not valid",
 			ingredientIDstr: "1",
+			ifMatch:         `"1"`,
 			payload: `{
 				"name": "aValidName",
 				"unit": "invalidUnit",
 				"price": 12.32
 			}`,
 			expected: `{
-				"error": {
-					"code":"INVALID_INPUT",
-					"message":"unit is invalid"
-				}
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "unit is invalid",
+				"errors": [{"field": "unit", "message": "unit is invalid"}]
 			}`,
 			statusCode: http.StatusBadRequest,
 		},
 		{
 			name:            "should get error if name is empty",
 			ingredientIDstr: "1",
+			ifMatch:         `"1"`,
 			payload: `{
 				"name": "",
 				"unit": "gr",
 				"price": 123.0
 			}`,
 			expected: `{
-				"error": {
-					"code":"INVALID_INPUT",
-					"message":"name is invalid"
-				}
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "name is invalid",
+				"errors": [{"field": "name", "message": "name is invalid"}]
 			}`,
 			statusCode: http.StatusBadRequest,
 		},
 		{
 			name:            "should get error if name and name are valid, but price is 0",
 			ingredientIDstr: "1",
+			ifMatch:         `"1"`,
 			payload: `{
 				"name": "aValidNamE",
 				"unit": "gr",
 				"price": 0
 			}`,
 			expected: `{
-				"error": {
-					"code":"INVALID_INPUT",
-					"message":"price is invalid"
-				}
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "price is invalid",
+				"errors": [{"field": "price", "message": "price is invalid"}]
 			}`,
 			statusCode: http.StatusBadRequest,
 		},
 		{
 			name:            "should get error if bad request id",
 			ingredientIDstr: "badID",
+			ifMatch:         `"1"`,
 			payload: `{
 				"name": "green tea",
 				"unit": "gr",
 				"price": 10.0
 			}`,
 			expected: `{
-				"error": {
-					"code":"INVALID_INPUT",
-					"message":"id is invalid"
-				}
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "id is invalid"
 			}`,
 			statusCode: http.StatusBadRequest,
 		},
@@ -134,12 +238,20 @@ func TestHandleEditIngredient(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			req, err := http.NewRequest("PUT", "/ingredients/"+tc.ingredientIDstr, bytes.NewBufferString(tc.payload))
 			require.NoError(t, err)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
 			rr := makeRequest(t, clock, func(useCases *usecases.UseCases) error {
 				useCases.Ingredients.Create(context.Background(), ingredients.CreateIngredientOptions{
 					Name:  "ingredientName",
 					Price: 12.43,
 					Unit:  model.Gram,
 				})
+				useCases.Ingredients.Create(context.Background(), ingredients.CreateIngredientOptions{
+					Name:  "black tea",
+					Price: 8.20,
+					Unit:  model.Gram,
+				})
 				return nil
 			}, req)
 			assert.Equal(t, tc.statusCode, rr.Code)
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"costly/core/usecases/recipes"
+	"net/http"
+	"strconv"
+)
+
+func EditRecipeHandler(recipeEditor recipes.RecipeEditor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipeIDstr := r.PathValue("recipeID")
+		recipeID, err := strconv.ParseInt(recipeIDstr, 10, 64)
+		if err != nil {
+			RespondError(w, r, ErrBadID)
+			return
+		}
+		version, err := parseIfMatch(r)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		editRecipeOpts := recipes.CreateRecipeOptions{}
+		if err := UnmarshallJSONBody(r, &editRecipeOpts); err != nil {
+			RespondError(w, r, ErrBadJson)
+			return
+		}
+		err = recipeEditor.Update(r.Context(), recipeID, version, editRecipeOpts)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// setETag tells the version of the entity responded, for clients to send it
+// back in If-Match when they edit it.
+func setETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", `"`+strconv.Itoa(version)+`"`)
+}
+
+// parseIfMatch gets the version an edited entity is expected to have from the
+// If-Match header, or zero if any version matches. An entity tag that is not
+// one of ours can not match the current version.
+func parseIfMatch(r *http.Request) (int, error) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" {
+		return 0, ErrMissingIfMatch
+	}
+	if ifMatch == "*" {
+		return 0, nil
+	}
+	versionStr, ok := strings.CutPrefix(ifMatch, `"`)
+	if !ok {
+		return 0, ErrBadIfMatch
+	}
+	versionStr, ok = strings.CutSuffix(versionStr, `"`)
+	version, err := strconv.Atoi(versionStr)
+	if !ok || err != nil || version < 1 {
+		return 0, ErrBadIfMatch
+	}
+	return version, nil
+}
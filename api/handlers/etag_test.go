@@ -0,0 +1,94 @@
+package handlers_test
+
+import (
+	"bytes"
+	"costly/core/mocks"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleETags(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	clock.On("Now").Return(time.UnixMilli(12345).UTC())
+	sirloin := `{"name": "sirloin", "price": 12, "ingredients": [{"id": 1, "units": 300}]}`
+
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		ifMatch    string
+		payload    string
+		etag       string
+		statusCode int
+	}{
+		{
+			name:       "should tell the version of an ingredient",
+			method:     "GET",
+			path:       "/ingredients/2",
+			etag:       `"1"`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "should not change the version of an ingredient as its stock changes",
+			method:     "GET",
+			path:       "/ingredients/1",
+			etag:       `"1"`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "should tell the version of a recipe edited once",
+			method:     "GET",
+			path:       "/recipes/1",
+			etag:       `"2"`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "should not tell the version of a past revision",
+			method:     "GET",
+			path:       "/recipes/1?as_of=1970-01-01T00:00:13Z",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "should edit the recipe if it has the version expected",
+			method:     "PUT",
+			path:       "/recipes/1",
+			ifMatch:    `"2"`,
+			payload:    sirloin,
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "should not edit the recipe if it was edited meanwhile",
+			method:     "PUT",
+			path:       "/recipes/1",
+			ifMatch:    `"1"`,
+			payload:    sirloin,
+			statusCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:       "should not edit the recipe without a version",
+			method:     "PUT",
+			path:       "/recipes/1",
+			payload:    sirloin,
+			statusCode: http.StatusPreconditionRequired,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.payload))
+			require.NoError(t, err)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			rr := makeRequest(t, clock, prepareSpecExamples(t), req)
+
+			assert.Equal(t, tc.statusCode, rr.Code, rr.Body.String())
+			assert.Equal(t, tc.etag, rr.Header().Get("ETag"))
+		})
+	}
+}
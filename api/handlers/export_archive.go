@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"costly/core/usecases/archives"
+	"fmt"
+	"net/http"
+)
+
+// ExportArchiveHandler responds the whole database as an archive to download,
+// which can be restored with RestoreArchiveHandler.
+func ExportArchiveHandler(exporter archives.Exporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		archive, err := exporter.Export(r.Context())
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"costly-%s.json\"", archive.ExportedAt.Format("20060102-150405")))
+		RespondJSON(w, http.StatusOK, archive)
+	}
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"costly/core/usecases/backups"
+	"net/http"
+)
+
+func GetBackupsHandler(backupsFinder backups.BackupsFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		backups, err := backupsFinder.FindAll(r.Context())
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, http.StatusOK, backups)
+	}
+}
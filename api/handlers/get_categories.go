@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"costly/core/usecases/categories"
+	"net/http"
+)
+
+func GetCategoriesHandler(categoriesGetter categories.CategoriesFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		categories, err := categoriesGetter.FindAll(r.Context())
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, 200, categories)
+	}
+}
+
+func GetCategoryAggregatesHandler(categoryAggregator categories.CategoryAggregator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		aggregates, err := categoryAggregator.Aggregate(r.Context())
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, 200, aggregates)
+	}
+}
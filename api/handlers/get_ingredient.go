@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"costly/core/errs"
-	"costly/core/ports/logger"
 	"costly/core/usecases/ingredients"
 	"net/http"
 	"strconv"
@@ -13,17 +11,15 @@ func GetIngredientHandler(ingredientGetter ingredients.IngredientFinder) http.Ha
 		ingredientIDstr := r.PathValue("ingredientID")
 		ingredientID, err := strconv.ParseInt(ingredientIDstr, 10, 64)
 		if err != nil {
-			RespondJSON(w, http.StatusBadRequest, ErrBadID)
+			RespondError(w, r, ErrBadID)
+			return
 		}
 		ingredient, err := ingredientGetter.Find(r.Context(), ingredientID)
-		if err == errs.ErrNotFound {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		} else if err != nil {
-			logger.Error(r.Context(), err, "error getting ingredient")
-			w.WriteHeader(http.StatusInternalServerError)
+		if err != nil {
+			RespondError(w, r, err)
 			return
 		}
+		setETag(w, ingredient.Version)
 		RespondJSON(w, 200, ingredient)
 	}
 }
@@ -35,6 +35,10 @@ func TestHandleGetIngredient(t *testing.T) {
 				"unit":"gr",
 				"price":12.43,
 				"units_in_stock":0,
+				"allergens":[],
+				"nutrition":{"energy_kcal": 0, "protein": 0, "fat": 0, "saturated_fat": 0, "carbohydrate": 0, "sugar": 0, "salt": 0, "fibre": 0},
+				"category_id": null,
+				"tags": [],
 				"created_at":"1970-01-01T00:00:12.345Z",
 				"last_modified":"1970-01-01T00:00:12.345Z"
 			}`,
@@ -43,17 +47,18 @@ func TestHandleGetIngredient(t *testing.T) {
 		{
 			name:            "should get error if unexistent ingredient",
 			ingredientIDstr: "123",
-			expected:        "",
+			expected:        notFoundProblem,
 			statusCode:      http.StatusNotFound,
 		},
 		{
 			name:            "should get error if bad request id",
 			ingredientIDstr: "badID",
 			expected: `{
-				"error": {
-					"code":"INVALID_INPUT",
-					"message":"id is invalid"
-				}
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "id is invalid"
 			}`,
 			statusCode: http.StatusBadRequest,
 		},
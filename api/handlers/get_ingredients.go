@@ -1,19 +1,27 @@
 package handlers
 
 import (
-	"costly/core/ports/logger"
 	"costly/core/usecases/ingredients"
 	"net/http"
 )
 
 func GetIngredientsHandler(ingredientsGetter ingredients.IngredientsFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ingredients, err := ingredientsGetter.FindAll(r.Context())
+		categoryID, err := parseCategory(r)
 		if err != nil {
-			logger.Error(r.Context(), err, "error getting ingredients")
-			w.WriteHeader(http.StatusInternalServerError)
+			RespondError(w, r, ErrBadID)
 			return
 		}
-		RespondJSON(w, 200, ingredients)
+		ingredients, err := ingredientsGetter.FindAll(r.Context(), ingredients.FindAllOptions{
+			Name:       r.URL.Query().Get("name"),
+			CategoryID: categoryID,
+			Tags:       r.URL.Query()["tag"],
+			Page:       parsePage(r),
+		})
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondPage(w, r, ingredients)
 	}
 }
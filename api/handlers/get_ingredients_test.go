@@ -40,13 +40,17 @@ func TestHandleGetIngredients(t *testing.T) {
 					Unit:  model.Gram,
 				},
 			},
-			expected: `[
+			expected: `{"data": [
 				{
 					"id": 1,
 					"name": "ingr1",
 					"unit": "gr",
 					"price": 1.5,
 					"units_in_stock":0,
+					"allergens":[],
+					"nutrition":{"energy_kcal": 0, "protein": 0, "fat": 0, "saturated_fat": 0, "carbohydrate": 0, "sugar": 0, "salt": 0, "fibre": 0},
+					"category_id": null,
+					"tags": [],
 					"created_at": "1970-01-01T00:00:12.345Z",
 					"last_modified": "1970-01-01T00:00:12.345Z"
 				},
@@ -56,16 +60,20 @@ func TestHandleGetIngredients(t *testing.T) {
 					"unit": "gr",
 					"price": 2.5,
 					"units_in_stock":0,
+					"allergens":[],
+					"nutrition":{"energy_kcal": 0, "protein": 0, "fat": 0, "saturated_fat": 0, "carbohydrate": 0, "sugar": 0, "salt": 0, "fibre": 0},
+					"category_id": null,
+					"tags": [],
 					"created_at": "1970-01-01T00:00:12.345Z",
 					"last_modified": "1970-01-01T00:00:12.345Z"
 				}
-			]`,
+			], "next_cursor": null}`,
 			statusCode: http.StatusOK,
 		},
 		{
 			name:        "should get empty ingredients",
 			ingredients: []ingredients.CreateIngredientOptions{},
-			expected:    `[]`,
+			expected:    `{"data": [], "next_cursor": null}`,
 			statusCode:  http.StatusOK,
 		},
 	}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"costly/core/usecases/menus"
+	"net/http"
+	"strconv"
+)
+
+func GetMenusHandler(menusGetter menus.MenuFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		menus, err := menusGetter.FindAll(r.Context())
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, 200, menus)
+	}
+}
+
+func GetMenuHandler(menuGetter menus.MenuFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		menuID, err := strconv.ParseInt(r.PathValue("menuID"), 10, 64)
+		if err != nil {
+			RespondError(w, r, ErrBadID)
+			return
+		}
+		menu, err := menuGetter.Find(r.Context(), menuID)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, 200, menu)
+	}
+}
+
+func GetMenuCostingHandler(menuCoster menus.MenuCoster) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		menuID, err := strconv.ParseInt(r.PathValue("menuID"), 10, 64)
+		if err != nil {
+			RespondError(w, r, ErrBadID)
+			return
+		}
+		costing, err := menuCoster.Cost(r.Context(), menuID)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, 200, costing)
+	}
+}
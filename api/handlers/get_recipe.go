@@ -1,9 +1,7 @@
 package handlers
 
 import (
-	"costly/core/errs"
 	"costly/core/model"
-	"costly/core/ports/logger"
 	"costly/core/usecases/recipes"
 	"net/http"
 	"strconv"
@@ -12,6 +10,28 @@ import (
 type RecipeResponse struct {
 	model.RecipeView
 	Cost float64 `json:"cost"`
+	// FoodCostPercentage is zero for recipes without price.
+	FoodCostPercentage float64                 `json:"food_cost_percentage"`
+	Allergens          []model.Allergen        `json:"allergens"`
+	Nutrition          RecipeNutritionResponse `json:"nutrition"`
+}
+
+type RecipeNutritionResponse struct {
+	Total      model.Nutrition `json:"total"`
+	PerPortion model.Nutrition `json:"per_portion"`
+}
+
+func NewRecipeResponse(recipe model.RecipeView) RecipeResponse {
+	return RecipeResponse{
+		RecipeView:         recipe,
+		Cost:               recipe.Cost(),
+		FoodCostPercentage: recipe.FoodCostPercentage(),
+		Allergens:          recipe.Allergens(),
+		Nutrition: RecipeNutritionResponse{
+			Total:      recipe.Nutrition(),
+			PerPortion: recipe.PortionNutrition(),
+		},
+	}
 }
 
 func GetRecipeHandler(recipeGetter recipes.RecipeFinder) http.HandlerFunc {
@@ -19,20 +39,28 @@ func GetRecipeHandler(recipeGetter recipes.RecipeFinder) http.HandlerFunc {
 		recipeIDstr := r.PathValue("recipeID")
 		recipeID, err := strconv.ParseInt(recipeIDstr, 10, 64)
 		if err != nil {
-			RespondJSON(w, http.StatusBadRequest, ErrBadID)
+			RespondError(w, r, ErrBadID)
+			return
 		}
-		recipe, err := recipeGetter.Find(r.Context(), recipeID)
-		if err == errs.ErrNotFound {
-			w.WriteHeader(http.StatusNotFound)
+		asOf, err := parseAsOf(r)
+		if err != nil {
+			RespondError(w, r, ErrBadAsOf)
 			return
-		} else if err != nil {
-			logger.Error(r.Context(), err, "error getting recipe")
-			w.WriteHeader(http.StatusInternalServerError)
+		}
+		var recipe model.RecipeView
+		if asOf.IsZero() {
+			recipe, err = recipeGetter.Find(r.Context(), recipeID)
+		} else {
+			recipe, err = recipeGetter.FindAsOf(r.Context(), recipeID, asOf)
+		}
+		if err != nil {
+			RespondError(w, r, err)
 			return
 		}
-		RespondJSON(w, 200, RecipeResponse{
-			RecipeView: recipe,
-			Cost:       recipe.Cost(),
-		})
+		// a past revision of the recipe can not be edited
+		if asOf.IsZero() {
+			setETag(w, recipe.Version)
+		}
+		RespondJSON(w, 200, NewRecipeResponse(recipe))
 	}
 }
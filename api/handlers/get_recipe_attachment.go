@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"costly/core/usecases/attachments"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+func GetRecipeAttachmentHandler(attachmentOpener attachments.AttachmentOpener) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipeID, err := strconv.ParseInt(r.PathValue("recipeID"), 10, 64)
+		if err != nil {
+			RespondError(w, r, ErrBadID)
+			return
+		}
+		attachmentID, err := strconv.ParseInt(r.PathValue("attachmentID"), 10, 64)
+		if err != nil {
+			RespondError(w, r, ErrBadID)
+			return
+		}
+		attachment, content, err := attachmentOpener.Open(r.Context(), recipeID, attachmentID)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		defer content.Close()
+		w.Header().Set("Content-Type", attachment.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": attachment.FileName}))
+		w.WriteHeader(http.StatusOK)
+		io.Copy(w, content)
+	}
+}
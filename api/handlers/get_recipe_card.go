@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"bytes"
+	"costly/core/errs"
+	"costly/core/model"
+	"costly/core/ports/document"
+	"costly/core/usecases/recipes"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+var ErrBadCardFormat = errs.NewValidationError("format", "format should be html or pdf")
+
+// GetRecipeCardHandler responds the recipe as a printable costed card, as HTML
+// or as PDF when asked in the format query parameter.
+func GetRecipeCardHandler(recipeGetter recipes.RecipeFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipeIDstr := r.PathValue("recipeID")
+		recipeID, err := strconv.ParseInt(recipeIDstr, 10, 64)
+		if err != nil {
+			RespondError(w, r, ErrBadID)
+			return
+		}
+		format := r.URL.Query().Get("format")
+		if format != "" && format != "html" && format != "pdf" {
+			RespondError(w, r, ErrBadCardFormat)
+			return
+		}
+		recipe, err := recipeGetter.Find(r.Context(), recipeID)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		// the card is rendered before responding so that a failure can still be
+		// responded as an error
+		card := model.NewRecipeCard(recipe)
+		rendered := &bytes.Buffer{}
+		contentType := "text/html; charset=utf-8"
+		if format == "pdf" {
+			contentType = "application/pdf"
+			err = document.WriteRecipeCardPDF(rendered, card)
+		} else {
+			err = document.WriteRecipeCardHTML(rendered, card)
+		}
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		if format == "pdf" {
+			w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"recipe-%d.pdf\"", recipeID))
+		}
+		w.WriteHeader(http.StatusOK)
+		rendered.WriteTo(w)
+	}
+}
@@ -0,0 +1,72 @@
+package handlers_test
+
+import (
+	"costly/core/mocks"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleGetRecipeCard(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	testCases := []struct {
+		name        string
+		path        string
+		contentType string
+		contains    []string
+		statusCode  int
+	}{
+		{
+			name:        "should print the recipe card as html",
+			path:        "/recipes/1/card",
+			contentType: "text/html; charset=utf-8",
+			contains: []string{
+				"<h1>steak</h1>",
+				`<td>meat</td><td class="number">300 gr</td><td class="number">10.00 / kg</td><td class="number">3.00</td>`,
+				`Price · food cost 30%</td><td class="number">10.00</td>`,
+				"Allergens: none</p>",
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:        "should print the recipe card as pdf",
+			path:        "/recipes/1/card?format=pdf",
+			contentType: "application/pdf",
+			contains:    []string{"%PDF-1.4", "(steak) Tj", "(10.00 / kg) Tj", "(Total cost) Tj"},
+			statusCode:  http.StatusOK,
+		},
+		{
+			name:        "should return error if format is not supported",
+			path:        "/recipes/1/card?format=docx",
+			contentType: "application/problem+json",
+			contains:    []string{`"field":"format"`},
+			statusCode:  http.StatusBadRequest,
+		},
+		{
+			name:        "should return error if recipe does not exist",
+			path:        "/recipes/123/card?format=pdf",
+			contentType: "application/problem+json",
+			contains:    []string{`"code":"NOT_FOUND"`},
+			statusCode:  http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tc.path, nil)
+			require.NoError(t, err)
+			rr := makeRequest(t, clock, prepareClassifiedRecipes(t), req)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, tc.contentType, rr.Header().Get("Content-Type"))
+			for _, content := range tc.contains {
+				assert.Contains(t, rr.Body.String(), content)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"costly/core/model"
+	"costly/core/usecases/recipes"
+	"net/http"
+	"strconv"
+)
+
+func GetRecipeNutritionHandler(recipeGetter recipes.RecipeFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipeIDstr := r.PathValue("recipeID")
+		recipeID, err := strconv.ParseInt(recipeIDstr, 10, 64)
+		if err != nil {
+			RespondError(w, r, ErrBadID)
+			return
+		}
+		recipe, err := recipeGetter.Find(r.Context(), recipeID)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, 200, model.NewNutritionLabel(recipe))
+	}
+}
@@ -0,0 +1,120 @@
+package handlers_test
+
+import (
+	"context"
+	"costly/core/mocks"
+	"costly/core/model"
+	"costly/core/usecases"
+	"costly/core/usecases/ingredients"
+	"costly/core/usecases/recipes"
+	"net/http"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleGetRecipeNutrition(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	testCases := []struct {
+		name        string
+		recipeIDstr string
+		expected    string
+		statusCode  int
+	}{
+		{
+			name:        "should get nutrition label of recipe",
+			recipeIDstr: "1",
+			expected: `{
+				"recipe_id": 1,
+				"name": "shortbread",
+				"portions": 4,
+				"net_weight": 300,
+				"portion_weight": 75,
+				"per_100g": {
+					"energy_kj": 2015,
+					"energy_kcal": 482,
+					"protein": 7,
+					"fat": 28,
+					"saturated_fat": 17,
+					"carbohydrate": 51,
+					"sugar": 0,
+					"salt": 0.01,
+					"fibre": 1.8
+				},
+				"per_portion": {
+					"energy_kj": 1511,
+					"energy_kcal": 361,
+					"protein": 5.2,
+					"fat": 21,
+					"saturated_fat": 13,
+					"carbohydrate": 38,
+					"sugar": 0,
+					"salt": 0,
+					"fibre": 1.4
+				},
+				"allergens": ["gluten", "milk"]
+			}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:        "should get error if unexistent recipe",
+			recipeIDstr: "123",
+			expected:    notFoundProblem,
+			statusCode:  http.StatusNotFound,
+		},
+		{
+			name:        "should get error if bad request id",
+			recipeIDstr: "badID",
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "id is invalid"
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/recipes/"+tc.recipeIDstr+"/nutrition", nil)
+			require.NoError(t, err)
+			rr := makeRequest(t, clock, func(useCases *usecases.UseCases) error {
+				_, err := useCases.Ingredients.Create(context.Background(), ingredients.CreateIngredientOptions{
+					Name:      "flour",
+					Price:     0.002,
+					Unit:      model.Gram,
+					Allergens: []model.Allergen{model.Gluten},
+					Nutrition: model.Nutrition{Energy: 364, Protein: 10, Fat: 1, SaturatedFat: 0.2, Carbohydrate: 76, Sugar: 0.3, Salt: 0.01, Fibre: 2.7},
+				})
+				require.NoError(t, err)
+				_, err = useCases.Ingredients.Create(context.Background(), ingredients.CreateIngredientOptions{
+					Name:      "butter",
+					Price:     0.01,
+					Unit:      model.Gram,
+					Allergens: []model.Allergen{model.Milk},
+					Nutrition: model.Nutrition{Energy: 717, Protein: 0.9, Fat: 81, SaturatedFat: 51, Carbohydrate: 0.1, Sugar: 0.1, Salt: 0.02},
+				})
+				require.NoError(t, err)
+				_, err = useCases.Recipes.Create(context.Background(), recipes.CreateRecipeOptions{
+					Name:        "shortbread",
+					Portions:    4,
+					Ingredients: []model.RecipeIngredient{{ID: 1, Units: 200}, {ID: 2, Units: 100}},
+				})
+				require.NoError(t, err)
+				return nil
+			}, req)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			if tc.expected != rr.Body.String() {
+				assert.JSONEq(t, tc.expected, rr.Body.String(), "Response body differs")
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"costly/core/model"
+	"costly/core/usecases/recipes"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// GetRecipeScaledHandler resizes a recipe to the portions or the yield, in
+// grams, given in the query without modifying the stored recipe.
+func GetRecipeScaledHandler(recipeGetter recipes.RecipeFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipeIDstr := r.PathValue("recipeID")
+		recipeID, err := strconv.ParseInt(recipeIDstr, 10, 64)
+		if err != nil {
+			RespondError(w, r, ErrBadID)
+			return
+		}
+		portionsStr, yieldStr := r.URL.Query().Get("portions"), r.URL.Query().Get("yield")
+		if (portionsStr == "") == (yieldStr == "") {
+			RespondError(w, r, ErrBadScale)
+			return
+		}
+		var portions int
+		var yield float64
+		if portionsStr != "" {
+			if portions, err = strconv.Atoi(portionsStr); err != nil {
+				RespondError(w, r, ErrBadPortionsParam)
+				return
+			}
+		} else {
+			if yield, err = strconv.ParseFloat(yieldStr, 64); err != nil || math.IsNaN(yield) || math.IsInf(yield, 0) {
+				RespondError(w, r, ErrBadYieldParam)
+				return
+			}
+		}
+		recipe, err := recipeGetter.Find(r.Context(), recipeID)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		var scaled *model.ScaledRecipe
+		if portionsStr != "" {
+			scaled, err = recipe.ScaleToPortions(portions)
+		} else {
+			scaled, err = recipe.ScaleToWeight(yield)
+		}
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, 200, scaled)
+	}
+}
@@ -0,0 +1,190 @@
+package handlers_test
+
+import (
+	"context"
+	"costly/core/mocks"
+	"costly/core/model"
+	"costly/core/usecases"
+	"costly/core/usecases/ingredients"
+	"costly/core/usecases/recipes"
+	"net/http"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleGetRecipeScaled(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	testCases := []struct {
+		name        string
+		recipeIDstr string
+		query       string
+		expected    string
+		statusCode  int
+	}{
+		{
+			name:        "should scale recipe to the given portions",
+			recipeIDstr: "1",
+			query:       "portions=6",
+			expected: `{
+				"recipe_id": 1,
+				"name": "bread",
+				"factor": 3,
+				"portions": 6,
+				"weight": 1800,
+				"ingredients": [
+					{"id": 1, "name": "flour", "amount": 1.5, "unit": "kg", "cost": 3},
+					{"id": 2, "name": "butter", "amount": 300, "unit": "gr", "cost": 1.5}
+				],
+				"cost": 4.5
+			}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:        "should scale recipe to the given yield",
+			recipeIDstr: "1",
+			query:       "yield=300",
+			expected: `{
+				"recipe_id": 1,
+				"name": "bread",
+				"factor": 0.5,
+				"portions": 1,
+				"weight": 300,
+				"ingredients": [
+					{"id": 1, "name": "flour", "amount": 250, "unit": "gr", "cost": 0.5},
+					{"id": 2, "name": "butter", "amount": 50, "unit": "gr", "cost": 0.25}
+				],
+				"cost": 0.75
+			}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:        "should get error if portions are invalid",
+			recipeIDstr: "1",
+			query:       "portions=-2",
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "portions should be more than 0",
+				"errors": [{"field": "portions", "message": "portions should be more than 0"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "should get error if portions are not a number",
+			recipeIDstr: "1",
+			query:       "portions=abc",
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "portions should be a whole number",
+				"errors": [{"field": "portions", "message": "portions should be a whole number"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "should get error if yield is not a number",
+			recipeIDstr: "1",
+			query:       "yield=abc",
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "yield should be a number of grams",
+				"errors": [{"field": "yield", "message": "yield should be a number of grams"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "should get error if yield is NaN",
+			recipeIDstr: "1",
+			query:       "yield=NaN",
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "yield should be a number of grams",
+				"errors": [{"field": "yield", "message": "yield should be a number of grams"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "should get error if yield is infinite",
+			recipeIDstr: "1",
+			query:       "yield=Inf",
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "yield should be a number of grams",
+				"errors": [{"field": "yield", "message": "yield should be a number of grams"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "should get error if neither portions nor yield are given",
+			recipeIDstr: "1",
+			query:       "",
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "either portions or yield should be given"
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "should get error if unexistent recipe",
+			recipeIDstr: "123",
+			query:       "portions=2",
+			expected:    notFoundProblem,
+			statusCode:  http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/recipes/"+tc.recipeIDstr+"/scaled?"+tc.query, nil)
+			require.NoError(t, err)
+			rr := makeRequest(t, clock, func(useCases *usecases.UseCases) error {
+				_, err := useCases.Ingredients.Create(context.Background(), ingredients.CreateIngredientOptions{
+					Name:  "flour",
+					Price: 0.002,
+					Unit:  model.Gram,
+				})
+				require.NoError(t, err)
+				_, err = useCases.Ingredients.Create(context.Background(), ingredients.CreateIngredientOptions{
+					Name:  "butter",
+					Price: 0.005,
+					Unit:  model.Gram,
+				})
+				require.NoError(t, err)
+				_, err = useCases.Recipes.Create(context.Background(), recipes.CreateRecipeOptions{
+					Name:        "bread",
+					Portions:    2,
+					Ingredients: []model.RecipeIngredient{{ID: 1, Units: 500}, {ID: 2, Units: 100}},
+				})
+				require.NoError(t, err)
+				return nil
+			}, req)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			if tc.expected != rr.Body.String() {
+				assert.JSONEq(t, tc.expected, rr.Body.String(), "Response body differs")
+			}
+		})
+	}
+}
@@ -33,40 +33,76 @@ func TestHandleGetRecipe(t *testing.T) {
 			expected: `{
 				"id": 1,
 				"name": "recipe1",
+				"price": 0,
+				"portions": 1,
 				"ingredients": [
 					{
 						"id": 1,
 						"name": "ingr1",
+						"unit": "gr",
 						"price": 1.50,
-						"units": 1
+						"units": 1,
+						"allergens": ["milk"]
 					},
 					{
 						"id": 2,
 						"name": "ingr2",
+						"unit": "gr",
 						"price": 2.50,
-						"units": 2
+						"units": 2,
+						"allergens": ["gluten", "milk"]
 					}
 				],
+				"steps": [],
+				"plating_notes": "",
+				"prep_minutes": 0,
+				"cook_minutes": 0,
+				"category_id": null,
+				"tags": [],
+				"attachments": [],
 				"created_at": "1970-01-01T00:00:12.345Z",
 				"last_modified": "1970-01-01T00:00:12.345Z",
-				"cost": 6.5
+				"cost": 6.5,
+				"food_cost_percentage": 0,
+				"allergens": ["gluten", "milk"],
+				"nutrition": {"total": {"energy_kcal": 0, "protein": 0, "fat": 0, "saturated_fat": 0, "carbohydrate": 0, "sugar": 0, "salt": 0, "fibre": 0}, "per_portion": {"energy_kcal": 0, "protein": 0, "fat": 0, "saturated_fat": 0, "carbohydrate": 0, "sugar": 0, "salt": 0, "fibre": 0}}
 			}`,
 			statusCode: http.StatusOK,
 		},
 		{
 			name:        "should get error if unexistent recipe",
 			recipeIDstr: "123",
-			expected:    "",
+			expected:    notFoundProblem,
 			statusCode:  http.StatusNotFound,
 		},
+		{
+			name:        "should get error if recipe did not exist as of the given time",
+			recipeIDstr: "1?as_of=1970-01-01T00:00:00Z",
+			expected:    notFoundProblem,
+			statusCode:  http.StatusNotFound,
+		},
+		{
+			name:        "should get error if as of time is invalid",
+			recipeIDstr: "1?as_of=yesterday",
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "as_of should be an RFC 3339 timestamp",
+				"errors": [{"field": "as_of", "message": "as_of should be an RFC 3339 timestamp"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
 		{
 			name:        "should get error if bad request id",
 			recipeIDstr: "badID",
 			expected: `{
-				"error": {
-					"code":"INVALID_INPUT",
-					"message":"id is invalid"
-				}
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "id is invalid"
 			}`,
 			statusCode: http.StatusBadRequest,
 		},
@@ -78,15 +114,17 @@ func TestHandleGetRecipe(t *testing.T) {
 			require.NoError(t, err)
 			rr := makeRequest(t, clock, func(useCases *usecases.UseCases) error {
 				_, err := useCases.Ingredients.Create(context.Background(), ingredients.CreateIngredientOptions{
-					Name:  "ingr1",
-					Price: 1.50,
-					Unit:  model.Gram,
+					Name:      "ingr1",
+					Price:     1.50,
+					Unit:      model.Gram,
+					Allergens: []model.Allergen{model.Milk},
 				})
 				require.NoError(t, err)
 				_, err = useCases.Ingredients.Create(context.Background(), ingredients.CreateIngredientOptions{
-					Name:  "ingr2",
-					Price: 2.50,
-					Unit:  model.Gram,
+					Name:      "ingr2",
+					Price:     2.50,
+					Unit:      model.Gram,
+					Allergens: []model.Allergen{model.Gluten, model.Milk},
 				})
 				require.NoError(t, err)
 				_, err = useCases.Recipes.Create(context.Background(), recipes.CreateRecipeOptions{
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"costly/core/usecases/recipes"
+	"net/http"
+	"strconv"
+)
+
+func GetRecipeVersionsHandler(versionsGetter recipes.RecipeVersionsFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipeIDstr := r.PathValue("recipeID")
+		recipeID, err := strconv.ParseInt(recipeIDstr, 10, 64)
+		if err != nil {
+			RespondError(w, r, ErrBadID)
+			return
+		}
+		versions, err := versionsGetter.FindVersions(r.Context(), recipeID)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, 200, versions)
+	}
+}
+
+// GetRecipeVersionsDiffHandler compares the versions given by the from and to
+// query parameters.
+func GetRecipeVersionsDiffHandler(versionsGetter recipes.RecipeVersionsFinder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		recipeIDstr := r.PathValue("recipeID")
+		recipeID, err := strconv.ParseInt(recipeIDstr, 10, 64)
+		if err != nil {
+			RespondError(w, r, ErrBadID)
+			return
+		}
+		from, fromErr := strconv.Atoi(r.URL.Query().Get("from"))
+		to, toErr := strconv.Atoi(r.URL.Query().Get("to"))
+		if fromErr != nil || toErr != nil {
+			RespondError(w, r, ErrBadVersions)
+			return
+		}
+		diff, err := versionsGetter.DiffVersions(r.Context(), recipeID, from, to)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, 200, diff)
+	}
+}
@@ -1,26 +1,42 @@
 package handlers
 
 import (
-	"costly/core/ports/logger"
+	"costly/core/model"
 	"costly/core/usecases/recipes"
 	"net/http"
 )
 
 func GetRecipesHandler(recipesGetter recipes.RecipesFinder) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		recipes, err := recipesGetter.FindAll(r.Context())
+		asOf, err := parseAsOf(r)
 		if err != nil {
-			logger.Error(r.Context(), err, "error getting recipes")
-			w.WriteHeader(http.StatusInternalServerError)
+			RespondError(w, r, ErrBadAsOf)
 			return
 		}
-		recipeResponses := []RecipeResponse{}
-		for _, recipe := range recipes {
-			recipeResponses = append(recipeResponses, RecipeResponse{
-				RecipeView: recipe,
-				Cost:       recipe.Cost(),
-			})
+		categoryID, err := parseCategory(r)
+		if err != nil {
+			RespondError(w, r, ErrBadID)
+			return
+		}
+		findAllOpts := recipes.FindAllOptions{
+			Name:       r.URL.Query().Get("name"),
+			CategoryID: categoryID,
+			Tags:       r.URL.Query()["tag"],
+			AsOf:       asOf,
+			Page:       parsePage(r),
+		}
+		for _, allergen := range r.URL.Query()["excludes_allergen"] {
+			findAllOpts.ExcludedAllergens = append(findAllOpts.ExcludedAllergens, model.Allergen(allergen))
+		}
+		page, err := recipesGetter.FindAll(r.Context(), findAllOpts)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		recipeResponses := model.Page[RecipeResponse]{Data: []RecipeResponse{}, NextCursor: page.NextCursor}
+		for _, recipe := range page.Data {
+			recipeResponses.Data = append(recipeResponses.Data, NewRecipeResponse(recipe))
 		}
-		RespondJSON(w, 200, recipeResponses)
+		RespondPage(w, r, recipeResponses)
 	}
 }
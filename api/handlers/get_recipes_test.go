@@ -2,12 +2,16 @@ package handlers_test
 
 import (
 	"context"
+	"costly/api/handlers"
 	"costly/core/mocks"
 	"costly/core/model"
 	"costly/core/usecases"
 	"costly/core/usecases/ingredients"
 	"costly/core/usecases/recipes"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -23,6 +27,7 @@ func TestHandleGetRecipes(t *testing.T) {
 
 	testCases := []struct {
 		name       string
+		query      string
 		recipes    []recipes.CreateRecipeOptions
 		expected   string
 		statusCode int
@@ -53,57 +58,140 @@ func TestHandleGetRecipes(t *testing.T) {
 					},
 				},
 			},
-			expected: `[
+			expected: `{"data": [
 				{
 					"id": 1,
 					"name": "recipe1",
+					"price": 0,
+					"portions": 1,
 					"ingredients": [
 						{
 							"id": 1,
 							"name": "ingr1",
+							"unit": "gr",
 							"price": 1.50,
-							"units": 1
+							"units": 1,
+							"allergens": []
 						},
 						{
 							"id": 2,
 							"name": "ingr2",
+							"unit": "gr",
 							"price": 2.50,
-							"units": 2
+							"units": 2,
+							"allergens": ["gluten"]
 						}
 					],
+					"steps": [],
+					"plating_notes": "",
+					"prep_minutes": 0,
+					"cook_minutes": 0,
+					"category_id": null,
+					"tags": [],
+					"attachments": [],
 					"created_at": "1970-01-01T00:00:12.345Z",
 					"last_modified": "1970-01-01T00:00:12.345Z",
-					"cost": 6.5
+					"cost": 6.5,
+					"food_cost_percentage": 0,
+					"allergens": ["gluten"],
+					"nutrition": {"total": {"energy_kcal": 0, "protein": 0, "fat": 0, "saturated_fat": 0, "carbohydrate": 0, "sugar": 0, "salt": 0, "fibre": 0}, "per_portion": {"energy_kcal": 0, "protein": 0, "fat": 0, "saturated_fat": 0, "carbohydrate": 0, "sugar": 0, "salt": 0, "fibre": 0}}
 				},
 				{
 					"id": 2,
 					"name": "recipe2",
+					"price": 0,
+					"portions": 1,
 					"ingredients": [
 						{
 							"id": 2,
 							"name": "ingr2",
+							"unit": "gr",
 							"price": 2.50,
-							"units": 3
+							"units": 3,
+							"allergens": ["gluten"]
 						}
 					],
+					"steps": [],
+					"plating_notes": "",
+					"prep_minutes": 0,
+					"cook_minutes": 0,
+					"category_id": null,
+					"tags": [],
+					"attachments": [],
 					"created_at": "1970-01-01T00:00:12.345Z",
 					"last_modified": "1970-01-01T00:00:12.345Z",
-					"cost": 7.5
+					"cost": 7.5,
+					"food_cost_percentage": 0,
+					"allergens": ["gluten"],
+					"nutrition": {"total": {"energy_kcal": 0, "protein": 0, "fat": 0, "saturated_fat": 0, "carbohydrate": 0, "sugar": 0, "salt": 0, "fibre": 0}, "per_portion": {"energy_kcal": 0, "protein": 0, "fat": 0, "saturated_fat": 0, "carbohydrate": 0, "sugar": 0, "salt": 0, "fibre": 0}}
 				}
-			]`,
+			], "next_cursor": null}`,
 			statusCode: http.StatusOK,
 		},
+		{
+			name:  "should exclude recipes containing an allergen",
+			query: "?excludes_allergen=Gluten",
+			recipes: []recipes.CreateRecipeOptions{
+				{
+					Name:        "recipe1",
+					Ingredients: []model.RecipeIngredient{{ID: 1, Units: 1}},
+				},
+				{
+					Name:        "recipe2",
+					Ingredients: []model.RecipeIngredient{{ID: 1, Units: 1}, {ID: 2, Units: 3}},
+				},
+			},
+			expected: `{"data": [
+				{
+					"id": 1,
+					"name": "recipe1",
+					"price": 0,
+					"portions": 1,
+					"ingredients": [
+						{
+							"id": 1,
+							"name": "ingr1",
+							"unit": "gr",
+							"price": 1.50,
+							"units": 1,
+							"allergens": []
+						}
+					],
+					"steps": [],
+					"plating_notes": "",
+					"prep_minutes": 0,
+					"cook_minutes": 0,
+					"category_id": null,
+					"tags": [],
+					"attachments": [],
+					"created_at": "1970-01-01T00:00:12.345Z",
+					"last_modified": "1970-01-01T00:00:12.345Z",
+					"cost": 1.5,
+					"food_cost_percentage": 0,
+					"allergens": [],
+					"nutrition": {"total": {"energy_kcal": 0, "protein": 0, "fat": 0, "saturated_fat": 0, "carbohydrate": 0, "sugar": 0, "salt": 0, "fibre": 0}, "per_portion": {"energy_kcal": 0, "protein": 0, "fat": 0, "saturated_fat": 0, "carbohydrate": 0, "sugar": 0, "salt": 0, "fibre": 0}}
+				}
+			], "next_cursor": null}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "should get error if excluded allergen is empty",
+			query:      "?excludes_allergen=",
+			recipes:    []recipes.CreateRecipeOptions{},
+			expected:   `{"type": "about:blank", "title": "Bad Request", "status": 400, "code": "INVALID_INPUT", "detail": "allergen is invalid", "errors": [{"field": "allergens", "message": "allergen is invalid"}]}`,
+			statusCode: http.StatusBadRequest,
+		},
 		{
 			name:       "should get empty ingredients",
 			recipes:    []recipes.CreateRecipeOptions{},
-			expected:   `[]`,
+			expected:   `{"data": [], "next_cursor": null}`,
 			statusCode: http.StatusOK,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			req, err := http.NewRequest("GET", "/recipes", nil)
+			req, err := http.NewRequest("GET", "/recipes"+tc.query, nil)
 			require.NoError(t, err)
 			rr := makeRequest(t, clock, func(useCases *usecases.UseCases) error {
 				useCases.Ingredients.Create(context.Background(), ingredients.CreateIngredientOptions{
@@ -112,9 +200,10 @@ func TestHandleGetRecipes(t *testing.T) {
 					Unit:  model.Gram,
 				})
 				useCases.Ingredients.Create(context.Background(), ingredients.CreateIngredientOptions{
-					Name:  "ingr2",
-					Price: 2.50,
-					Unit:  model.Gram,
+					Name:      "ingr2",
+					Price:     2.50,
+					Unit:      model.Gram,
+					Allergens: []model.Allergen{model.Gluten},
 				})
 
 				for _, opts := range tc.recipes {
@@ -129,3 +218,92 @@ func TestHandleGetRecipes(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleGetRecipesPages(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	getNames := func(t *testing.T, path string) ([]string, *httptest.ResponseRecorder, model.Page[struct{ Name string }]) {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		rr := makeRequest(t, clock, prepareClassifiedRecipes(t), req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var page model.Page[struct{ Name string }]
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &page))
+		names := []string{}
+		for _, recipe := range page.Data {
+			names = append(names, recipe.Name)
+		}
+		return names, rr, page
+	}
+
+	t.Run("should page recipes sorted by cost following the next cursor", func(t *testing.T) {
+		names, rr, page := getNames(t, "/recipes?sort=cost&order=desc&limit=2")
+		assert.Equal(t, []string{"steak", "staff meal"}, names)
+		require.NotNil(t, page.NextCursor)
+		next := "/recipes?cursor=" + url.QueryEscape(*page.NextCursor) + "&limit=2&order=desc&sort=cost"
+		assert.Equal(t, "<"+next+`>; rel="next"`, rr.Header().Get("Link"))
+
+		names, rr, page = getNames(t, next)
+		assert.Equal(t, []string{"flan"}, names)
+		assert.Nil(t, page.NextCursor)
+		assert.Empty(t, rr.Header().Get("Link"))
+	})
+
+	t.Run("should search recipes and ingredients by name", func(t *testing.T) {
+		names, _, _ := getNames(t, "/recipes?name=ST&sort=name")
+		assert.Equal(t, []string{"staff meal", "steak"}, names)
+		names, _, _ = getNames(t, "/ingredients?name=ug&sort=price")
+		assert.Equal(t, []string{"sugar"}, names)
+	})
+
+	testCases := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{
+			name:     "should get error if sort field is invalid",
+			path:     "/recipes?sort=portions",
+			expected: "sort is invalid",
+		},
+		{
+			name:     "should get error if ingredients are sorted by cost",
+			path:     "/ingredients?sort=cost",
+			expected: "sort is invalid",
+		},
+		{
+			name:     "should get error if limit is invalid",
+			path:     "/recipes?limit=many",
+			expected: "limit should be between 1 and 100",
+		},
+		{
+			name:     "should get error if cursor is invalid",
+			path:     "/recipes?cursor=notACursor",
+			expected: "cursor is invalid",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tc.path, nil)
+			require.NoError(t, err)
+			rr := makeRequest(t, clock, prepareClassifiedRecipes(t), req)
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			var problem handlers.Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+			assert.Equal(t, "INVALID_INPUT", problem.Code)
+			assert.Equal(t, tc.expected, problem.Detail)
+		})
+	}
+
+	t.Run("should get error if cursor was given for another sort", func(t *testing.T) {
+		_, _, page := getNames(t, "/recipes?sort=cost&limit=1")
+		require.NotNil(t, page.NextCursor)
+		req, err := http.NewRequest("GET", "/recipes?sort=name&cursor="+url.QueryEscape(*page.NextCursor), nil)
+		require.NoError(t, err)
+		rr := makeRequest(t, clock, prepareClassifiedRecipes(t), req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"costly/core/errs"
+	"costly/core/usecases/batch"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+const maxImportSize = 20 << 20
+
+var ErrBadColumns = errs.NewValidationError("columns", "columns should be a JSON object mapping fields to headers")
+var ErrBadDryRun = errs.NewValidationError("dry_run", "dry_run should be true or false")
+
+// importOptionsOf reads the spreadsheet uploaded in the file field of a form,
+// along with the columns and dry_run fields. The file must be closed.
+func importOptionsOf(w http.ResponseWriter, r *http.Request) (batch.ImportOptions, func() error, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	file, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		return batch.ImportOptions{}, nil, ErrBadFile
+	}
+	var validation errs.ValidationErrors
+	importOpts := batch.ImportOptions{FileName: fileHeader.Filename, Content: file}
+	if columns := r.FormValue("columns"); columns != "" {
+		if err := json.Unmarshal([]byte(columns), &importOpts.Columns); err != nil {
+			validation.Add(ErrBadColumns)
+		}
+	}
+	if dryRun := r.FormValue("dry_run"); dryRun != "" {
+		if importOpts.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			validation.Add(ErrBadDryRun)
+		}
+	}
+	if err := validation.Err(); err != nil {
+		file.Close()
+		return batch.ImportOptions{}, nil, err
+	}
+	return importOpts, file.Close, nil
+}
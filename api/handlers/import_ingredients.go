@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"costly/core/usecases/ingredients"
+	"net/http"
+)
+
+func ImportIngredientsHandler(ingredientImporter ingredients.IngredientImporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		importOpts, closeFile, err := importOptionsOf(w, r)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		defer closeFile()
+		report, err := ingredientImporter.Import(r.Context(), importOpts)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, http.StatusOK, report)
+	}
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"costly/core/usecases/recipes"
+	"net/http"
+)
+
+func ImportRecipesHandler(recipeImporter recipes.RecipeImporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		importOpts, closeFile, err := importOptionsOf(w, r)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		defer closeFile()
+		report, err := recipeImporter.Import(r.Context(), importOpts)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, http.StatusOK, report)
+	}
+}
@@ -0,0 +1,154 @@
+package handlers_test
+
+import (
+	"bytes"
+	"costly/core/mocks"
+	"mime/multipart"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// multipartImport is a form uploading the spreadsheet along with the fields.
+func multipartImport(t *testing.T, fileName string, content string, fields map[string]string) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+	part, err := writer.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+const ingredientCatalog = "Producto;Precio;Cantidad;Alergenos\n" +
+	"Meat;12,50;1 kg;\n" +
+	"Flour;1,20;1 kg;gluten\n" +
+	"Milk;1;1 L;milk\n"
+
+const recipeBook = "recipe,ingredient,quantity,price,portions\n" +
+	"steak,meats,0.25 kg,11,\n" +
+	"bread,flour,500g,3,4\n" +
+	"bread,salt,10g,,\n"
+
+func TestHandleImport(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	testCases := []struct {
+		name       string
+		path       string
+		fileName   string
+		content    string
+		fields     map[string]string
+		expected   string
+		statusCode int
+	}{
+		{
+			name:     "should import an ingredient catalog with its own headers",
+			path:     "/ingredients/import",
+			fileName: "catalog.csv",
+			content:  ingredientCatalog,
+			fields:   map[string]string{"columns": `{"name": "Producto", "price": "Precio", "quantity": "Cantidad", "allergens": "Alergenos"}`},
+			expected: `{
+				"dry_run": false,
+				"created": 1,
+				"updated": 1,
+				"rejected": 1,
+				"rows": [
+					{"line": 2, "name": "Meat", "action": "updated", "id": 1},
+					{"line": 3, "name": "Flour", "action": "created", "id": 3},
+					{
+						"line": 4,
+						"name": "Milk",
+						"action": "rejected",
+						"errors": [{"line": 4, "field": "quantity", "message": "unit is invalid"}]
+					}
+				]
+			}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:     "should only report what would be imported in a dry run",
+			path:     "/recipes/import",
+			fileName: "book.csv",
+			content:  recipeBook,
+			fields:   map[string]string{"dry_run": "true"},
+			expected: `{
+				"dry_run": true,
+				"created": 0,
+				"updated": 1,
+				"rejected": 1,
+				"rows": [
+					{
+						"line": 2,
+						"name": "steak",
+						"action": "updated",
+						"id": 1,
+						"matches": [{"line": 2, "name": "meats", "ingredient_id": 1, "matched_name": "meat"}]
+					},
+					{
+						"line": 3,
+						"name": "bread",
+						"action": "rejected",
+						"errors": [
+							{"line": 3, "field": "ingredient", "message": "ingredient does not match any in the catalog"},
+							{"line": 4, "field": "ingredient", "message": "ingredient does not match any in the catalog"}
+						]
+					}
+				]
+			}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:     "should return error if a column is not in the file",
+			path:     "/ingredients/import",
+			fileName: "catalog.csv",
+			content:  ingredientCatalog,
+			fields:   map[string]string{"columns": `{"name": "Nombre"}`},
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "column is not in the file",
+				"errors": [{"field": "columns.name", "message": "column is not in the file"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:     "should return error if the file is not a spreadsheet",
+			path:     "/recipes/import",
+			fileName: "book.txt",
+			content:  recipeBook,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "file should be a CSV or XLSX spreadsheet",
+				"errors": [{"field": "file", "message": "file should be a CSV or XLSX spreadsheet"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, contentType := multipartImport(t, tc.fileName, tc.content, tc.fields)
+			req, err := http.NewRequest("POST", tc.path, body)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", contentType)
+			rr := makeRequest(t, clock, prepareClassifiedRecipes(t), req)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.JSONEq(t, tc.expected, rr.Body.String(), "Response body differs")
+		})
+	}
+}
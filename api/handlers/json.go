@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"costly/core/model"
 	"encoding/json"
 	"net/http"
 )
@@ -11,6 +12,17 @@ func RespondJSON(w http.ResponseWriter, status int, body interface{}) {
 	json.NewEncoder(w).Encode(body)
 }
 
+// RespondPage responds with a page of a list, linking to the next one if any
+// with the same query and the next cursor.
+func RespondPage[T any](w http.ResponseWriter, r *http.Request, page model.Page[T]) {
+	if page.NextCursor != nil {
+		query := r.URL.Query()
+		query.Set("cursor", *page.NextCursor)
+		w.Header().Set("Link", "<"+r.URL.Path+"?"+query.Encode()+">; rel=\"next\"")
+	}
+	RespondJSON(w, http.StatusOK, page)
+}
+
 func UnmarshallJSONBody(r *http.Request, v interface{}) error {
 	decoder := json.NewDecoder(r.Body)
 	decoder.DisallowUnknownFields()
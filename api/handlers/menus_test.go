@@ -0,0 +1,263 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"costly/core/mocks"
+	"costly/core/model"
+	"costly/core/usecases"
+	"costly/core/usecases/menus"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// prepareMenu makes a dine-in menu offering the steak alone and a lunch combo
+// of steak and flan.
+func prepareMenu(t *testing.T) func(useCases *usecases.UseCases) error {
+	return func(useCases *usecases.UseCases) error {
+		require.NoError(t, prepareClassifiedRecipes(t)(useCases))
+		_, err := useCases.Menus.Create(context.Background(), menus.CreateMenuOptions{
+			Name:    "lunch",
+			Channel: model.DineIn,
+			Items: []menus.MenuItemOptions{
+				{Price: 12, Recipes: []model.MenuItemRecipe{{ID: 1, Units: 1}}},
+				{Name: "set lunch", Price: 14, Recipes: []model.MenuItemRecipe{{ID: 1, Units: 1}, {ID: 2, Units: 1}}},
+			},
+		})
+		return err
+	}
+}
+
+func TestHandleCreateMenu(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	testCases := []struct {
+		name       string
+		payload    string
+		expected   string
+		statusCode int
+	}{
+		{
+			name: "should create menu if payload is valid",
+			payload: `{
+				"name": " Summer delivery ",
+				"channel": "delivery",
+				"valid_from": "2024-06-01T00:00:00Z",
+				"valid_until": "2024-08-31T23:59:59Z",
+				"items": [
+					{"price": 6, "recipes": [{"id": 2, "units": 1}]},
+					{"name": "dessert for two", "price": 10, "recipes": [{"id": 2, "units": 2}]}
+				]
+			}`,
+			expected: `{
+				"id": 1,
+				"name": "Summer delivery",
+				"channel": "delivery",
+				"valid_from": "2024-06-01T00:00:00Z",
+				"valid_until": "2024-08-31T23:59:59Z",
+				"items": [
+					{"id": 1, "name": "", "price": 6, "recipes": [{"id": 2, "units": 1}]},
+					{"id": 2, "name": "dessert for two", "price": 10, "recipes": [{"id": 2, "units": 2}]}
+				],
+				"created_at": "1970-01-01T00:00:12.345Z",
+				"last_modified": "1970-01-01T00:00:12.345Z"
+			}`,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:    "should get error if channel is invalid",
+			payload: `{"name": "lunch", "channel": "takeaway", "items": [{"price": 6, "recipes": [{"id": 2, "units": 1}]}]}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "channel is invalid",
+				"errors": [{"field": "channel", "message": "channel is invalid"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "should get error if validity ends before it starts",
+			payload: `{"name": "lunch", "channel": "dine-in", "valid_from": "2024-06-01T00:00:00Z", "valid_until": "2024-05-01T00:00:00Z", "items": [{"price": 6, "recipes": [{"id": 2, "units": 1}]}]}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "valid_until should not be before valid_from",
+				"errors": [{"field": "valid_until", "message": "valid_until should not be before valid_from"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "should get error if combo has no name",
+			payload: `{"name": "lunch", "channel": "dine-in", "items": [{"price": 14, "recipes": [{"id": 1, "units": 1}, {"id": 2, "units": 1}]}]}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "name is invalid",
+				"errors": [{"field": "items[0].name", "message": "name is invalid"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "should report every invalid field of the menu and its items",
+			payload: `{"name": "lunch", "channel": "takeaway", "items": [{"price": 6, "recipes": [{"id": 2, "units": 1}]}, {"price": 0, "recipes": [{"id": 2, "units": 0}]}]}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "units should be more than 0; price is invalid; channel is invalid",
+				"errors": [
+					{"field": "items[1].recipes[0].units", "message": "units should be more than 0"},
+					{"field": "items[1].price", "message": "price is invalid"},
+					{"field": "channel", "message": "channel is invalid"}
+				]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "should get error if recipe does not exist",
+			payload: `{"name": "lunch", "channel": "dine-in", "items": [{"price": 6, "recipes": [{"id": 123, "units": 1}]}]}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "recipe does not exist",
+				"errors": [{"field": "recipes", "message": "recipe does not exist"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/menus", bytes.NewBufferString(tc.payload))
+			require.NoError(t, err)
+			rr := makeRequest(t, clock, prepareClassifiedRecipes(t), req)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			if tc.expected != rr.Body.String() {
+				assert.JSONEq(t, tc.expected, rr.Body.String(), "Response body differs")
+			}
+		})
+	}
+}
+
+func TestHandleGetMenu(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	testCases := []struct {
+		name       string
+		path       string
+		expected   string
+		statusCode int
+	}{
+		{
+			name: "should get menu with its items",
+			path: "/menus/1",
+			expected: `{
+				"id": 1,
+				"name": "lunch",
+				"channel": "dine-in",
+				"valid_from": null,
+				"valid_until": null,
+				"items": [
+					{"id": 1, "name": "", "price": 12, "recipes": [{"id": 1, "units": 1}]},
+					{"id": 2, "name": "set lunch", "price": 14, "recipes": [{"id": 1, "units": 1}, {"id": 2, "units": 1}]}
+				],
+				"created_at": "1970-01-01T00:00:12.345Z",
+				"last_modified": "1970-01-01T00:00:12.345Z"
+			}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "should get error if unexistent menu",
+			path:       "/menus/123",
+			expected:   notFoundProblem,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "should get error if unexistent menu costing",
+			path:       "/menus/123/costing",
+			expected:   notFoundProblem,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "should get error if bad menu id",
+			path: "/menus/badID",
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "id is invalid"
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tc.path, nil)
+			require.NoError(t, err)
+			rr := makeRequest(t, clock, prepareMenu(t), req)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			if tc.expected != rr.Body.String() {
+				assert.JSONEq(t, tc.expected, rr.Body.String(), "Response body differs")
+			}
+		})
+	}
+
+	t.Run("should list menus", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/menus", nil)
+		require.NoError(t, err)
+		rr := makeRequest(t, clock, prepareMenu(t), req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var menus []model.Menu
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &menus))
+		require.Len(t, menus, 1)
+		assert.Len(t, menus[0].Items, 2)
+	})
+}
+
+func TestHandleGetMenuCosting(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	req, err := http.NewRequest("GET", "/menus/1/costing", nil)
+	require.NoError(t, err)
+	rr := makeRequest(t, clock, prepareMenu(t), req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var costing model.MenuCosting
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &costing))
+	require.Len(t, costing.Items, 2)
+	steak, combo := costing.Items[0], costing.Items[1]
+	assert.Equal(t, "steak", steak.Name)
+	assert.False(t, steak.Combo)
+	assert.InDelta(t, 3.0, steak.Cost, 0.0001)
+	assert.InDelta(t, 25.0, steak.FoodCostPercentage, 0.0001)
+	assert.InDelta(t, 9.0, steak.Margin, 0.0001)
+	assert.Equal(t, "set lunch", combo.Name)
+	assert.True(t, combo.Combo)
+	assert.InDelta(t, 3.4, combo.Cost, 0.0001)
+	assert.InDelta(t, 10.6, combo.Margin, 0.0001)
+	assert.InDelta(t, 26.0, costing.Price, 0.0001)
+	assert.InDelta(t, 6.4, costing.Cost, 0.0001)
+	assert.InDelta(t, 6.4/26*100, costing.FoodCostPercentage, 0.0001)
+}
@@ -0,0 +1,365 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"costly/api"
+	"costly/core/mocks"
+	"costly/core/model"
+	"costly/core/usecases"
+	"costly/core/usecases/attachments"
+	"costly/core/usecases/recipes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openAPISpec is the document served by the API, decoded into plain maps so
+// that its schemas can be followed while validating responses.
+type openAPISpec map[string]any
+
+func loadOpenAPISpec(t *testing.T) openAPISpec {
+	spec := openAPISpec{}
+	require.NoError(t, json.Unmarshal(api.OpenAPISpec, &spec))
+	return spec
+}
+
+// resolve follows the node when it is a reference to a component.
+func (spec openAPISpec) resolve(node map[string]any) map[string]any {
+	ref, ok := node["$ref"].(string)
+	if !ok {
+		return node
+	}
+	var resolved any = map[string]any(spec)
+	for _, key := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		resolved = resolved.(map[string]any)[key]
+	}
+	return spec.resolve(resolved.(map[string]any))
+}
+
+// operations lists the method and path of every operation in the spec.
+func (spec openAPISpec) operations() []string {
+	operations := []string{}
+	for path, item := range spec["paths"].(map[string]any) {
+		for method := range item.(map[string]any) {
+			if method != "parameters" {
+				operations = append(operations, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+	slices.Sort(operations)
+	return operations
+}
+
+func (spec openAPISpec) response(method string, path string, status int) (map[string]any, bool) {
+	item, ok := spec["paths"].(map[string]any)[path].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	operation, ok := item[strings.ToLower(method)].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	response, ok := operation["responses"].(map[string]any)[fmt.Sprint(status)].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	return spec.resolve(response), true
+}
+
+// validate checks the value against the schema. Objects are closed: every
+// property of a response must be documented.
+func (spec openAPISpec) validate(schema map[string]any, value any, at string) error {
+	schema = spec.resolve(schema)
+	if allOf, ok := schema["allOf"].([]any); ok {
+		merged := map[string]any{"type": "object", "properties": map[string]any{}, "required": []any{}}
+		for _, part := range allOf {
+			part := spec.resolve(part.(map[string]any))
+			for name, property := range part["properties"].(map[string]any) {
+				merged["properties"].(map[string]any)[name] = property
+			}
+			required, _ := part["required"].([]any)
+			merged["required"] = append(merged["required"].([]any), required...)
+		}
+		schema = merged
+	}
+	if value == nil {
+		if nullable, _ := schema["nullable"].(bool); nullable {
+			return nil
+		}
+		return fmt.Errorf("%s should not be null", at)
+	}
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s should be an object", at)
+		}
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				return fmt.Errorf("%s.%s is required", at, name)
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		for name, property := range object {
+			propertySchema, ok := properties[name].(map[string]any)
+			if !ok {
+				if additional, _ := schema["additionalProperties"].(bool); additional {
+					continue
+				}
+				return fmt.Errorf("%s.%s is not documented", at, name)
+			}
+			if err := spec.validate(propertySchema, property, at+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s should be an array", at)
+		}
+		for i, item := range array {
+			if err := spec.validate(schema["items"].(map[string]any), item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s should be a string", at)
+		}
+		if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, any(str)) {
+			return fmt.Errorf("%s should be one of %v", at, enum)
+		}
+		if schema["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				return fmt.Errorf("%s should be a date-time", at)
+			}
+		}
+	case "integer":
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			return fmt.Errorf("%s should be an integer", at)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s should be a number", at)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s should be a boolean", at)
+		}
+	}
+	return nil
+}
+
+// prepareSpecExamples makes a menu whose steak has been edited once and has an
+// attachment, so every route has something to respond with.
+func prepareSpecExamples(t *testing.T) func(useCases *usecases.UseCases) error {
+	return func(useCases *usecases.UseCases) error {
+		ctx := context.Background()
+		require.NoError(t, prepareMenu(t)(useCases))
+		_, err := useCases.Attachments.Create(ctx, 1, attachments.AttachmentOptions{
+			FileName:    "plating.png",
+			ContentType: "image/png",
+			Content:     bytes.NewReader(pngContent),
+		})
+		require.NoError(t, err)
+		return useCases.Recipes.Update(ctx, 1, 0, recipes.CreateRecipeOptions{
+			Name: "sirloin", Price: 11, CategoryID: int64Ptr(5),
+			Ingredients: []model.RecipeIngredient{{ID: 1, Units: 250}},
+		})
+	}
+}
+
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	spec := loadOpenAPISpec(t)
+	router := api.NewRouter(&usecases.UseCases{}, dummyHandler).(chi.Routes)
+	routes := []string{}
+	err := chi.Walk(router, func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		routes = append(routes, method+" "+route)
+		return nil
+	})
+	require.NoError(t, err)
+	slices.Sort(routes)
+	assert.Equal(t, routes, spec.operations())
+}
+
+func TestOpenAPISpecDescribesResponses(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+	spec := loadOpenAPISpec(t)
+
+	testCases := []struct {
+		method     string
+		route      string
+		path       string
+		payload    string
+		statusCode int
+	}{
+		{"GET", "/openapi.json", "/openapi.json", "", http.StatusOK},
+		{"GET", "/search", "/search?q=sirloin", "", http.StatusOK},
+		{"GET", "/search", "/search?q=", "", http.StatusBadRequest},
+		{"GET", "/ingredients", "/ingredients?limit=1", "", http.StatusOK},
+		{"GET", "/ingredients", "/ingredients?sort=cost", "", http.StatusBadRequest},
+		{"POST", "/ingredients", "/ingredients", `{"name": "salt", "unit": "gr", "price": 0.001, "tags": ["seasoning"]}`, http.StatusCreated},
+		{"POST", "/ingredients", "/ingredients", `{"name": "salt"`, http.StatusBadRequest},
+		{"POST", "/ingredients", "/ingredients", `{"name": "meat", "unit": "gr", "price": 0.02}`, http.StatusConflict},
+		{"POST", "/ingredients/batch", "/ingredients/batch", `{"items": [{"name": "salt", "unit": "gr", "price": 0.001}]}`, http.StatusCreated},
+		{"POST", "/ingredients/batch", "/ingredients/batch", `{"mode": "best_effort", "items": [{"name": "salt", "unit": "gr", "price": 0.001}, {"name": ""}]}`, http.StatusMultiStatus},
+		{"PUT", "/ingredients/batch", "/ingredients/batch", `{"items": [{"id": 1, "version": 1, "name": "beef", "unit": "gr", "price": 0.02}, {"id": 123, "version": 1, "name": "pork", "unit": "gr", "price": 0.01}]}`, http.StatusUnprocessableEntity},
+		{"PUT", "/ingredients/batch", "/ingredients/batch", `{"mode": "best_effort", "items": [{"id": 1, "version": 1, "name": "beef", "unit": "gr", "price": 0.02}, {"id": 2, "version": 5, "name": "brown sugar", "unit": "gr", "price": 0.003}]}`, http.StatusMultiStatus},
+		{"GET", "/ingredients/{ingredientID}", "/ingredients/1", "", http.StatusOK},
+		{"GET", "/ingredients/{ingredientID}", "/ingredients/123", "", http.StatusNotFound},
+		{"PUT", "/ingredients/{ingredientID}", "/ingredients/1", `{"name": "beef", "unit": "gr", "price": 0.02}`, http.StatusNoContent},
+		{"POST", "/ingredients/{ingredientID}/stock", "/ingredients/1/stock", `{"units": 1000, "price": 0.02}`, http.StatusCreated},
+		{"GET", "/categories", "/categories", "", http.StatusOK},
+		{"POST", "/categories", "/categories", `{"name": "tapas", "parent_id": 1}`, http.StatusCreated},
+		{"POST", "/categories", "/categories", `{"name": "starters"}`, http.StatusConflict},
+		{"GET", "/categories/aggregates", "/categories/aggregates", "", http.StatusOK},
+		{"GET", "/menus", "/menus", "", http.StatusOK},
+		{"POST", "/menus", "/menus", `{"name": "dinner", "channel": "delivery", "valid_from": "2024-01-01T00:00:00Z", "items": [{"price": 5, "recipes": [{"id": 2, "units": 1}]}]}`, http.StatusCreated},
+		{"POST", "/menus", "/menus", `{"name": "lunch", "channel": "delivery", "items": [{"price": 5, "recipes": [{"id": 2, "units": 1}]}]}`, http.StatusConflict},
+		{"GET", "/menus/{menuID}", "/menus/1", "", http.StatusOK},
+		{"GET", "/menus/{menuID}", "/menus/123", "", http.StatusNotFound},
+		{"GET", "/menus/{menuID}/costing", "/menus/1/costing", "", http.StatusOK},
+		{"POST", "/scenarios/price-shock", "/scenarios/price-shock", `{"changes": [{"id": 1, "percentage": 10}]}`, http.StatusOK},
+		{"GET", "/recipes", "/recipes?limit=1&sort=cost", "", http.StatusOK},
+		{"POST", "/recipes", "/recipes", `{"name": "burger", "price": 9, "ingredients": [{"id": 1, "units": 150}], "steps": ["grill"]}`, http.StatusCreated},
+		{"POST", "/recipes", "/recipes", `{"name": "flan", "ingredients": [{"id": 1, "units": 150}]}`, http.StatusConflict},
+		{"POST", "/recipes", "/recipes", `{"name": "burger", "ingredients": [{"id": 123, "units": 150}]}`, http.StatusUnprocessableEntity},
+		{"POST", "/recipes/batch", "/recipes/batch", `{"items": [{"name": "burger", "ingredients": [{"id": 1, "units": 150}]}]}`, http.StatusCreated},
+		{"PUT", "/recipes/batch", "/recipes/batch", `{"mode": "best_effort", "items": [{"id": 1, "version": 2, "name": "steak", "ingredients": [{"id": 1, "units": 300}]}]}`, http.StatusOK},
+		{"PUT", "/recipes/batch", "/recipes/batch", `{"mode": "sometimes", "items": []}`, http.StatusBadRequest},
+		{"GET", "/recipes/{recipeID}", "/recipes/1", "", http.StatusOK},
+		{"GET", "/recipes/{recipeID}", "/recipes/1?as_of=yesterday", "", http.StatusBadRequest},
+		{"PUT", "/recipes/{recipeID}", "/recipes/2", `{"name": "flan", "price": 4.5, "ingredients": [{"id": 2, "units": 200}]}`, http.StatusNoContent},
+		{"PUT", "/recipes/{recipeID}", "/recipes/2", `{"name": "sirloin", "ingredients": [{"id": 2, "units": 200}]}`, http.StatusConflict},
+		{"GET", "/recipes/{recipeID}/versions", "/recipes/1/versions", "", http.StatusOK},
+		{"GET", "/recipes/{recipeID}/versions/diff", "/recipes/1/versions/diff?from=1&to=2", "", http.StatusOK},
+		{"GET", "/recipes/{recipeID}/nutrition", "/recipes/1/nutrition", "", http.StatusOK},
+		{"GET", "/recipes/{recipeID}/scaled", "/recipes/1/scaled?portions=4", "", http.StatusOK},
+		{"GET", "/recipes/{recipeID}/card", "/recipes/1/card", "", http.StatusOK},
+		{"GET", "/recipes/{recipeID}/card", "/recipes/1/card?format=pdf", "", http.StatusOK},
+		{"GET", "/recipes/{recipeID}/card", "/recipes/1/card?format=docx", "", http.StatusBadRequest},
+		{"POST", "/recipes/{recipeID}/simulate", "/recipes/1/simulate", `{"substitutions": [{"id": 1, "substitute_id": 2}]}`, http.StatusOK},
+		{"POST", "/recipes/{recipeID}/sales", "/recipes/1/sales", `{"sold_units": 2}`, http.StatusCreated},
+		{"POST", "/recipes/{recipeID}/productions", "/recipes/1/productions", `{"ingredient_id": 2, "batches": 1, "units": 250}`, http.StatusCreated},
+		{"POST", "/recipes/{recipeID}/productions", "/recipes/1/productions", `{"ingredient_id": 2, "batches": 100, "units": 25000}`, http.StatusConflict},
+		{"GET", "/recipes/{recipeID}/attachments/{attachmentID}", "/recipes/1/attachments/1", "", http.StatusOK},
+		{"DELETE", "/recipes/{recipeID}/attachments/{attachmentID}", "/recipes/1/attachments/1", "", http.StatusNoContent},
+		{"GET", "/admin/export", "/admin/export", "", http.StatusOK},
+		{"POST", "/admin/import", "/admin/import", `{"version": 1}`, http.StatusConflict},
+		{"POST", "/admin/import", "/admin/import", `{"version": 3}`, http.StatusBadRequest},
+		{"GET", "/admin/backups", "/admin/backups", "", http.StatusOK},
+		{"POST", "/admin/backups", "/admin/backups", "", http.StatusCreated},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.payload))
+			require.NoError(t, err)
+			if tc.method == "PUT" {
+				req.Header.Set("If-Match", "*")
+			}
+			rr := makeRequest(t, clock, prepareSpecExamples(t), req)
+			require.Equal(t, tc.statusCode, rr.Code, rr.Body.String())
+			response, ok := spec.response(tc.method, tc.route, tc.statusCode)
+			require.True(t, ok, "response is not documented")
+			if rr.Header().Get("ETag") != "" {
+				assert.Contains(t, response["headers"], "ETag", "ETag header is not documented")
+			}
+			content, ok := response["content"].(map[string]any)
+			if !ok {
+				assert.Empty(t, rr.Body.String())
+				return
+			}
+			mediaType := strings.Split(rr.Header().Get("Content-Type"), ";")[0]
+			media, ok := content[mediaType].(map[string]any)
+			if !ok {
+				media, ok = content[strings.Split(mediaType, "/")[0]+"/*"].(map[string]any)
+			}
+			require.True(t, ok, "media type %s is not documented", mediaType)
+			if strings.HasSuffix(mediaType, "json") {
+				var body any
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+				assert.NoError(t, spec.validate(media["schema"].(map[string]any), body, "body"))
+			}
+		})
+	}
+
+	preconditionCases := []struct {
+		ifMatch    string
+		statusCode int
+	}{
+		{`"1"`, http.StatusPreconditionFailed},
+		{"", http.StatusPreconditionRequired},
+	}
+	for _, pc := range preconditionCases {
+		t.Run(fmt.Sprintf("PUT /recipes/1 with If-Match %s", pc.ifMatch), func(t *testing.T) {
+			req, err := http.NewRequest("PUT", "/recipes/1", bytes.NewBufferString(`{"name": "sirloin", "ingredients": [{"id": 1, "units": 300}]}`))
+			require.NoError(t, err)
+			req.Header.Set("If-Match", pc.ifMatch)
+			rr := makeRequest(t, clock, prepareSpecExamples(t), req)
+			require.Equal(t, pc.statusCode, rr.Code, rr.Body.String())
+			response, ok := spec.response("PUT", "/recipes/{recipeID}", pc.statusCode)
+			require.True(t, ok, "response is not documented")
+			var problem any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+			schema := response["content"].(map[string]any)["application/problem+json"].(map[string]any)["schema"].(map[string]any)
+			assert.NoError(t, spec.validate(schema, problem, "body"))
+		})
+	}
+
+	t.Run("POST /recipes/1/attachments", func(t *testing.T) {
+		body, contentType := multipartFile(t, "plating.png", pngContent)
+		req, err := http.NewRequest("POST", "/recipes/1/attachments", body)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+		rr := makeRequest(t, clock, prepareSpecExamples(t), req)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		response, ok := spec.response("POST", "/recipes/{recipeID}/attachments", http.StatusCreated)
+		require.True(t, ok, "response is not documented")
+		var attachment any
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &attachment))
+		schema := response["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+		assert.NoError(t, spec.validate(schema, attachment, "body"))
+	})
+
+	importCases := []struct {
+		route   string
+		content string
+		fields  map[string]string
+	}{
+		{"/ingredients/import", ingredientCatalog, map[string]string{"columns": `{"name": "Producto", "price": "Precio", "quantity": "Cantidad"}`}},
+		{"/recipes/import", recipeBook, map[string]string{"dry_run": "true"}},
+	}
+	for _, ic := range importCases {
+		t.Run("POST "+ic.route, func(t *testing.T) {
+			body, contentType := multipartImport(t, "import.csv", ic.content, ic.fields)
+			req, err := http.NewRequest("POST", ic.route, body)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", contentType)
+			rr := makeRequest(t, clock, prepareSpecExamples(t), req)
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+			response, ok := spec.response("POST", ic.route, http.StatusOK)
+			require.True(t, ok, "response is not documented")
+			var report any
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+			schema := response["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+			assert.NoError(t, spec.validate(schema, report, "body"))
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"costly/core/usecases/scenarios"
+	"net/http"
+)
+
+// PriceShockHandler simulates the impact of the ingredient price changes in the
+// body on every recipe and menu, weighted by the sales of the last days.
+func PriceShockHandler(priceShocker scenarios.PriceShocker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		priceShockOpts := scenarios.PriceShockOptions{}
+		if err := UnmarshallJSONBody(r, &priceShockOpts); err != nil {
+			RespondError(w, r, ErrBadJson)
+			return
+		}
+		shock, err := priceShocker.PriceShock(r.Context(), priceShockOpts)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, 200, shock)
+	}
+}
@@ -0,0 +1,117 @@
+package handlers_test
+
+import (
+	"bytes"
+	"costly/core/mocks"
+	"costly/core/model"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlePriceShock(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	t.Run("should get the impact on every recipe and menu weighted by sales", func(t *testing.T) {
+		payload := `{"changes": [{"id": 1, "percentage": 10}], "days": 7}`
+		req, err := http.NewRequest("POST", "/scenarios/price-shock", bytes.NewBufferString(payload))
+		require.NoError(t, err)
+		rr := makeRequest(t, clock, prepareMenu(t), req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var shock model.PriceShock
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &shock))
+		require.Len(t, shock.Recipes, 3)
+		steak, flan := shock.Recipes[0], shock.Recipes[1]
+		assert.InDelta(t, 3.3, steak.Simulated.Cost, 0.0001)
+		assert.InDelta(t, 33.0, steak.Simulated.FoodCostPercentage, 0.0001)
+		assert.InDelta(t, -0.3, steak.MarginDelta, 0.0001)
+		assert.Equal(t, 3, steak.SoldUnits)
+		assert.InDelta(t, 0.9, steak.WeightedCostDelta, 0.0001)
+		assert.InDelta(t, 0.0, flan.CostDelta, 0.0001)
+		assert.InDelta(t, 0.4, shock.CostDelta, 0.0001)
+		assert.Equal(t, 3, shock.SoldUnits)
+		assert.InDelta(t, -0.9, shock.WeightedMarginDelta, 0.0001)
+		require.Len(t, shock.Menus, 1)
+		assert.InDelta(t, 6.4, shock.Menus[0].CurrentCost, 0.0001)
+		assert.InDelta(t, 7.0, shock.Menus[0].SimulatedCost, 0.0001)
+		assert.InDelta(t, 7.0/26*100, shock.Menus[0].SimulatedFoodCostPercentage, 0.0001)
+	})
+
+	testCases := []struct {
+		name       string
+		payload    string
+		expected   string
+		statusCode int
+	}{
+		{
+			name:    "should get error if both price and percentage are given",
+			payload: `{"changes": [{"id": 1, "price": 0.02, "percentage": 10}]}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "either price or percentage should be given",
+				"errors": [{"field": "changes", "message": "either price or percentage should be given"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "should get error if price drops to zero",
+			payload: `{"changes": [{"id": 2, "percentage": -100}]}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "price is invalid",
+				"errors": [{"field": "price", "message": "price is invalid"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "should get error if ingredient does not exist",
+			payload: `{"changes": [{"id": 123, "price": 0.02}]}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "ingredient does not exist"
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:    "should get error if days are invalid",
+			payload: `{"changes": [], "days": 0}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "days should be more than 0",
+				"errors": [{"field": "days", "message": "days should be more than 0"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/scenarios/price-shock", bytes.NewBufferString(tc.payload))
+			require.NoError(t, err)
+			rr := makeRequest(t, clock, prepareMenu(t), req)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			if tc.expected != rr.Body.String() {
+				assert.JSONEq(t, tc.expected, rr.Body.String(), "Response body differs")
+			}
+		})
+	}
+}
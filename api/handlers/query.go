@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"costly/core/model"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// parseAsOf gets the moment given in the as_of query parameter as an RFC 3339
+// timestamp, or the zero time if not given.
+func parseAsOf(r *http.Request) (time.Time, error) {
+	asOfStr := r.URL.Query().Get("as_of")
+	if asOfStr == "" {
+		return time.Time{}, nil
+	}
+	asOf, err := time.Parse(time.RFC3339, asOfStr)
+	return asOf.UTC(), err
+}
+
+// parseCategory gets the category ID given in the category query parameter, or
+// nil if not given.
+func parseCategory(r *http.Request) (*int64, error) {
+	categoryStr := r.URL.Query().Get("category")
+	if categoryStr == "" {
+		return nil, nil
+	}
+	categoryID, err := strconv.ParseInt(categoryStr, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &categoryID, nil
+}
+
+// parsePage gets the page options given in the limit, cursor, sort and order
+// query parameters. They are validated by the use cases, so an unparseable
+// limit is passed on as an invalid one.
+func parsePage(r *http.Request) model.PageOptions {
+	query := r.URL.Query()
+	limit := 0
+	if limitStr := query.Get("limit"); limitStr != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitStr); err != nil {
+			limit = -1
+		}
+	}
+	return model.PageOptions{
+		Limit:  limit,
+		Cursor: query.Get("cursor"),
+		SortBy: model.SortField(query.Get("sort")),
+		Order:  model.Order(query.Get("order")),
+	}
+}
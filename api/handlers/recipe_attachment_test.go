@@ -0,0 +1,200 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"costly/core/mocks"
+	"costly/core/model"
+	"costly/core/usecases"
+	"costly/core/usecases/attachments"
+	"costly/core/usecases/ingredients"
+	"costly/core/usecases/recipes"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pngContent = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func multipartFile(t *testing.T, fileName string, content []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func prepareRecipeWithAttachment(t *testing.T) func(useCases *usecases.UseCases) error {
+	return func(useCases *usecases.UseCases) error {
+		_, err := useCases.Ingredients.Create(context.Background(), ingredients.CreateIngredientOptions{
+			Name:  "ingr1",
+			Price: 1.50,
+			Unit:  model.Gram,
+		})
+		require.NoError(t, err)
+		_, err = useCases.Recipes.Create(context.Background(), recipes.CreateRecipeOptions{
+			Name:        "recipe1",
+			Ingredients: []model.RecipeIngredient{{ID: 1, Units: 1}},
+		})
+		require.NoError(t, err)
+		_, err = useCases.Attachments.Create(context.Background(), 1, attachments.AttachmentOptions{
+			FileName:    "plating.png",
+			ContentType: "image/png",
+			Content:     bytes.NewReader(pngContent),
+		})
+		require.NoError(t, err)
+		return nil
+	}
+}
+
+func TestHandleAddRecipeAttachment(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	testCases := []struct {
+		name        string
+		recipeIDstr string
+		fileName    string
+		content     []byte
+		expected    string
+		statusCode  int
+	}{
+		{
+			name:        "should add image attachment",
+			recipeIDstr: "1",
+			fileName:    "dish.png",
+			content:     pngContent,
+			expected: `{
+				"id": 2,
+				"recipe_id": 1,
+				"file_name": "dish.png",
+				"content_type": "image/png",
+				"size": 16,
+				"created_at": "1970-01-01T00:00:12.345Z"
+			}`,
+			statusCode: http.StatusCreated,
+		},
+		{
+			name:        "should get error if attachment is not an image",
+			recipeIDstr: "1",
+			fileName:    "notes.txt",
+			content:     []byte("some notes"),
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "attachments must be images",
+				"errors": [{"field": "file", "message": "attachments must be images"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "should get error if unexistent recipe",
+			recipeIDstr: "123",
+			fileName:    "dish.png",
+			content:     pngContent,
+			expected:    notFoundProblem,
+			statusCode:  http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, contentType := multipartFile(t, tc.fileName, tc.content)
+			req, err := http.NewRequest("POST", "/recipes/"+tc.recipeIDstr+"/attachments", body)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", contentType)
+			rr := makeRequest(t, clock, prepareRecipeWithAttachment(t), req)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			if tc.expected != rr.Body.String() {
+				assert.JSONEq(t, tc.expected, rr.Body.String(), "Response body differs")
+			}
+		})
+	}
+
+	t.Run("should get error if file is missing", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/recipes/1/attachments", strings.NewReader("{}"))
+		require.NoError(t, err)
+		rr := makeRequest(t, clock, prepareRecipeWithAttachment(t), req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+}
+
+func TestHandleGetRecipeAttachment(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	t.Run("should get attachment content", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/recipes/1/attachments/1", nil)
+		require.NoError(t, err)
+		rr := makeRequest(t, clock, prepareRecipeWithAttachment(t), req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+		assert.Equal(t, `inline; filename=plating.png`, rr.Header().Get("Content-Disposition"))
+		assert.Equal(t, pngContent, rr.Body.Bytes())
+	})
+
+	t.Run("should list attachments in recipe", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/recipes/1", nil)
+		require.NoError(t, err)
+		rr := makeRequest(t, clock, prepareRecipeWithAttachment(t), req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), `"attachments":[{"id":1,"recipe_id":1,"file_name":"plating.png","content_type":"image/png","size":16,"created_at":"1970-01-01T00:00:12.345Z"}]`)
+	})
+
+	t.Run("should get error if attachment belongs to another recipe", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/recipes/2/attachments/1", nil)
+		require.NoError(t, err)
+		rr := makeRequest(t, clock, prepareRecipeWithAttachment(t), req)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
+
+func TestHandleDeleteRecipeAttachment(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	testCases := []struct {
+		name            string
+		attachmentIDstr string
+		statusCode      int
+	}{
+		{
+			name:            "should delete attachment",
+			attachmentIDstr: "1",
+			statusCode:      http.StatusNoContent,
+		},
+		{
+			name:            "should get error if unexistent attachment",
+			attachmentIDstr: "123",
+			statusCode:      http.StatusNotFound,
+		},
+		{
+			name:            "should get error if bad attachment id",
+			attachmentIDstr: "badID",
+			statusCode:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("DELETE", "/recipes/1/attachments/"+tc.attachmentIDstr, nil)
+			require.NoError(t, err)
+			rr := makeRequest(t, clock, prepareRecipeWithAttachment(t), req)
+			assert.Equal(t, tc.statusCode, rr.Code)
+		})
+	}
+}
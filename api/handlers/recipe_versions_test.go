@@ -0,0 +1,190 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"costly/core/mocks"
+	"costly/core/model"
+	"costly/core/usecases"
+	"costly/core/usecases/ingredients"
+	"costly/core/usecases/recipes"
+	"net/http"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func prepareRecipeWithVersions(t *testing.T) func(useCases *usecases.UseCases) error {
+	return func(useCases *usecases.UseCases) error {
+		ctx := context.Background()
+		for _, name := range []string{"ingr1", "ingr2"} {
+			_, err := useCases.Ingredients.Create(ctx, ingredients.CreateIngredientOptions{Name: name, Price: 1.5, Unit: model.Gram})
+			require.NoError(t, err)
+		}
+		_, err := useCases.Recipes.Create(ctx, recipes.CreateRecipeOptions{
+			Name:        "recipe1",
+			Ingredients: []model.RecipeIngredient{{ID: 1, Units: 2}},
+		})
+		require.NoError(t, err)
+		require.NoError(t, useCases.Ingredients.Update(ctx, 1, 0, ingredients.CreateIngredientOptions{Name: "ingr1", Price: 2, Unit: model.Gram}))
+		return useCases.Recipes.Update(ctx, 1, 0, recipes.CreateRecipeOptions{
+			Name:        "recipe1",
+			Portions:    2,
+			Ingredients: []model.RecipeIngredient{{ID: 1, Units: 3}, {ID: 2, Units: 1}},
+		})
+	}
+}
+
+func TestHandleEditRecipe(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	testCases := []struct {
+		name        string
+		recipeIDstr string
+		payload     string
+		expected    string
+		statusCode  int
+	}{
+		{
+			name:        "should update recipe if payload is valid",
+			recipeIDstr: "1",
+			payload:     `{"name": "recipe2", "ingredients": [{"id": 2, "units": 5}]}`,
+			expected:    "",
+			statusCode:  http.StatusNoContent,
+		},
+		{
+			name:        "should get error if ingredients are empty",
+			recipeIDstr: "1",
+			payload:     `{"name": "recipe2", "ingredients": []}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "recipe must have at least one ingredient",
+				"errors": [{"field": "ingredients", "message": "recipe must have at least one ingredient"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "should get error if unexistent recipe",
+			recipeIDstr: "123",
+			payload:     `{"name": "recipe2", "ingredients": [{"id": 2, "units": 5}]}`,
+			expected:    notFoundProblem,
+			statusCode:  http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("PUT", "/recipes/"+tc.recipeIDstr, bytes.NewBufferString(tc.payload))
+			require.NoError(t, err)
+			req.Header.Set("If-Match", "*")
+			rr := makeRequest(t, clock, prepareRecipeWithVersions(t), req)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			if tc.expected != rr.Body.String() {
+				assert.JSONEq(t, tc.expected, rr.Body.String(), "Response body differs")
+			}
+		})
+	}
+}
+
+func TestHandleGetRecipeVersions(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	testCases := []struct {
+		name       string
+		path       string
+		expected   string
+		statusCode int
+	}{
+		{
+			name: "should list versions with their cost at the time",
+			path: "/recipes/1/versions",
+			expected: `[
+				{
+					"recipe_id": 1,
+					"version": 1,
+					"name": "recipe1",
+					"portions": 1,
+					"ingredients": [{"id": 1, "name": "ingr1", "price": 1.5, "units": 2}],
+					"cost": 3,
+					"created_at": "1970-01-01T00:00:12.345Z"
+				},
+				{
+					"recipe_id": 1,
+					"version": 2,
+					"name": "recipe1",
+					"portions": 2,
+					"ingredients": [
+						{"id": 1, "name": "ingr1", "price": 2, "units": 3},
+						{"id": 2, "name": "ingr2", "price": 1.5, "units": 1}
+					],
+					"cost": 7.5,
+					"created_at": "1970-01-01T00:00:12.345Z"
+				}
+			]`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "should get diff between two versions",
+			path: "/recipes/1/versions/diff?from=1&to=2",
+			expected: `{
+				"recipe_id": 1,
+				"from": 1,
+				"to": 2,
+				"portions": {"from": 1, "to": 2},
+				"cost": {"from": 3, "to": 7.5},
+				"added": [{"id": 2, "name": "ingr2", "price": 1.5, "units": 1}],
+				"removed": [],
+				"changed": [
+					{"id": 1, "name": "ingr1", "units": {"from": 2, "to": 3}, "price": {"from": 1.5, "to": 2}}
+				]
+			}`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "should get error if versions to compare are invalid",
+			path: "/recipes/1/versions/diff?from=1",
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "from and to versions are invalid"
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "should get error if unexistent version",
+			path:       "/recipes/1/versions/diff?from=1&to=3",
+			expected:   notFoundProblem,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "should get error if unexistent recipe",
+			path:       "/recipes/123/versions",
+			expected:   notFoundProblem,
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tc.path, nil)
+			require.NoError(t, err)
+			rr := makeRequest(t, clock, prepareRecipeWithVersions(t), req)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			if tc.expected != rr.Body.String() {
+				assert.JSONEq(t, tc.expected, rr.Body.String(), "Response body differs")
+			}
+		})
+	}
+}
@@ -1,37 +1,111 @@
 package handlers
 
-import "fmt"
+import (
+	"costly/core/errs"
+	"costly/core/ports/logger"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
 
-type ErrorResponse struct {
-	APIError *APIError `json:"error"`
+// Problem is an RFC 7807 problem details response. Code is a stable name of
+// the kind of problem, and Errors details the invalid fields of a request.
+type Problem struct {
+	Type   string       `json:"type"`
+	Title  string       `json:"title"`
+	Status int          `json:"status"`
+	Code   string       `json:"code"`
+	Detail string       `json:"detail,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
 }
 
-type APIError struct {
-	Code    string `json:"code"`
+type FieldError struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
 }
 
-func NewErrorResponse(code string, message string) *ErrorResponse {
-	return &ErrorResponse{
-		APIError: &APIError{
-			Code:    code,
-			Message: message,
-		},
+func NewProblem(status int, code string, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Code:   code,
+		Detail: detail,
 	}
 }
 
-func NewInvalidInputResponseError(message string) *ErrorResponse {
-	return &ErrorResponse{
-		APIError: &APIError{
-			Code:    "INVALID_INPUT",
-			Message: message,
-		},
+func (p *Problem) Error() string {
+	return p.Detail
+}
+
+// problemOf maps the error to the problem responded for it, and tells whether
+// it is a known one or an unexpected error whose details must not leak.
+func problemOf(err error) (*Problem, bool) {
+	var problem *Problem
+	var validationErrs errs.ValidationErrors
+	var validationErr *errs.ValidationError
+	var notFoundErr *errs.NotFoundError
+	var conflictErr *errs.ConflictError
+	var referenceErr *errs.ReferenceError
+	var staleErr *errs.StaleError
+	var stockErr *errs.InsufficientStockError
+	switch {
+	case errors.As(err, &problem):
+		return problem, true
+	case errors.As(err, &validationErrs):
+		problem = NewProblem(http.StatusBadRequest, validationErrs.Code(), validationErrs.Error())
+		for _, validationErr := range validationErrs {
+			if validationErr.Field != "" {
+				problem.Errors = append(problem.Errors, FieldError{Field: validationErr.Field, Message: validationErr.Message})
+			}
+		}
+		return problem, true
+	case errors.As(err, &validationErr):
+		problem = NewProblem(http.StatusBadRequest, validationErr.Code(), validationErr.Message)
+		if validationErr.Field != "" {
+			problem.Errors = []FieldError{{Field: validationErr.Field, Message: validationErr.Message}}
+		}
+		return problem, true
+	case errors.As(err, &notFoundErr):
+		return NewProblem(http.StatusNotFound, notFoundErr.Code(), notFoundErr.Error()), true
+	case errors.As(err, &conflictErr):
+		problem = NewProblem(http.StatusConflict, conflictErr.Code(), conflictErr.Message)
+		if conflictErr.Field != "" {
+			problem.Errors = []FieldError{{Field: conflictErr.Field, Message: conflictErr.Message}}
+		}
+		return problem, true
+	case errors.As(err, &referenceErr):
+		problem = NewProblem(http.StatusUnprocessableEntity, referenceErr.Code(), referenceErr.Message)
+		if referenceErr.Field != "" {
+			problem.Errors = []FieldError{{Field: referenceErr.Field, Message: referenceErr.Message}}
+		}
+		return problem, true
+	case errors.As(err, &staleErr):
+		return NewProblem(http.StatusPreconditionFailed, staleErr.Code(), staleErr.Error()), true
+	case errors.As(err, &stockErr):
+		return NewProblem(http.StatusConflict, stockErr.Code(), stockErr.Error()), true
 	}
+	return NewProblem(http.StatusInternalServerError, "INTERNAL", ""), false
 }
 
-func (re *ErrorResponse) Error() string {
-	return fmt.Sprintf("error code: %s, message: %s", re.APIError.Code, re.APIError.Message)
+// RespondError responds with the problem the error maps to. Unexpected errors
+// are logged and answered with an internal error.
+func RespondError(w http.ResponseWriter, r *http.Request, err error) {
+	problem, known := problemOf(err)
+	if !known {
+		logger.Error(r.Context(), err, "error handling "+r.Method+" "+r.URL.Path)
+	}
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
 }
 
-var ErrBadID = NewInvalidInputResponseError("id is invalid")
-var ErrBadJson = NewErrorResponse("INVALID_JSON", "error unmarshalling request body")
+var ErrBadID = errs.NewValidationError("", "id is invalid")
+var ErrBadJson = NewProblem(http.StatusBadRequest, "INVALID_JSON", "error unmarshalling request body")
+var ErrBadScale = errs.NewValidationError("", "either portions or yield should be given")
+var ErrBadPortionsParam = errs.NewValidationError("portions", "portions should be a whole number")
+var ErrBadYieldParam = errs.NewValidationError("yield", "yield should be a number of grams")
+var ErrBadVersions = errs.NewValidationError("", "from and to versions are invalid")
+var ErrBadAsOf = errs.NewValidationError("as_of", "as_of should be an RFC 3339 timestamp")
+var ErrMissingIfMatch = NewProblem(http.StatusPreconditionRequired, "PRECONDITION_REQUIRED", "If-Match header with the ETag of the entity is required")
+var ErrBadIfMatch = NewProblem(http.StatusPreconditionFailed, "PRECONDITION_FAILED", "If-Match does not match the current version")
@@ -0,0 +1,135 @@
+package handlers_test
+
+import (
+	"costly/api/handlers"
+	"costly/core/errs"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRespondError(t *testing.T) {
+	testCases := []struct {
+		name       string
+		err        error
+		statusCode int
+		expected   string
+	}{
+		{
+			name:       "should detail the invalid field",
+			err:        errs.ErrBadPrice,
+			statusCode: http.StatusBadRequest,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "price is invalid",
+				"errors": [{"field": "price", "message": "price is invalid"}]
+			}`,
+		},
+		{
+			name:       "should name the entity that was not found",
+			err:        fmt.Errorf("finding menu: %w", errs.NewNotFoundError("menu")),
+			statusCode: http.StatusNotFound,
+			expected: `{
+				"type": "about:blank",
+				"title": "Not Found",
+				"status": 404,
+				"code": "NOT_FOUND",
+				"detail": "menu not found"
+			}`,
+		},
+		{
+			name:       "should respond conflicts",
+			err:        errs.NewConflictError("category", "name", "a category with this name already exists"),
+			statusCode: http.StatusConflict,
+			expected: `{
+				"type": "about:blank",
+				"title": "Conflict",
+				"status": 409,
+				"code": "CONFLICT",
+				"detail": "a category with this name already exists",
+				"errors": [{"field": "name", "message": "a category with this name already exists"}]
+			}`,
+		},
+		{
+			name:       "should respond references to missing entities",
+			err:        errs.NewReferenceError("ingredient", "ingredient_id", "ingredient does not exist"),
+			statusCode: http.StatusUnprocessableEntity,
+			expected: `{
+				"type": "about:blank",
+				"title": "Unprocessable Entity",
+				"status": 422,
+				"code": "INVALID_REFERENCE",
+				"detail": "ingredient does not exist",
+				"errors": [{"field": "ingredient_id", "message": "ingredient does not exist"}]
+			}`,
+		},
+		{
+			name:       "should respond stale versions",
+			err:        errs.NewStaleError("recipe"),
+			statusCode: http.StatusPreconditionFailed,
+			expected: `{
+				"type": "about:blank",
+				"title": "Precondition Failed",
+				"status": 412,
+				"code": "PRECONDITION_FAILED",
+				"detail": "recipe has been modified since it was read"
+			}`,
+		},
+		{
+			name:       "should respond insufficient stock",
+			err:        &errs.InsufficientStockError{IngredientID: 3, Available: 10, Required: 25},
+			statusCode: http.StatusConflict,
+			expected: `{
+				"type": "about:blank",
+				"title": "Conflict",
+				"status": 409,
+				"code": "INSUFFICIENT_STOCK",
+				"detail": "ingredient 3 has 10 units in stock but 25 are required"
+			}`,
+		},
+		{
+			name:       "should hide unexpected errors",
+			err:        errors.New("database is locked"),
+			statusCode: http.StatusInternalServerError,
+			expected: `{
+				"type": "about:blank",
+				"title": "Internal Server Error",
+				"status": 500,
+				"code": "INTERNAL"
+			}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handlers.RespondError(rr, httptest.NewRequest("GET", "/", nil), tc.err)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.Equal(t, "application/problem+json", rr.Header().Get("Content-Type"))
+			assert.JSONEq(t, tc.expected, rr.Body.String())
+		})
+	}
+
+	t.Run("should match typed errors with their kind", func(t *testing.T) {
+		assert.ErrorIs(t, errs.ErrBadName, errs.ErrBadOpts)
+		assert.ErrorIs(t, errs.NewNotFoundError("recipe"), errs.ErrNotFound)
+		assert.ErrorIs(t, errs.NewConflictError("recipe", "name", "taken"), errs.ErrConflict)
+		assert.ErrorIs(t, errs.NewReferenceError("ingredient", "ingredient_id", "missing"), errs.ErrBadReference)
+		assert.ErrorIs(t, errs.NewStaleError("recipe"), errs.ErrStale)
+		assert.ErrorIs(t, &errs.InsufficientStockError{}, errs.ErrInsufficientStock)
+		var body map[string]any
+		rr := httptest.NewRecorder()
+		handlers.RespondError(rr, httptest.NewRequest("GET", "/", nil), handlers.ErrBadJson)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		assert.Equal(t, "INVALID_JSON", body["code"])
+	})
+}
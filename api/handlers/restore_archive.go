@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"costly/core/model"
+	"costly/core/usecases/archives"
+	"net/http"
+)
+
+func RestoreArchiveHandler(restorer archives.Restorer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		archive := model.Archive{}
+		if err := UnmarshallJSONBody(r, &archive); err != nil {
+			RespondError(w, r, ErrBadJson)
+			return
+		}
+		summary, err := restorer.Restore(r.Context(), archive)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, http.StatusCreated, summary)
+	}
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"costly/core/usecases/search"
+	"net/http"
+)
+
+func SearchHandler(searcher search.Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		results, err := searcher.Search(r.Context(), r.URL.Query().Get("q"))
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, 200, results)
+	}
+}
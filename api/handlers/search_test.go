@@ -0,0 +1,126 @@
+package handlers_test
+
+import (
+	"context"
+	"costly/core/mocks"
+	"costly/core/model"
+	"costly/core/usecases"
+	"costly/core/usecases/ingredients"
+	"costly/core/usecases/recipes"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSearch(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	prepare := func(useCases *usecases.UseCases) error {
+		ctx := context.Background()
+		_, err := useCases.Ingredients.Create(ctx, ingredients.CreateIngredientOptions{Name: "Jalapeño", Price: 0.01, Unit: model.Gram, Tags: []string{"spicy"}})
+		require.NoError(t, err)
+		_, err = useCases.Ingredients.Create(ctx, ingredients.CreateIngredientOptions{Name: "salt", Price: 0.001, Unit: model.Gram})
+		require.NoError(t, err)
+		_, err = useCases.Recipes.Create(ctx, recipes.CreateRecipeOptions{
+			Name:         "Nachos",
+			Ingredients:  []model.RecipeIngredient{{ID: 1, Units: 20}},
+			RecipeMethod: model.RecipeMethod{Steps: []string{"Slice the jalapeños thinly"}},
+			Tags:         []string{"vegetarian"},
+		})
+		require.NoError(t, err)
+		_, err = useCases.Recipes.Create(ctx, recipes.CreateRecipeOptions{
+			Name:        "Tomato dip",
+			Ingredients: []model.RecipeIngredient{{ID: 2, Units: 5}},
+		})
+		require.NoError(t, err)
+		// the index is kept in sync when recipes are edited
+		return useCases.Recipes.Update(ctx, 2, 0, recipes.CreateRecipeOptions{
+			Name:        "Green salsa",
+			Ingredients: []model.RecipeIngredient{{ID: 2, Units: 5}},
+		})
+	}
+
+	testCases := []struct {
+		name       string
+		query      string
+		expected   string
+		statusCode int
+	}{
+		{
+			name:  "should match names and steps ignoring accents",
+			query: "jalapeno",
+			expected: `[
+				{"kind": "ingredient", "id": 1, "name": "Jalapeño"},
+				{"kind": "recipe", "id": 1, "name": "Nachos"}
+			]`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "should match prefixes ignoring case",
+			query: "SAL",
+			expected: `[
+				{"kind": "ingredient", "id": 2, "name": "salt"},
+				{"kind": "recipe", "id": 2, "name": "Green salsa"}
+			]`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "should match tags",
+			query: "veget",
+			expected: `[
+				{"kind": "recipe", "id": 1, "name": "Nachos"}
+			]`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "should match all the words",
+			query: "green sal",
+			expected: `[
+				{"kind": "recipe", "id": 2, "name": "Green salsa"}
+			]`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "should take punctuation as word separators and not as search syntax",
+			query:      "salsa -green",
+			expected:   `[{"kind": "recipe", "id": 2, "name": "Green salsa"}]`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "should not match names edited out",
+			query:      "tomato",
+			expected:   `[]`,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "should get error if query has no words",
+			query: `"*"`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "search query should have at least one word",
+				"errors": [{"field": "q", "message": "search query should have at least one word"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", "/search?q="+url.QueryEscape(tc.query), nil)
+			require.NoError(t, err)
+			rr := makeRequest(t, clock, prepare, req)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			assert.JSONEq(t, tc.expected, rr.Body.String(), "Response body differs")
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"costly/core/model"
+	"costly/core/usecases/recipes"
+	"net/http"
+	"strconv"
+)
+
+// SimulateRecipeHandler compares the cost and margin of a recipe with the ones
+// it would have after the substitutions, quantities and prices in the body.
+func SimulateRecipeHandler(recipeSimulator recipes.RecipeSimulator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		recipeID, err := strconv.ParseInt(r.PathValue("recipeID"), 10, 64)
+		if err != nil {
+			RespondError(w, r, ErrBadID)
+			return
+		}
+		changes := model.RecipeChanges{}
+		if err := UnmarshallJSONBody(r, &changes); err != nil {
+			RespondError(w, r, ErrBadJson)
+			return
+		}
+		simulation, err := recipeSimulator.Simulate(r.Context(), recipeID, changes)
+		if err != nil {
+			RespondError(w, r, err)
+			return
+		}
+		RespondJSON(w, 200, simulation)
+	}
+}
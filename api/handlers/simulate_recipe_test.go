@@ -0,0 +1,164 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"costly/core/mocks"
+	"costly/core/model"
+	"costly/core/usecases"
+	"costly/core/usecases/ingredients"
+	"costly/core/usecases/recipes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSimulateRecipe(t *testing.T) {
+	clock := new(mocks.ClockMock)
+	now := time.UnixMilli(12345).UTC()
+	clock.On("Now").Return(now)
+
+	prepare := func(useCases *usecases.UseCases) error {
+		ctx := context.Background()
+		for _, opts := range []ingredients.CreateIngredientOptions{
+			{Name: "flour", Price: 0.002, Unit: model.Gram},
+			{Name: "butter", Price: 0.005, Unit: model.Gram},
+			{Name: "margarine", Price: 0.003, Unit: model.Gram},
+		} {
+			_, err := useCases.Ingredients.Create(ctx, opts)
+			require.NoError(t, err)
+		}
+		_, err := useCases.Recipes.Create(ctx, recipes.CreateRecipeOptions{
+			Name:        "bread",
+			Price:       5,
+			Ingredients: []model.RecipeIngredient{{ID: 1, Units: 500}, {ID: 2, Units: 100}},
+		})
+		return err
+	}
+
+	t.Run("should compare cost and margin with the current ones without persisting", func(t *testing.T) {
+		payload := `{
+			"substitutions": [{"id": 2, "substitute_id": 3}],
+			"quantities": [{"id": 1, "units": 400}],
+			"prices": [{"id": 3, "price": 0.002}]
+		}`
+		req, err := http.NewRequest("POST", "/recipes/1/simulate", bytes.NewBufferString(payload))
+		require.NoError(t, err)
+		rr := makeRequest(t, clock, prepare, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var simulation model.RecipeSimulation
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &simulation))
+		require.Len(t, simulation.Ingredients, 2)
+		assert.Equal(t, "margarine", simulation.Ingredients[1].Name)
+		assert.Equal(t, 100, simulation.Ingredients[1].Units)
+		assert.InDelta(t, 1.5, simulation.Current.Cost, 0.0001)
+		assert.InDelta(t, 3.5, simulation.Current.Margin, 0.0001)
+		assert.InDelta(t, 30.0, simulation.Current.FoodCostPercentage, 0.0001)
+		assert.InDelta(t, 1.0, simulation.Simulated.Cost, 0.0001)
+		assert.InDelta(t, 4.0, simulation.Simulated.Margin, 0.0001)
+		assert.InDelta(t, 20.0, simulation.Simulated.FoodCostPercentage, 0.0001)
+		assert.InDelta(t, -0.5, simulation.CostDelta, 0.0001)
+		assert.InDelta(t, 0.5, simulation.MarginDelta, 0.0001)
+	})
+
+	testCases := []struct {
+		name        string
+		recipeIDstr string
+		payload     string
+		expected    string
+		statusCode  int
+	}{
+		{
+			name:        "should get error if substituted ingredient is not in the recipe",
+			recipeIDstr: "1",
+			payload:     `{"substitutions": [{"id": 3, "substitute_id": 2}]}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "ingredient is not in the recipe"
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "should get error if substitute does not exist",
+			recipeIDstr: "1",
+			payload:     `{"substitutions": [{"id": 2, "substitute_id": 123}]}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "ingredient does not exist"
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "should get error if overridden price is invalid",
+			recipeIDstr: "1",
+			payload:     `{"prices": [{"id": 1, "price": -1}]}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "price is invalid",
+				"errors": [{"field": "price", "message": "price is invalid"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "should get error if the price of an ingredient is changed twice",
+			recipeIDstr: "1",
+			payload:     `{"prices": [{"id": 1, "price": 0.003}, {"id": 1, "price": 0.004}]}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "price of the ingredient is changed more than once",
+				"errors": [{"field": "prices[1].id", "message": "price of the ingredient is changed more than once"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "should get error if every ingredient is removed",
+			recipeIDstr: "1",
+			payload:     `{"quantities": [{"id": 1, "units": 0}, {"id": 2, "units": 0}]}`,
+			expected: `{
+				"type": "about:blank",
+				"title": "Bad Request",
+				"status": 400,
+				"code": "INVALID_INPUT",
+				"detail": "recipe must have at least one ingredient",
+				"errors": [{"field": "ingredients", "message": "recipe must have at least one ingredient"}]
+			}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:        "should get error if unexistent recipe",
+			recipeIDstr: "123",
+			payload:     `{}`,
+			expected:    notFoundProblem,
+			statusCode:  http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/recipes/"+tc.recipeIDstr+"/simulate", bytes.NewBufferString(tc.payload))
+			require.NoError(t, err)
+			rr := makeRequest(t, clock, prepare, req)
+			assert.Equal(t, tc.statusCode, rr.Code)
+			if tc.expected != rr.Body.String() {
+				assert.JSONEq(t, tc.expected, rr.Body.String(), "Response body differs")
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"costly/api/handlers"
 
+	"costly/core/errs"
 	"costly/core/usecases"
 	"net/http"
 
@@ -37,21 +38,70 @@ func NewRouter(useCases *usecases.UseCases, authMiddleware Middleware, middlewar
 	// TODO: change this to use zerolog
 	r.Use(middleware.Logger)
 
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		handlers.RespondError(w, r, errs.NewNotFoundError("route"))
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		handlers.RespondError(w, r, handlers.NewProblem(http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", ""))
+	})
+
+	r.Get("/openapi.json", openAPIHandler)
+
 	// authenticated routes
 	r.Group(func(r chi.Router) {
 		r.Use(authMiddleware)
+		// search
+		r.Get("/search", handlers.SearchHandler(useCases.Search))
+
 		// ingredients
 		r.Get("/ingredients", handlers.GetIngredientsHandler(useCases.Ingredients))
 		r.Post("/ingredients", handlers.CreateIngredientHandler(useCases.Ingredients))
+		r.Post("/ingredients/batch", handlers.CreateIngredientBatchHandler(useCases.Ingredients))
+		r.Put("/ingredients/batch", handlers.EditIngredientBatchHandler(useCases.Ingredients))
+		r.Post("/ingredients/import", handlers.ImportIngredientsHandler(useCases.Ingredients))
 		r.Get("/ingredients/{ingredientID}", handlers.GetIngredientHandler(useCases.Ingredients))
 		r.Put("/ingredients/{ingredientID}", handlers.EditIngredientHandler(useCases.Ingredients))
 		r.Post("/ingredients/{ingredientID}/stock", handlers.AddIngredientStockHandler(useCases.Ingredients))
 
+		// categories
+		r.Post("/categories", handlers.CreateCategoryHandler(useCases.Categories))
+		r.Get("/categories", handlers.GetCategoriesHandler(useCases.Categories))
+		r.Get("/categories/aggregates", handlers.GetCategoryAggregatesHandler(useCases.Categories))
+
+		// menus
+		r.Post("/menus", handlers.CreateMenuHandler(useCases.Menus))
+		r.Get("/menus", handlers.GetMenusHandler(useCases.Menus))
+		r.Get("/menus/{menuID}", handlers.GetMenuHandler(useCases.Menus))
+		r.Get("/menus/{menuID}/costing", handlers.GetMenuCostingHandler(useCases.Menus))
+
+		// scenarios
+		r.Post("/scenarios/price-shock", handlers.PriceShockHandler(useCases.Scenarios))
+
 		// recipes
 		r.Post("/recipes", handlers.CreateRecipeHandler(useCases.Recipes))
 		r.Get("/recipes", handlers.GetRecipesHandler(useCases.Recipes))
+		r.Post("/recipes/batch", handlers.CreateRecipeBatchHandler(useCases.Recipes))
+		r.Put("/recipes/batch", handlers.EditRecipeBatchHandler(useCases.Recipes))
+		r.Post("/recipes/import", handlers.ImportRecipesHandler(useCases.Recipes))
 		r.Get("/recipes/{recipeID}", handlers.GetRecipeHandler(useCases.Recipes))
+		r.Put("/recipes/{recipeID}", handlers.EditRecipeHandler(useCases.Recipes))
+		r.Get("/recipes/{recipeID}/versions", handlers.GetRecipeVersionsHandler(useCases.Recipes))
+		r.Get("/recipes/{recipeID}/versions/diff", handlers.GetRecipeVersionsDiffHandler(useCases.Recipes))
+		r.Get("/recipes/{recipeID}/nutrition", handlers.GetRecipeNutritionHandler(useCases.Recipes))
+		r.Get("/recipes/{recipeID}/scaled", handlers.GetRecipeScaledHandler(useCases.Recipes))
+		r.Get("/recipes/{recipeID}/card", handlers.GetRecipeCardHandler(useCases.Recipes))
+		r.Post("/recipes/{recipeID}/simulate", handlers.SimulateRecipeHandler(useCases.Recipes))
 		r.Post("/recipes/{recipeID}/sales", handlers.AddRecipeSalesHandler(useCases.Recipes))
+		r.Post("/recipes/{recipeID}/productions", handlers.AddRecipeProductionHandler(useCases.Recipes))
+		r.Post("/recipes/{recipeID}/attachments", handlers.AddRecipeAttachmentHandler(useCases.Attachments))
+		r.Get("/recipes/{recipeID}/attachments/{attachmentID}", handlers.GetRecipeAttachmentHandler(useCases.Attachments))
+		r.Delete("/recipes/{recipeID}/attachments/{attachmentID}", handlers.DeleteRecipeAttachmentHandler(useCases.Attachments))
+
+		// admin
+		r.Get("/admin/export", handlers.ExportArchiveHandler(useCases.Archives))
+		r.Post("/admin/import", handlers.RestoreArchiveHandler(useCases.Archives))
+		r.Get("/admin/backups", handlers.GetBackupsHandler(useCases.Backups))
+		r.Post("/admin/backups", handlers.CreateBackupHandler(useCases.Backups))
 	})
 
 	return r
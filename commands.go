@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"costly/core/model"
+	"costly/core/ports/database"
+	comps "costly/core/usecases"
+	"costly/core/usecases/batch"
+)
+
+// runCommand runs the command in args instead of serving the API. It tells
+// whether there was any command to run.
+func runCommand(args []string, components *comps.UseCases, out io.Writer) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+	switch args[0] {
+	case "export":
+		return true, runExport(args[1:], components, out)
+	case "import":
+		return true, runImport(args[1:], components, out)
+	case "restore-archive":
+		return true, runRestoreArchive(args[1:], components, out)
+	}
+	return true, fmt.Errorf("unknown command %q", args[0])
+}
+
+// runDatabaseCommand runs the command in args when it replaces the database
+// file, so it has to be run before the database is opened. It tells whether
+// there was any such command to run.
+func runDatabaseCommand(args []string, config *Config, out io.Writer) (bool, error) {
+	if len(args) == 0 || args[0] != "restore" {
+		return false, nil
+	}
+	return true, runRestoreBackup(args[1:], config, out)
+}
+
+// runRestoreBackup replaces the database with a backup, given either its path
+// or its name in the backup directory.
+func runRestoreBackup(args []string, config *Config, out io.Writer) error {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: restore BACKUP")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected the backup to restore")
+	}
+	path := fs.Arg(0)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		path = filepath.Join(config.Backup.Dir, fs.Arg(0))
+	}
+	if err := database.Restore(context.Background(), path, config.Database.ConnectionString); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "restored %s into %s\n", path, config.Database.ConnectionString)
+	return nil
+}
+
+// columnsFlag collects the field=header mappings of the columns to import.
+type columnsFlag map[string]string
+
+func (c columnsFlag) String() string {
+	return fmt.Sprint(map[string]string(c))
+}
+
+func (c columnsFlag) Set(value string) error {
+	field, header, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("column should be field=header")
+	}
+	c[strings.TrimSpace(field)] = strings.TrimSpace(header)
+	return nil
+}
+
+func runImport(args []string, components *comps.UseCases, out io.Writer) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: import [flags] ingredients|recipes FILE")
+		fs.PrintDefaults()
+	}
+	dryRun := fs.Bool("dry-run", false, "Report what would be imported without importing it.")
+	columns := columnsFlag{}
+	fs.Var(columns, "column", "Header of the column of a field, as field=header. Can be repeated.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("expected what to import and the file")
+	}
+	file, err := os.Open(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	importOpts := batch.ImportOptions{FileName: file.Name(), Content: file, Columns: columns, DryRun: *dryRun}
+	var report *model.ImportReport
+	switch fs.Arg(0) {
+	case "ingredients":
+		report, err = components.Ingredients.Import(context.Background(), importOpts)
+	case "recipes":
+		report, err = components.Recipes.Import(context.Background(), importOpts)
+	default:
+		return fmt.Errorf("can not import %q, only ingredients or recipes", fs.Arg(0))
+	}
+	if err != nil {
+		return err
+	}
+	printImportReport(out, report)
+	return nil
+}
+
+// runExport writes the archive of the whole database to the file. It is not
+// written to out, as logs are written there too.
+func runExport(args []string, components *comps.UseCases, out io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: export FILE")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected the file to export to")
+	}
+	archive, err := components.Archives.Export(context.Background())
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := writeArchive(file, archive); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "exported %s\n", describeSummary(archive.Summary()))
+	return nil
+}
+
+func writeArchive(w io.Writer, archive *model.Archive) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(archive)
+}
+
+// runRestoreArchive restores the archive in the file, which must have been
+// exported with runExport.
+func runRestoreArchive(args []string, components *comps.UseCases, out io.Writer) error {
+	fs := flag.NewFlagSet("restore-archive", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: restore-archive FILE")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected the archive to restore")
+	}
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	archive := model.Archive{}
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&archive); err != nil {
+		return fmt.Errorf("could not read archive: %w", err)
+	}
+	summary, err := components.Archives.Restore(context.Background(), archive)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "restored %s\n", describeSummary(*summary))
+	return nil
+}
+
+func describeSummary(summary model.ArchiveSummary) string {
+	return fmt.Sprintf("%d categories, %d ingredients, %d recipes, %d menus, %d stock entries, %d sales and %d productions",
+		summary.Categories, summary.Ingredients, summary.Recipes, summary.Menus, summary.Stock, summary.Sales, summary.Productions)
+}
+
+func printImportReport(out io.Writer, report *model.ImportReport) {
+	for _, row := range report.Rows {
+		fmt.Fprintf(out, "line %d\t%s\t%s", row.Line, row.Action, row.Name)
+		if row.ID != 0 {
+			fmt.Fprintf(out, " (id %d)", row.ID)
+		}
+		fmt.Fprintln(out)
+		for _, match := range row.Matches {
+			fmt.Fprintf(out, "  line %d: %q matched to %q\n", match.Line, match.Name, match.MatchedName)
+		}
+		for _, importErr := range row.Errors {
+			fmt.Fprintf(out, "  line %d: %s: %s\n", importErr.Line, importErr.Field, importErr.Message)
+		}
+	}
+	summary := fmt.Sprintf("%d created, %d updated, %d rejected", report.Created, report.Updated, report.Rejected)
+	if report.DryRun {
+		summary += " (dry run, nothing was imported)"
+	}
+	fmt.Fprintln(out, summary)
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"time"
 )
 
 type Database struct {
@@ -16,6 +17,14 @@ type Config struct {
 	Database      struct {
 		ConnectionString string
 	}
+	Storage struct {
+		Dir string
+	}
+	Backup struct {
+		Dir       string
+		Interval  time.Duration
+		Retention int
+	}
 }
 
 func LoadConfig() (*Config, error) {
@@ -24,11 +33,18 @@ func LoadConfig() (*Config, error) {
 	fs.StringVar(&cfg.ListenAddress, "listen-addr", ":3000", "Main listen address for the HTTP server.")
 	fs.StringVar(&cfg.AuthSecret, "auth-secret", "sample-secret", "Authentication secret for signing JWTs.")
 	fs.StringVar(&cfg.Database.ConnectionString, "db.connection-string", "", "SQLite connection string.")
+	fs.StringVar(&cfg.Storage.Dir, "storage.dir", "attachments", "Directory where recipe attachments are stored.")
+	fs.StringVar(&cfg.Backup.Dir, "backup.dir", "backups", "Directory where database backups are stored.")
+	fs.DurationVar(&cfg.Backup.Interval, "backup.interval", 0, "Interval between scheduled backups while serving, none if 0.")
+	fs.IntVar(&cfg.Backup.Retention, "backup.retention", 7, "Number of backups to keep, deleting the oldest ones.")
 	fs.StringVar(&cfg.LogLevel, "log.level", "info", "Log level.")
 	flag.Parse()
 	if cfg.Database.ConnectionString == "" {
 		return &Config{}, fmt.Errorf("empty DB connection string")
 	}
+	if cfg.Backup.Retention < 1 {
+		return &Config{}, fmt.Errorf("backup retention should be at least 1")
+	}
 	fmt.Println(cfg)
 	return &cfg, nil
 }
@@ -3,17 +3,280 @@ package errs
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
-var ErrNotFound = errors.New("entity not found")
-var ErrBadOpts = errors.New("")
+// The kinds of domain errors. Every typed error matches its kind with
+// errors.Is, so callers can tell them apart without knowing every error.
+var (
+	ErrNotFound          = &NotFoundError{}
+	ErrBadOpts           = errors.New("invalid input")
+	ErrConflict          = errors.New("conflict")
+	ErrBadReference      = errors.New("invalid reference")
+	ErrStale             = errors.New("stale version")
+	ErrInsufficientStock = errors.New("insufficient stock")
+)
+
+// Error is a domain error with a stable code clients can rely on instead of
+// the message.
+type Error interface {
+	error
+	Code() string
+}
+
+// NotFoundError is returned when an entity does not exist. Entity is empty
+// when it is not known which one.
+type NotFoundError struct {
+	Entity string
+}
+
+func NewNotFoundError(entity string) *NotFoundError {
+	return &NotFoundError{Entity: entity}
+}
+
+func (e *NotFoundError) Error() string {
+	if e.Entity == "" {
+		return "entity not found"
+	}
+	return e.Entity + " not found"
+}
+
+func (e *NotFoundError) Code() string {
+	return "NOT_FOUND"
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// ValidationError is returned when some input is invalid. Field is the JSON
+// path of the invalid input, empty if it is not a single one.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func NewValidationError(field string, message string) *ValidationError {
+	return &ValidationError{Field: field, Message: message}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func (e *ValidationError) Code() string {
+	return "INVALID_INPUT"
+}
+
+// Is matches the kind of the error, and any other validation error with the
+// same message whatever the field.
+func (e *ValidationError) Is(target error) bool {
+	if t, ok := target.(*ValidationError); ok {
+		return t.Message == e.Message
+	}
+	return target == ErrBadOpts
+}
+
+// At returns the error for the input at the given JSON path, such as
+// ingredients[2].units.
+func (e *ValidationError) At(field string) *ValidationError {
+	return &ValidationError{Field: field, Message: e.Message}
+}
+
+// ValidationErrors are all the validation errors of some input, so they can be
+// reported at once instead of one at a time.
+type ValidationErrors []*ValidationError
+
+// Add collects err, which must be nil, a validation error or several of them.
+func (e *ValidationErrors) Add(err error) {
+	var validationErrs ValidationErrors
+	var validationErr *ValidationError
+	switch {
+	case err == nil:
+	case errors.As(err, &validationErrs):
+		*e = append(*e, validationErrs...)
+	case errors.As(err, &validationErr):
+		*e = append(*e, validationErr)
+	default:
+		*e = append(*e, &ValidationError{Message: err.Error()})
+	}
+}
+
+// In returns the errors of a nested input at their paths in the enclosing one,
+// such as items[1].price for the price of the second item.
+func (e ValidationErrors) In(path string) ValidationErrors {
+	nested := make(ValidationErrors, len(e))
+	for i, err := range e {
+		nested[i] = err.At(path)
+		if err.Field != "" {
+			nested[i] = err.At(path + "." + err.Field)
+		}
+	}
+	return nested
+}
+
+// Err is nil when there are no errors, the only error when there is one, and
+// all of them otherwise.
+func (e ValidationErrors) Err() error {
+	switch len(e) {
+	case 0:
+		return nil
+	case 1:
+		return e[0]
+	}
+	return e
+}
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e ValidationErrors) Code() string {
+	return "INVALID_INPUT"
+}
+
+func (e ValidationErrors) Unwrap() []error {
+	unwrapped := make([]error, len(e))
+	for i, err := range e {
+		unwrapped[i] = err
+	}
+	return unwrapped
+}
+
+// ConflictError is returned when a change clashes with the stored state, such
+// as another entity already having a unique value.
+type ConflictError struct {
+	Entity  string
+	Field   string
+	Message string
+}
+
+func NewConflictError(entity string, field string, message string) *ConflictError {
+	return &ConflictError{Entity: entity, Field: field, Message: message}
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+func (e *ConflictError) Code() string {
+	return "CONFLICT"
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// ReferenceError is returned when an entity refers to another one that does
+// not exist. Entity is the missing one, and Field the input referring to it.
+type ReferenceError struct {
+	Entity  string
+	Field   string
+	Message string
+}
+
+func NewReferenceError(entity string, field string, message string) *ReferenceError {
+	return &ReferenceError{Entity: entity, Field: field, Message: message}
+}
+
+func (e *ReferenceError) Error() string {
+	return e.Message
+}
+
+func (e *ReferenceError) Code() string {
+	return "INVALID_REFERENCE"
+}
+
+func (e *ReferenceError) Is(target error) bool {
+	return target == ErrBadReference
+}
+
+// StaleError is returned when an entity is edited expecting a version of it
+// other than the current one, as someone else edited it meanwhile.
+type StaleError struct {
+	Entity string
+}
+
+func NewStaleError(entity string) *StaleError {
+	return &StaleError{Entity: entity}
+}
+
+func (e *StaleError) Error() string {
+	return e.Entity + " has been modified since it was read"
+}
+
+func (e *StaleError) Code() string {
+	return "PRECONDITION_FAILED"
+}
+
+func (e *StaleError) Is(target error) bool {
+	return target == ErrStale
+}
+
+// InsufficientStockError is returned when more units of an ingredient are
+// required than there are in stock.
+type InsufficientStockError struct {
+	IngredientID int64
+	Available    int
+	Required     int
+}
+
+func (e *InsufficientStockError) Error() string {
+	return fmt.Sprintf("ingredient %d has %d units in stock but %d are required", e.IngredientID, e.Available, e.Required)
+}
+
+func (e *InsufficientStockError) Code() string {
+	return "INSUFFICIENT_STOCK"
+}
 
-func newBadOptsError(msg string) error {
-	return fmt.Errorf("%w%s", ErrBadOpts, msg)
+func (e *InsufficientStockError) Is(target error) bool {
+	return target == ErrInsufficientStock
 }
 
-var ErrBadName = newBadOptsError("name is invalid")
-var ErrBadUnit = newBadOptsError("unit is invalid")
-var ErrBadPrice = newBadOptsError("price is invalid")
-var ErrBadIngrs = newBadOptsError("recipe must have at least one ingredient")
-var ErrBadStockUnits = newBadOptsError("units should be more than 0")
+var ErrBadName = NewValidationError("name", "name is invalid")
+var ErrBadUnit = NewValidationError("unit", "unit is invalid")
+var ErrBadPrice = NewValidationError("price", "price is invalid")
+var ErrBadIngrs = NewValidationError("ingredients", "recipe must have at least one ingredient")
+var ErrRepeatedIngr = NewValidationError("ingredients", "ingredient is repeated in the recipe")
+var ErrBadStockUnits = NewValidationError("units", "units should be more than 0")
+var ErrBadBatches = NewValidationError("batches", "batches should be more than 0")
+var ErrBadProducedIngr = NewValidationError("ingredient_id", "recipe can not consume the ingredient it produces")
+var ErrBadAllergen = NewValidationError("allergens", "allergen is invalid")
+var ErrBadNutrition = NewValidationError("nutrition", "nutrition is invalid")
+var ErrBadPortions = NewValidationError("portions", "portions should be more than 0")
+var ErrBadStep = NewValidationError("steps", "steps can not be empty")
+var ErrBadTimes = NewValidationError("prep_minutes", "prep and cook times can not be negative")
+var ErrBadContentType = NewValidationError("file", "attachments must be images")
+var ErrBadYield = NewValidationError("yield", "yield should be more than 0")
+var ErrBadTag = NewValidationError("tags", "tags can not be empty")
+var ErrBadCategory = NewValidationError("category_id", "category does not exist")
+var ErrBadParent = NewValidationError("parent_id", "category does not exist")
+var ErrBadChannel = NewValidationError("channel", "channel is invalid")
+var ErrBadValidity = NewValidationError("valid_until", "valid_until should not be before valid_from")
+var ErrBadMenuItem = NewValidationError("recipes", "menu items must have at least one recipe")
+var ErrBadRecipe = NewValidationError("recipes", "recipe does not exist")
+var ErrBadMenuItems = NewValidationError("items", "menu must have at least one item")
+var ErrBadIngredient = NewValidationError("", "ingredient does not exist")
+var ErrBadRecipeIngr = NewValidationError("", "ingredient is not in the recipe")
+var ErrBadPriceChange = NewValidationError("changes", "either price or percentage should be given")
+var ErrRepeatedPrice = NewValidationError("prices", "price of the ingredient is changed more than once")
+var ErrBadDays = NewValidationError("days", "days should be more than 0")
+var ErrBadLimit = NewValidationError("limit", "limit should be between 1 and 100")
+var ErrBadSort = NewValidationError("sort", "sort is invalid")
+var ErrBadOrder = NewValidationError("order", "order should be asc or desc")
+var ErrBadCursor = NewValidationError("cursor", "cursor is invalid")
+var ErrBadQuery = NewValidationError("q", "search query should have at least one word")
+var ErrBadBatchMode = NewValidationError("mode", "mode should be all_or_nothing or best_effort")
+var ErrBadBatchItems = NewValidationError("items", "items should have between 1 and 1000 entries")
+var ErrBadVersion = NewValidationError("version", "version should be more than 0")
+var ErrBadSpreadsheet = NewValidationError("file", "file should be a CSV or XLSX spreadsheet")
+var ErrBadColumn = NewValidationError("columns", "column is not a field that can be imported")
+var ErrMissingColumn = NewValidationError("columns", "column is not in the file")
+var ErrBadQuantity = NewValidationError("quantity", "quantity should be an amount followed by its unit")
+var ErrUnmatchedIngr = NewValidationError("ingredient", "ingredient does not match any in the catalog")
+var ErrBadArchiveVersion = NewValidationError("version", "archive version is not supported")
+var ErrBadArchive = NewValidationError("", "archive refers to missing entities or repeats them")
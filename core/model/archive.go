@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+// ArchiveVersion is the version of the archive format written on export. It
+// must be increased whenever the format changes in a way older versions of
+// restore can not read. Version 2 added the versions of ingredients and recipes.
+const ArchiveVersion = 2
+
+// Archive is all the data of a database in a portable format, so it can be
+// restored into another one keeping the ids and timestamps. Attachments are not
+// archived, as their files are not in the database.
+type Archive struct {
+	Version          int                      `json:"version"`
+	ExportedAt       time.Time                `json:"exported_at"`
+	Categories       []Category               `json:"categories"`
+	Ingredients      []ArchivedIngredient     `json:"ingredients"`
+	IngredientPrices []ArchivedPrice          `json:"ingredient_prices"`
+	Recipes          []ArchivedRecipe         `json:"recipes"`
+	RecipeRevisions  []ArchivedRecipeRevision `json:"recipe_revisions"`
+	Stock            []IngredientStock        `json:"stock"`
+	Sales            []RecipeSales            `json:"sales"`
+	Productions      []Production             `json:"productions"`
+	Menus            []Menu                   `json:"menus"`
+}
+
+// ArchivedIngredient is an ingredient along with its version, which is kept out
+// of its JSON elsewhere as clients read it from the ETag.
+type ArchivedIngredient struct {
+	Ingredient
+	Version int `json:"version"`
+}
+
+// ArchivedRecipe is a recipe along with its version, as ArchivedIngredient is.
+type ArchivedRecipe struct {
+	Recipe
+	Version int `json:"version"`
+}
+
+// ArchivedPrice is an entry of the price history of an ingredient.
+type ArchivedPrice struct {
+	ID           int64     `json:"id"`
+	IngredientID int64     `json:"ingredient_id"`
+	Price        float64   `json:"price"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ArchivedRecipeRevision is a revision along with its id, which sales refer to.
+type ArchivedRecipeRevision struct {
+	ID int64 `json:"id"`
+	RecipeRevision
+}
+
+// ArchiveSummary counts what an archive has.
+type ArchiveSummary struct {
+	Categories  int `json:"categories"`
+	Ingredients int `json:"ingredients"`
+	Recipes     int `json:"recipes"`
+	Menus       int `json:"menus"`
+	Stock       int `json:"stock"`
+	Sales       int `json:"sales"`
+	Productions int `json:"productions"`
+}
+
+func (archive *Archive) Summary() ArchiveSummary {
+	return ArchiveSummary{
+		Categories:  len(archive.Categories),
+		Ingredients: len(archive.Ingredients),
+		Recipes:     len(archive.Recipes),
+		Menus:       len(archive.Menus),
+		Stock:       len(archive.Stock),
+		Sales:       len(archive.Sales),
+		Productions: len(archive.Productions),
+	}
+}
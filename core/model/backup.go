@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+// Backup is a snapshot of the database file, named after when it was taken.
+type Backup struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,69 @@
+package model
+
+import "math"
+
+// RecipeCard is a recipe costed to be printed in the kitchen. It is never
+// stored.
+type RecipeCard struct {
+	RecipeID    int64                  `json:"recipe_id"`
+	Name        string                 `json:"name"`
+	Portions    int                    `json:"portions"`
+	Price       float64                `json:"price"`
+	Ingredients []RecipeCardIngredient `json:"ingredients"`
+	Cost        float64                `json:"cost"`
+	PortionCost float64                `json:"portion_cost"`
+	// FoodCostPercentage is zero if the recipe has no price.
+	FoodCostPercentage float64    `json:"food_cost_percentage"`
+	Allergens          []Allergen `json:"allergens"`
+	RecipeMethod
+}
+
+// RecipeCardIngredient is a line of a recipe card. The unit cost is given per
+// kilogram or liter for ingredients measured in grams or milliliters, which is
+// how suppliers price them.
+type RecipeCardIngredient struct {
+	Name      string     `json:"name"`
+	Quantity  Quantity   `json:"quantity"`
+	UnitCost  float64    `json:"unit_cost"`
+	CostUnit  Unit       `json:"cost_unit"`
+	Cost      float64    `json:"cost"`
+	Allergens []Allergen `json:"allergens"`
+}
+
+func NewRecipeCard(recipe RecipeView) RecipeCard {
+	ingredients := []RecipeCardIngredient{}
+	for _, ingredient := range recipe.Ingredients {
+		unitCost, costUnit := ingredient.Price, ingredient.Unit
+		switch ingredient.Unit {
+		case Gram:
+			unitCost, costUnit = ingredient.Price*1000, Kilogram
+		case Milliliter:
+			unitCost, costUnit = ingredient.Price*1000, Liter
+		}
+		ingredients = append(ingredients, RecipeCardIngredient{
+			Name:      ingredient.Name,
+			Quantity:  Quantity{Amount: float64(ingredient.Units), Unit: ingredient.Unit}.Normalize(),
+			UnitCost:  roundCost(unitCost),
+			CostUnit:  costUnit,
+			Cost:      roundCost(ingredient.Price * float64(ingredient.Units)),
+			Allergens: ingredient.Allergens,
+		})
+	}
+	return RecipeCard{
+		RecipeID:           recipe.ID,
+		Name:               recipe.Name,
+		Portions:           recipe.portions(),
+		Price:              recipe.Price,
+		Ingredients:        ingredients,
+		Cost:               roundCost(recipe.Cost()),
+		PortionCost:        roundCost(recipe.Cost() / float64(recipe.portions())),
+		FoodCostPercentage: math.Round(recipe.FoodCostPercentage()*10) / 10,
+		Allergens:          recipe.Allergens(),
+		RecipeMethod:       recipe.RecipeMethod,
+	}
+}
+
+// roundCost rounds to the cent.
+func roundCost(cost float64) float64 {
+	return math.Round(cost*100) / 100
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"costly/core/errs"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Category groups recipes and ingredients, such as the sections of a menu.
+// Categories without parent are top level ones.
+type Category struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	ParentID  *int64    `json:"parent_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewCategory(name string, parentID *int64, now time.Time) (*Category, error) {
+	name = strings.TrimSpace(strings.ToLower(name))
+	if name == "" {
+		return &Category{}, errs.ErrBadName
+	}
+	return &Category{
+		ID:        -1,
+		Name:      name,
+		ParentID:  parentID,
+		CreatedAt: now,
+	}, nil
+}
+
+// Classification is how a recipe or an ingredient is organized: the category
+// it belongs to, if any, and free-form tags.
+type Classification struct {
+	CategoryID *int64   `json:"category_id"`
+	Tags       []string `json:"tags"`
+}
+
+// NewClassification normalizes tags to lowercase, sorted and without
+// duplicates.
+func NewClassification(categoryID *int64, tags []string) (Classification, error) {
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(strings.ToLower(tag))
+		if tag == "" {
+			return Classification{}, errs.ErrBadTag
+		}
+		normalized = append(normalized, tag)
+	}
+	slices.Sort(normalized)
+	return Classification{
+		CategoryID: categoryID,
+		Tags:       slices.Compact(normalized),
+	}, nil
+}
+
+// FoodCostPercentage is the share of the selling price spent in ingredients,
+// zero if the recipe has no price.
+func (recipe *RecipeView) FoodCostPercentage() float64 {
+	if recipe.Price <= 0 {
+		return 0
+	}
+	return recipe.Cost() / recipe.Price * 100
+}
+
+// CategoryAggregate summarizes the recipes of a category and its descendants.
+type CategoryAggregate struct {
+	Category
+	Recipes int `json:"recipes"`
+	// AverageFoodCostPercentage only takes into account recipes with a price.
+	AverageFoodCostPercentage float64 `json:"average_food_cost_percentage"`
+	SoldUnits                 int     `json:"sold_units"`
+	Revenue                   float64 `json:"revenue"`
+}
+
+// NewCategoryAggregates aggregates the recipes and their sold units into every
+// category, counting recipes of subcategories in their ancestors too.
+func NewCategoryAggregates(categories []Category, recipes []RecipeView, soldUnits map[int64]int) []CategoryAggregate {
+	parents := map[int64]*int64{}
+	for _, category := range categories {
+		parents[category.ID] = category.ParentID
+	}
+	aggregates := []CategoryAggregate{}
+	for _, category := range categories {
+		aggregate := CategoryAggregate{Category: category}
+		pricedRecipes, foodCostPercentages := 0, 0.0
+		for _, recipe := range recipes {
+			if !inCategory(recipe.CategoryID, category.ID, parents) {
+				continue
+			}
+			aggregate.Recipes++
+			aggregate.SoldUnits += soldUnits[recipe.ID]
+			aggregate.Revenue += float64(soldUnits[recipe.ID]) * recipe.Price
+			if recipe.Price > 0 {
+				pricedRecipes++
+				foodCostPercentages += recipe.FoodCostPercentage()
+			}
+		}
+		if pricedRecipes > 0 {
+			aggregate.AverageFoodCostPercentage = foodCostPercentages / float64(pricedRecipes)
+		}
+		aggregates = append(aggregates, aggregate)
+	}
+	return aggregates
+}
+
+func inCategory(categoryID *int64, ancestorID int64, parents map[int64]*int64) bool {
+	// Bounded by the number of categories in case of a cycle.
+	for range len(parents) + 1 {
+		if categoryID == nil {
+			return false
+		}
+		if *categoryID == ancestorID {
+			return true
+		}
+		categoryID = parents[*categoryID]
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package model
+
+import (
+	"costly/core/errs"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+type ImportAction string
+
+const (
+	ImportCreated  ImportAction = "created"
+	ImportUpdated  ImportAction = "updated"
+	ImportRejected ImportAction = "rejected"
+)
+
+// ImportReport tells what an import did, or would do when it is a dry run, to
+// every entity in the file.
+type ImportReport struct {
+	DryRun   bool        `json:"dry_run"`
+	Created  int         `json:"created"`
+	Updated  int         `json:"updated"`
+	Rejected int         `json:"rejected"`
+	Rows     []ImportRow `json:"rows"`
+}
+
+// ImportRow is the result of importing an entity, read from the file starting
+// at Line. ID is not given when the entity was rejected nor when a new one would
+// be created by a dry run.
+type ImportRow struct {
+	Line    int           `json:"line"`
+	Name    string        `json:"name"`
+	Action  ImportAction  `json:"action"`
+	ID      int64         `json:"id,omitempty"`
+	Matches []ImportMatch `json:"matches,omitempty"`
+	Errors  []ImportError `json:"errors,omitempty"`
+}
+
+// ImportMatch is an ingredient of the catalog whose name was matched to a
+// different one written in the file.
+type ImportMatch struct {
+	Line         int    `json:"line"`
+	Name         string `json:"name"`
+	IngredientID int64  `json:"ingredient_id"`
+	MatchedName  string `json:"matched_name"`
+}
+
+type ImportError struct {
+	Line    int    `json:"line"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Add counts the row in the report.
+func (report *ImportReport) Add(row ImportRow) {
+	switch row.Action {
+	case ImportCreated:
+		report.Created++
+		if report.DryRun {
+			row.ID = 0
+		}
+	case ImportUpdated:
+		report.Updated++
+	case ImportRejected:
+		report.Rejected++
+		row.ID = 0
+	}
+	report.Rows = append(report.Rows, row)
+}
+
+// NewImportErrors are the errors of an entity read from the file at line,
+// with the field of each validation error.
+func NewImportErrors(line int, err error) []ImportError {
+	var validationErrs errs.ValidationErrors
+	var validationErr *errs.ValidationError
+	switch {
+	case errors.As(err, &validationErrs):
+		importErrs := []ImportError{}
+		for _, validationErr := range validationErrs {
+			importErrs = append(importErrs, ImportError{Line: line, Field: validationErr.Field, Message: validationErr.Message})
+		}
+		return importErrs
+	case errors.As(err, &validationErr):
+		return []ImportError{{Line: line, Field: validationErr.Field, Message: validationErr.Message}}
+	}
+	return []ImportError{{Line: line, Message: err.Error()}}
+}
+
+// ParseDecimal parses a number written with either a decimal point or a
+// decimal comma, such as "2.10" or "2,10".
+func ParseDecimal(number string) (float64, error) {
+	number = strings.TrimSpace(number)
+	if !strings.Contains(number, ".") {
+		number = strings.Replace(number, ",", ".", 1)
+	}
+	return strconv.ParseFloat(number, 64)
+}
+
+// minNameSimilarity is how similar a name must be to another to be taken as a
+// misspelling of it, from 0 to 1.
+const minNameSimilarity = 0.75
+
+// MatchName finds the name most similar to the given one, ignoring case and
+// spacing, so that "Tomatoes" matches "tomato". It returns the index of the
+// name matched, or an error if none is similar enough.
+func MatchName(name string, names []string) (int, error) {
+	normalized := normalizeName(name)
+	best, bestSimilarity := -1, 0.0
+	for i, candidate := range names {
+		similarity := nameSimilarity(normalized, normalizeName(candidate))
+		if similarity > bestSimilarity {
+			best, bestSimilarity = i, similarity
+		}
+	}
+	if bestSimilarity < minNameSimilarity {
+		return -1, errs.ErrUnmatchedIngr
+	}
+	return best, nil
+}
+
+func normalizeName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// nameSimilarity is one minus the edit distance between the names relative to
+// the length of the longest one.
+func nameSimilarity(a string, b string) float64 {
+	if a == b {
+		return 1
+	}
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	return 1 - float64(editDistance(ra, rb))/float64(longest)
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a []rune, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
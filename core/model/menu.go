@@ -0,0 +1,160 @@
+package model
+
+import (
+	"costly/core/errs"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Channel string
+
+const (
+	DineIn   Channel = "dine-in"
+	Delivery Channel = "delivery"
+)
+
+type MenuItemRecipe struct {
+	ID    int64 `json:"id"`
+	Units int   `json:"units"`
+}
+
+// MenuItem is what is offered in a menu for a price: a single recipe, or a set
+// menu or combo of several recipes priced as a bundle.
+type MenuItem struct {
+	ID      int64            `json:"id"`
+	Name    string           `json:"name"`
+	Price   float64          `json:"price"`
+	Recipes []MenuItemRecipe `json:"recipes"`
+}
+
+func (item *MenuItem) IsCombo() bool {
+	return len(item.Recipes) > 1 || item.Recipes[0].Units > 1
+}
+
+// NewMenuItem validates the item, reporting every invalid field at once. Single
+// recipe items may have no name, as they are named after their recipe, but
+// combos must have one.
+func NewMenuItem(name string, price float64, recipes []MenuItemRecipe) (MenuItem, error) {
+	var validation errs.ValidationErrors
+	if len(recipes) == 0 {
+		validation.Add(errs.ErrBadMenuItem)
+	}
+	for i, recipe := range recipes {
+		if recipe.Units <= 0 {
+			validation.Add(errs.ErrBadStockUnits.At(fmt.Sprintf("recipes[%d].units", i)))
+		}
+	}
+	if price <= 0 {
+		validation.Add(errs.ErrBadPrice)
+	}
+	item := MenuItem{ID: -1, Name: strings.TrimSpace(name), Price: price, Recipes: recipes}
+	if len(recipes) > 0 && item.Name == "" && item.IsCombo() {
+		validation.Add(errs.ErrBadName)
+	}
+	if err := validation.Err(); err != nil {
+		return MenuItem{}, err
+	}
+	return item, nil
+}
+
+type Menu struct {
+	ID      int64   `json:"id"`
+	Name    string  `json:"name"`
+	Channel Channel `json:"channel"`
+	// ValidFrom and ValidUntil limit when the menu is offered, if given.
+	ValidFrom    *time.Time `json:"valid_from"`
+	ValidUntil   *time.Time `json:"valid_until"`
+	Items        []MenuItem `json:"items"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastModified time.Time  `json:"last_modified"`
+}
+
+// NewMenu validates the menu, reporting every invalid field at once. Its items
+// must have been validated with NewMenuItem.
+func NewMenu(name string, channel Channel, validFrom *time.Time, validUntil *time.Time, items []MenuItem, now time.Time) (*Menu, error) {
+	var validation errs.ValidationErrors
+	name = strings.TrimSpace(name)
+	if name == "" {
+		validation.Add(errs.ErrBadName)
+	}
+	if channel != DineIn && channel != Delivery {
+		validation.Add(errs.ErrBadChannel)
+	}
+	if validFrom != nil && validUntil != nil && validUntil.Before(*validFrom) {
+		validation.Add(errs.ErrBadValidity)
+	}
+	if len(items) == 0 {
+		validation.Add(errs.ErrBadMenuItems)
+	}
+	if err := validation.Err(); err != nil {
+		return &Menu{}, err
+	}
+	return &Menu{
+		ID:           -1,
+		Name:         name,
+		Channel:      channel,
+		ValidFrom:    validFrom,
+		ValidUntil:   validUntil,
+		Items:        items,
+		CreatedAt:    now,
+		LastModified: now,
+	}, nil
+}
+
+type MenuItemCosting struct {
+	ID                 int64   `json:"id"`
+	Name               string  `json:"name"`
+	Combo              bool    `json:"combo"`
+	Price              float64 `json:"price"`
+	Cost               float64 `json:"cost"`
+	FoodCostPercentage float64 `json:"food_cost_percentage"`
+	Margin             float64 `json:"margin"`
+}
+
+type MenuCosting struct {
+	MenuID  int64             `json:"menu_id"`
+	Name    string            `json:"name"`
+	Channel Channel           `json:"channel"`
+	Items   []MenuItemCosting `json:"items"`
+	Price   float64           `json:"price"`
+	Cost    float64           `json:"cost"`
+	// FoodCostPercentage is the blended food cost of the menu, weighting every
+	// item by its price.
+	FoodCostPercentage float64 `json:"food_cost_percentage"`
+}
+
+// NewMenuCosting costs every item of the menu with the given recipes, which
+// must include all the recipes in the menu.
+func NewMenuCosting(menu Menu, recipes map[int64]RecipeView) MenuCosting {
+	costing := MenuCosting{
+		MenuID:  menu.ID,
+		Name:    menu.Name,
+		Channel: menu.Channel,
+		Items:   []MenuItemCosting{},
+	}
+	for _, item := range menu.Items {
+		itemCosting := MenuItemCosting{
+			ID:    item.ID,
+			Name:  item.Name,
+			Combo: item.IsCombo(),
+			Price: item.Price,
+		}
+		for _, itemRecipe := range item.Recipes {
+			recipe := recipes[itemRecipe.ID]
+			itemCosting.Cost += recipe.Cost() * float64(itemRecipe.Units)
+			if itemCosting.Name == "" {
+				itemCosting.Name = recipe.Name
+			}
+		}
+		itemCosting.FoodCostPercentage = itemCosting.Cost / itemCosting.Price * 100
+		itemCosting.Margin = itemCosting.Price - itemCosting.Cost
+		costing.Items = append(costing.Items, itemCosting)
+		costing.Price += itemCosting.Price
+		costing.Cost += itemCosting.Cost
+	}
+	if costing.Price > 0 {
+		costing.FoodCostPercentage = costing.Cost / costing.Price * 100
+	}
+	return costing
+}
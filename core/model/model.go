@@ -2,6 +2,9 @@ package model
 
 import (
 	"costly/core/errs"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -17,14 +20,67 @@ const (
 
 type ID int64
 
+type Allergen string
+
+// The 14 allergens that must be declared under EU Regulation 1169/2011. Any
+// other non-empty name is accepted as a custom allergen.
+const (
+	Celery      Allergen = "celery"
+	Gluten      Allergen = "gluten"
+	Crustaceans Allergen = "crustaceans"
+	Eggs        Allergen = "eggs"
+	Fish        Allergen = "fish"
+	Lupin       Allergen = "lupin"
+	Milk        Allergen = "milk"
+	Molluscs    Allergen = "molluscs"
+	Mustard     Allergen = "mustard"
+	Nuts        Allergen = "nuts"
+	Peanuts     Allergen = "peanuts"
+	Sesame      Allergen = "sesame"
+	Soya        Allergen = "soya"
+	Sulphites   Allergen = "sulphites"
+)
+
+var EUAllergens = []Allergen{Celery, Gluten, Crustaceans, Eggs, Fish, Lupin, Milk, Molluscs, Mustard, Nuts, Peanuts, Sesame, Soya, Sulphites}
+
+func (a Allergen) IsCustom() bool {
+	return !slices.Contains(EUAllergens, a)
+}
+
+// NewAllergens normalizes allergen names to lower case and returns them sorted
+// and without duplicates, reporting every empty one at its index.
+func NewAllergens(allergens []Allergen) ([]Allergen, error) {
+	var validation errs.ValidationErrors
+	normalized := []Allergen{}
+	for i, allergen := range allergens {
+		name := Allergen(strings.ToLower(strings.TrimSpace(string(allergen))))
+		if name == "" {
+			validation.Add(errs.ErrBadAllergen.At(fmt.Sprintf("allergens[%d]", i)))
+		}
+		normalized = append(normalized, name)
+	}
+	if err := validation.Err(); err != nil {
+		return []Allergen{}, err
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized), nil
+}
+
 type Ingredient struct {
-	ID           int64     `json:"id"`
-	Name         string    `json:"name"`
-	Unit         Unit      `json:"unit"`
-	Price        float64   `json:"price"`
-	UnitsInStock int       `json:"units_in_stock"`
+	ID           int64      `json:"id"`
+	Name         string     `json:"name"`
+	Unit         Unit       `json:"unit"`
+	Price        float64    `json:"price"`
+	UnitsInStock int        `json:"units_in_stock"`
+	Allergens    []Allergen `json:"allergens"`
+	Nutrition    Nutrition  `json:"nutrition"`
+	Classification
 	CreatedAt    time.Time `json:"created_at"`
 	LastModified time.Time `json:"last_modified"`
+	// Version counts the edits of the ingredient, so that editing it can tell
+	// whether it was edited meanwhile. Stock entries, sales and productions
+	// change the stock without changing the version.
+	Version int `json:"-"`
 }
 
 func NewIngredient(name string, unit Unit, price float64, now time.Time) (*Ingredient, error) {
@@ -39,13 +95,15 @@ func NewIngredient(name string, unit Unit, price float64, now time.Time) (*Ingre
 		return &Ingredient{}, errs.ErrBadPrice
 	}
 	return &Ingredient{
-		ID:           -1,
-		Name:         name,
-		Unit:         unit,
-		Price:        price,
-		UnitsInStock: 0,
-		CreatedAt:    now,
-		LastModified: now,
+		ID:             -1,
+		Name:           name,
+		Unit:           unit,
+		Price:          price,
+		UnitsInStock:   0,
+		Allergens:      []Allergen{},
+		Classification: Classification{Tags: []string{}},
+		CreatedAt:      now,
+		LastModified:   now,
 	}, nil
 }
 
@@ -76,18 +134,27 @@ func NewIngredientStock(ingredientID int64, units int, price float64, now time.T
 }
 
 type RecipeIngredientView struct {
-	ID    int64   `json:"id"`
-	Name  string  `json:"name"`
-	Price float64 `json:"price"`
-	Units int     `json:"units"`
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Unit      Unit       `json:"unit"`
+	Price     float64    `json:"price"`
+	Units     int        `json:"units"`
+	Allergens []Allergen `json:"allergens"`
+	Nutrition Nutrition  `json:"-"`
 }
 
 type RecipeView struct {
-	ID           int64                  `json:"id"`
-	Name         string                 `json:"name"`
-	Ingredients  []RecipeIngredientView `json:"ingredients"`
-	CreatedAt    time.Time              `json:"created_at"`
-	LastModified time.Time              `json:"last_modified"`
+	ID          int64                  `json:"id"`
+	Name        string                 `json:"name"`
+	Price       float64                `json:"price"`
+	Portions    int                    `json:"portions"`
+	Ingredients []RecipeIngredientView `json:"ingredients"`
+	RecipeMethod
+	Classification
+	Attachments  []RecipeAttachment `json:"attachments"`
+	CreatedAt    time.Time          `json:"created_at"`
+	LastModified time.Time          `json:"last_modified"`
+	Version      int                `json:"-"`
 }
 
 func (recipe *RecipeView) Cost() float64 {
@@ -100,11 +167,23 @@ func (recipe *RecipeView) Cost() float64 {
 	return cost
 }
 
+// Allergens returns the allergens contained in any of the recipe ingredients.
+func (recipe *RecipeView) Allergens() []Allergen {
+	allergens := []Allergen{}
+	for _, ingredient := range recipe.Ingredients {
+		allergens = append(allergens, ingredient.Allergens...)
+	}
+	slices.Sort(allergens)
+	return slices.Compact(allergens)
+}
+
 type RecipeSales struct {
-	ID        int64
-	RecipeID  int64
-	Units     int
-	CreatedAt time.Time
+	ID       int64 `json:"id"`
+	RecipeID int64 `json:"recipe_id"`
+	// RevisionID is the revision of the recipe that was active when it was sold.
+	RevisionID int64     `json:"revision_id"`
+	Units      int       `json:"units"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 func NewRecipeSales(recipeID int64, units int, now time.Time) *RecipeSales {
@@ -116,31 +195,154 @@ func NewRecipeSales(recipeID int64, units int, now time.Time) *RecipeSales {
 	}
 }
 
+type Production struct {
+	ID           int64     `json:"id"`
+	RecipeID     int64     `json:"recipe_id"`
+	IngredientID int64     `json:"ingredient_id"`
+	Batches      int       `json:"batches"`
+	Units        int       `json:"units"`
+	Price        float64   `json:"price"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewProduction records that recipe was made batches times yielding units of the
+// prep ingredient ingredientID. The produced units are priced at the rolled-up
+// cost of the consumed ingredients.
+func NewProduction(recipe RecipeView, ingredientID int64, batches int, units int, now time.Time) (*Production, error) {
+	if batches <= 0 {
+		return &Production{}, errs.ErrBadBatches
+	}
+	if units <= 0 {
+		return &Production{}, errs.ErrBadStockUnits
+	}
+	for _, ingredient := range recipe.Ingredients {
+		if ingredient.ID == ingredientID {
+			return &Production{}, errs.ErrBadProducedIngr
+		}
+	}
+	return &Production{
+		ID:           -1,
+		RecipeID:     recipe.ID,
+		IngredientID: ingredientID,
+		Batches:      batches,
+		Units:        units,
+		Price:        recipe.Cost() * float64(batches) / float64(units),
+		CreatedAt:    now,
+	}, nil
+}
+
 type RecipeIngredient struct {
 	ID    int64 `json:"id"`
 	Units int   `json:"units"`
 }
 
+// RecipeMethod is how a recipe is prepared and plated. Times are given in minutes.
+type RecipeMethod struct {
+	Steps        []string `json:"steps"`
+	PlatingNotes string   `json:"plating_notes"`
+	PrepMinutes  int      `json:"prep_minutes"`
+	CookMinutes  int      `json:"cook_minutes"`
+}
+
+func NewRecipeMethod(steps []string, platingNotes string, prepMinutes int, cookMinutes int) (RecipeMethod, error) {
+	var validation errs.ValidationErrors
+	trimmedSteps := []string{}
+	for i, step := range steps {
+		trimmedStep := strings.TrimSpace(step)
+		if trimmedStep == "" {
+			validation.Add(errs.ErrBadStep.At(fmt.Sprintf("steps[%d]", i)))
+		}
+		trimmedSteps = append(trimmedSteps, trimmedStep)
+	}
+	if prepMinutes < 0 {
+		validation.Add(errs.ErrBadTimes)
+	}
+	if cookMinutes < 0 {
+		validation.Add(errs.ErrBadTimes.At("cook_minutes"))
+	}
+	if err := validation.Err(); err != nil {
+		return RecipeMethod{}, err
+	}
+	return RecipeMethod{
+		Steps:        trimmedSteps,
+		PlatingNotes: strings.TrimSpace(platingNotes),
+		PrepMinutes:  prepMinutes,
+		CookMinutes:  cookMinutes,
+	}, nil
+}
+
 type Recipe struct {
-	ID           int64              `json:"id"`
-	Name         string             `json:"name"`
-	Ingredients  []RecipeIngredient `json:"ingredients"`
-	CreatedAt    time.Time          `json:"created_at"`
-	LastModified time.Time          `json:"last_modified"`
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// Price is what the recipe is sold for, the same unit registered in its sales.
+	Price       float64            `json:"price"`
+	Portions    int                `json:"portions"`
+	Ingredients []RecipeIngredient `json:"ingredients"`
+	RecipeMethod
+	Classification
+	CreatedAt    time.Time `json:"created_at"`
+	LastModified time.Time `json:"last_modified"`
+	// Version counts the changes of the recipe, as Ingredient.Version does.
+	Version int `json:"-"`
 }
 
+// NewRecipe validates the recipe, reporting every invalid field at once.
 func NewRecipe(name string, ingredients []RecipeIngredient, now time.Time) (*Recipe, error) {
+	var validation errs.ValidationErrors
 	if name == "" {
-		return &Recipe{}, errs.ErrBadName
+		validation.Add(errs.ErrBadName)
 	}
 	if len(ingredients) == 0 {
-		return &Recipe{}, errs.ErrBadIngrs
+		validation.Add(errs.ErrBadIngrs)
+	}
+	seen := map[int64]bool{}
+	for i, ingredient := range ingredients {
+		if ingredient.Units <= 0 {
+			validation.Add(errs.ErrBadStockUnits.At(fmt.Sprintf("ingredients[%d].units", i)))
+		}
+		if seen[ingredient.ID] {
+			validation.Add(errs.ErrRepeatedIngr.At(fmt.Sprintf("ingredients[%d].id", i)))
+		}
+		seen[ingredient.ID] = true
+	}
+	if err := validation.Err(); err != nil {
+		return &Recipe{}, err
 	}
 	return &Recipe{
-		ID:           -1,
-		Name:         name,
-		Ingredients:  ingredients,
-		CreatedAt:    now,
-		LastModified: now,
+		ID:             -1,
+		Name:           name,
+		Portions:       1,
+		Ingredients:    ingredients,
+		RecipeMethod:   RecipeMethod{Steps: []string{}},
+		Classification: Classification{Tags: []string{}},
+		CreatedAt:      now,
+		LastModified:   now,
+	}, nil
+}
+
+type RecipeAttachment struct {
+	ID          int64     `json:"id"`
+	RecipeID    int64     `json:"recipe_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func NewRecipeAttachment(recipeID int64, fileName string, contentType string, now time.Time) (*RecipeAttachment, error) {
+	fileName = strings.TrimSpace(fileName)
+	if fileName == "" {
+		return &RecipeAttachment{}, errs.ErrBadName
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		return &RecipeAttachment{}, errs.ErrBadContentType
+	}
+	return &RecipeAttachment{
+		ID:          -1,
+		RecipeID:    recipeID,
+		FileName:    fileName,
+		ContentType: contentType,
+		CreatedAt:   now,
 	}, nil
 }
@@ -4,7 +4,9 @@ import (
 	"costly/core/errs"
 	"costly/core/model"
 	"costly/core/ports/clock"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -139,4 +141,407 @@ func TestNewRecipe(t *testing.T) {
 		_, err := model.NewRecipe("name", []model.RecipeIngredient{}, now)
 		assert.Equal(t, err, errs.ErrBadIngrs)
 	})
+
+	t.Run("should return every invalid ingredient with its path", func(t *testing.T) {
+		recipeIngredients := []model.RecipeIngredient{
+			{ID: 5, Units: 150},
+			{ID: 6, Units: -1},
+			{ID: 5, Units: 10},
+		}
+		_, err := model.NewRecipe("", recipeIngredients, now)
+		assert.Equal(t, errs.ValidationErrors{
+			errs.ErrBadName,
+			errs.ErrBadStockUnits.At("ingredients[1].units"),
+			errs.ErrRepeatedIngr.At("ingredients[2].id"),
+		}, err)
+	})
+}
+
+func TestNewProduction(t *testing.T) {
+
+	now := clock.New().Now()
+	recipe := model.RecipeView{
+		ID:   1,
+		Name: "sauce",
+		Ingredients: []model.RecipeIngredientView{
+			{ID: 1, Name: "tomato", Units: 1000, Price: 0.5},
+			{ID: 2, Name: "salt", Units: 10, Price: 2.0},
+		},
+	}
+
+	t.Run("should price produced units at the rolled-up cost of the recipe", func(t *testing.T) {
+		production, err := model.NewProduction(recipe, 3, 2, 1040, now)
+		require.NoError(t, err)
+		assert.Equal(t, int64(-1), production.ID)
+		assert.Equal(t, int64(1), production.RecipeID)
+		assert.Equal(t, int64(3), production.IngredientID)
+		assert.Equal(t, 2, production.Batches)
+		assert.Equal(t, 1040, production.Units)
+		assert.Equal(t, 1.0, production.Price)
+		assert.Equal(t, now, production.CreatedAt)
+	})
+
+	t.Run("should return error if batches or units are invalid", func(t *testing.T) {
+		_, err := model.NewProduction(recipe, 3, 0, 1040, now)
+		assert.Equal(t, errs.ErrBadBatches, err)
+		_, err = model.NewProduction(recipe, 3, 1, 0, now)
+		assert.Equal(t, errs.ErrBadStockUnits, err)
+	})
+
+	t.Run("should return error if recipe consumes the produced ingredient", func(t *testing.T) {
+		_, err := model.NewProduction(recipe, 1, 1, 1040, now)
+		assert.Equal(t, errs.ErrBadProducedIngr, err)
+	})
+}
+
+func TestNewAllergens(t *testing.T) {
+
+	t.Run("should normalize, sort and deduplicate allergens", func(t *testing.T) {
+		allergens, err := model.NewAllergens([]model.Allergen{" Milk", "gluten", "GLUTEN", "garlic"})
+		require.NoError(t, err)
+		assert.Equal(t, []model.Allergen{"garlic", model.Gluten, model.Milk}, allergens)
+		assert.True(t, allergens[0].IsCustom())
+		assert.False(t, allergens[1].IsCustom())
+	})
+
+	t.Run("should return error at every empty allergen", func(t *testing.T) {
+		_, err := model.NewAllergens([]model.Allergen{"milk", " "})
+		assert.Equal(t, errs.ErrBadAllergen.At("allergens[1]"), err)
+		_, err = model.NewAllergens([]model.Allergen{"", "milk", " "})
+		assert.Equal(t, errs.ValidationErrors{errs.ErrBadAllergen.At("allergens[0]"), errs.ErrBadAllergen.At("allergens[2]")}, err)
+	})
+}
+
+func TestRecipeAllergens(t *testing.T) {
+
+	t.Run("allergens of a recipe are the allergens of its ingredients", func(t *testing.T) {
+		recipe := model.RecipeView{
+			Ingredients: []model.RecipeIngredientView{
+				{ID: 1, Allergens: []model.Allergen{model.Milk, model.Gluten}},
+				{ID: 2, Allergens: []model.Allergen{}},
+				{ID: 3, Allergens: []model.Allergen{model.Eggs, model.Milk}},
+			},
+		}
+		assert.Equal(t, []model.Allergen{model.Eggs, model.Gluten, model.Milk}, recipe.Allergens())
+	})
+}
+
+func TestRecipeNutrition(t *testing.T) {
+
+	recipe := model.RecipeView{
+		ID:       1,
+		Name:     "aName",
+		Portions: 2,
+		Ingredients: []model.RecipeIngredientView{
+			{ID: 1, Units: 200, Nutrition: model.Nutrition{Energy: 100, Protein: 10, Fat: 5, SaturatedFat: 1, Salt: 1}},
+			{ID: 2, Units: 50, Nutrition: model.Nutrition{Energy: 400, Carbohydrate: 80, Sugar: 40, Fibre: 2}},
+		},
+	}
+
+	t.Run("nutrition of a recipe is the sum of its ingredients nutrition per 100 g", func(t *testing.T) {
+		assert.Equal(t, model.Nutrition{Energy: 400, Protein: 20, Fat: 10, SaturatedFat: 2, Carbohydrate: 40, Sugar: 20, Salt: 2, Fibre: 1}, recipe.Nutrition())
+		assert.Equal(t, model.Nutrition{Energy: 200, Protein: 10, Fat: 5, SaturatedFat: 1, Carbohydrate: 20, Sugar: 10, Salt: 1, Fibre: 0.5}, recipe.PortionNutrition())
+		assert.Equal(t, 250.0, recipe.Weight())
+	})
+
+	t.Run("nutrition label should be given per 100 g and per portion", func(t *testing.T) {
+		label := model.NewNutritionLabel(recipe)
+		assert.Equal(t, 2, label.Portions)
+		assert.Equal(t, 125.0, label.PortionWeight)
+		assert.Equal(t, 160.0, label.Per100g.Energy)
+		assert.Equal(t, 669.0, label.Per100g.EnergyKJ)
+		assert.Equal(t, 8.0, label.Per100g.Protein)
+		assert.Equal(t, 0.8, label.Per100g.Salt)
+		assert.Equal(t, 0.0, label.Per100g.Fibre)
+		assert.Equal(t, 200.0, label.PerPortion.Energy)
+	})
+
+	t.Run("should return error if nutrition is invalid", func(t *testing.T) {
+		assert.NoError(t, model.Nutrition{Fat: 2, SaturatedFat: 1}.Validate())
+		assert.Equal(t, errs.ErrBadNutrition.At("nutrition.protein"), model.Nutrition{Protein: -1}.Validate())
+		assert.Equal(t, errs.ErrBadNutrition.At("nutrition.saturated_fat"), model.Nutrition{Fat: 1, SaturatedFat: 2}.Validate())
+		assert.Equal(t, errs.ErrBadNutrition.At("nutrition.sugar"), model.Nutrition{Carbohydrate: 1, Sugar: 2}.Validate())
+		assert.Equal(t, errs.ValidationErrors{
+			errs.ErrBadNutrition.At("nutrition.energy_kcal"),
+			errs.ErrBadNutrition.At("nutrition.salt"),
+			errs.ErrBadNutrition.At("nutrition.sugar"),
+		}, model.Nutrition{Energy: -1, Salt: -1, Sugar: 2}.Validate())
+	})
+}
+
+func TestQuantityNormalize(t *testing.T) {
+
+	testCases := []struct {
+		quantity model.Quantity
+		expected model.Quantity
+	}{
+		{model.Quantity{Amount: 1500, Unit: model.Gram}, model.Quantity{Amount: 1.5, Unit: model.Kilogram}},
+		{model.Quantity{Amount: 999, Unit: model.Gram}, model.Quantity{Amount: 999, Unit: model.Gram}},
+		{model.Quantity{Amount: 0.25, Unit: model.Kilogram}, model.Quantity{Amount: 250, Unit: model.Gram}},
+		{model.Quantity{Amount: 2500, Unit: model.Milliliter}, model.Quantity{Amount: 2.5, Unit: model.Liter}},
+		{model.Quantity{Amount: 0.5, Unit: model.Liter}, model.Quantity{Amount: 500, Unit: model.Milliliter}},
+		{model.Quantity{Amount: 2.0 / 3, Unit: model.Units}, model.Quantity{Amount: 0.667, Unit: model.Units}},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, tc.quantity.Normalize())
+	}
+}
+
+func TestRecipeScale(t *testing.T) {
+
+	recipe := model.RecipeView{
+		ID:       1,
+		Name:     "aName",
+		Portions: 4,
+		Ingredients: []model.RecipeIngredientView{
+			{ID: 1, Name: "flour", Unit: model.Gram, Units: 500, Price: 0.002},
+			{ID: 2, Name: "eggs", Unit: model.Units, Units: 2, Price: 0.25},
+		},
+	}
+
+	t.Run("should scale ingredients and cost to the given portions", func(t *testing.T) {
+		scaled, err := recipe.ScaleToPortions(12)
+		require.NoError(t, err)
+		assert.Equal(t, 3.0, scaled.Factor)
+		assert.Equal(t, 12.0, scaled.Portions)
+		assert.Equal(t, model.Quantity{Amount: 1.5, Unit: model.Kilogram}, scaled.Ingredients[0].Quantity)
+		assert.Equal(t, model.Quantity{Amount: 6, Unit: model.Units}, scaled.Ingredients[1].Quantity)
+		assert.InDelta(t, 4.5, scaled.Cost, 0.0001)
+		assert.Equal(t, 500, recipe.Ingredients[0].Units)
+	})
+
+	t.Run("should scale to the given yield in grams", func(t *testing.T) {
+		scaled, err := recipe.ScaleToWeight(251)
+		require.NoError(t, err)
+		assert.Equal(t, 0.5, scaled.Factor)
+		assert.Equal(t, 2.0, scaled.Portions)
+		assert.Equal(t, model.Quantity{Amount: 250, Unit: model.Gram}, scaled.Ingredients[0].Quantity)
+	})
+
+	t.Run("should return error if portions or yield are invalid", func(t *testing.T) {
+		_, err := recipe.ScaleToPortions(0)
+		assert.Equal(t, errs.ErrBadPortions, err)
+		_, err = recipe.ScaleToWeight(-1)
+		assert.Equal(t, errs.ErrBadYield, err)
+		_, err = recipe.ScaleToWeight(math.NaN())
+		assert.Equal(t, errs.ErrBadYield, err)
+		_, err = recipe.ScaleToWeight(math.Inf(1))
+		assert.Equal(t, errs.ErrBadYield, err)
+	})
+}
+
+func TestNewRecipeRevisionDiff(t *testing.T) {
+
+	t.Run("should get added, removed and changed ingredients between revisions", func(t *testing.T) {
+		from := model.RecipeRevision{RecipeID: 1, Version: 1, Name: "steak", Portions: 1, Cost: 210, Ingredients: []model.RecipeRevisionIngredient{
+			{ID: 1, Name: "meat", Price: 1, Units: 200},
+			{ID: 2, Name: "salt", Price: 5, Units: 2},
+		}}
+		to := model.RecipeRevision{RecipeID: 1, Version: 3, Name: "peppered steak", Portions: 1, Cost: 213, Ingredients: []model.RecipeRevisionIngredient{
+			{ID: 1, Name: "meat", Price: 1, Units: 200},
+			{ID: 3, Name: "pepper", Price: 13, Units: 1},
+		}}
+
+		diff := model.NewRecipeRevisionDiff(from, to)
+		assert.Equal(t, 1, diff.From)
+		assert.Equal(t, 3, diff.To)
+		assert.Equal(t, &model.Change[string]{From: "steak", To: "peppered steak"}, diff.Name)
+		assert.Nil(t, diff.Portions)
+		assert.Equal(t, model.Change[float64]{From: 210, To: 213}, diff.Cost)
+		assert.Equal(t, []model.RecipeRevisionIngredient{to.Ingredients[1]}, diff.Added)
+		assert.Equal(t, []model.RecipeRevisionIngredient{from.Ingredients[1]}, diff.Removed)
+		assert.Empty(t, diff.Changed)
+	})
+}
+
+func TestNewClassification(t *testing.T) {
+
+	t.Run("should normalize tags", func(t *testing.T) {
+		categoryID := int64(2)
+		classification, err := model.NewClassification(&categoryID, []string{" Vegan", "spicy", "vegan"})
+		require.NoError(t, err)
+		assert.Equal(t, &categoryID, classification.CategoryID)
+		assert.Equal(t, []string{"spicy", "vegan"}, classification.Tags)
+	})
+
+	t.Run("should return error if a tag is empty", func(t *testing.T) {
+		_, err := model.NewClassification(nil, []string{"vegan", ""})
+		assert.Equal(t, errs.ErrBadTag, err)
+	})
+}
+
+func TestRecipeFoodCostPercentage(t *testing.T) {
+
+	recipe := model.RecipeView{Price: 8, Ingredients: []model.RecipeIngredientView{{ID: 1, Units: 200, Price: 0.01}}}
+	assert.Equal(t, 25.0, recipe.FoodCostPercentage())
+	recipe.Price = 0
+	assert.Equal(t, 0.0, recipe.FoodCostPercentage())
+}
+
+func TestNewMenu(t *testing.T) {
+
+	now := clock.New().Now()
+	item, err := model.NewMenuItem("", 10, []model.MenuItemRecipe{{ID: 1, Units: 1}})
+	require.NoError(t, err)
+
+	t.Run("single recipe items may have no name but combos must", func(t *testing.T) {
+		assert.False(t, item.IsCombo())
+		_, err := model.NewMenuItem(" ", 18, []model.MenuItemRecipe{{ID: 1, Units: 2}})
+		assert.Equal(t, errs.ErrBadName, err)
+		_, err = model.NewMenuItem("", 10, []model.MenuItemRecipe{})
+		assert.Equal(t, errs.ErrBadMenuItem, err)
+	})
+
+	t.Run("should report every invalid field of an item", func(t *testing.T) {
+		_, err := model.NewMenuItem("", 0, []model.MenuItemRecipe{{ID: 1, Units: 1}, {ID: 2, Units: 0}})
+		assert.Equal(t, errs.ValidationErrors{errs.ErrBadStockUnits.At("recipes[1].units"), errs.ErrBadPrice, errs.ErrBadName}, err)
+	})
+
+	t.Run("menu validity should not end before it starts", func(t *testing.T) {
+		from, until := now, now.Add(time.Hour)
+		_, err := model.NewMenu("lunch", model.DineIn, &from, &until, []model.MenuItem{item}, now)
+		require.NoError(t, err)
+		_, err = model.NewMenu("lunch", model.DineIn, &until, &from, []model.MenuItem{item}, now)
+		assert.Equal(t, errs.ErrBadValidity, err)
+	})
+
+	t.Run("should report every invalid field of a menu", func(t *testing.T) {
+		_, err := model.NewMenu(" ", "takeaway", nil, nil, []model.MenuItem{}, now)
+		assert.Equal(t, errs.ValidationErrors{errs.ErrBadName, errs.ErrBadChannel, errs.ErrBadMenuItems}, err)
+	})
+}
+
+func TestNewMenuCosting(t *testing.T) {
+
+	recipes := map[int64]model.RecipeView{
+		1: {ID: 1, Name: "burger", Ingredients: []model.RecipeIngredientView{{ID: 1, Units: 100, Price: 0.03}}},
+		2: {ID: 2, Name: "fries", Ingredients: []model.RecipeIngredientView{{ID: 2, Units: 200, Price: 0.005}}},
+	}
+	menu := model.Menu{ID: 1, Name: "delivery", Channel: model.Delivery, Items: []model.MenuItem{
+		{ID: 1, Price: 12, Recipes: []model.MenuItemRecipe{{ID: 1, Units: 1}}},
+		{ID: 2, Name: "burger meal", Price: 20, Recipes: []model.MenuItemRecipe{{ID: 1, Units: 1}, {ID: 2, Units: 2}}},
+	}}
+
+	costing := model.NewMenuCosting(menu, recipes)
+	require.Len(t, costing.Items, 2)
+	assert.Equal(t, "burger", costing.Items[0].Name)
+	assert.InDelta(t, 25.0, costing.Items[0].FoodCostPercentage, 0.0001)
+	assert.True(t, costing.Items[1].Combo)
+	assert.InDelta(t, 5.0, costing.Items[1].Cost, 0.0001)
+	assert.InDelta(t, 15.0, costing.Items[1].Margin, 0.0001)
+	assert.InDelta(t, 8.0/32*100, costing.FoodCostPercentage, 0.0001)
+}
+
+func TestRecipeChangesApply(t *testing.T) {
+
+	recipe := model.RecipeView{Price: 10, Ingredients: []model.RecipeIngredientView{{ID: 1, Units: 100, Price: 0.02}, {ID: 2, Units: 50, Price: 0.01}}}
+	substitutes := map[int64]model.RecipeIngredientView{3: {ID: 3, Name: "tofu", Price: 0.01}}
+	units := 150
+	changes := model.RecipeChanges{
+		Substitutions: []model.IngredientSubstitution{{ID: 1, SubstituteID: 3, Units: &units}},
+		Quantities:    []model.RecipeIngredient{{ID: 2, Units: 0}},
+	}
+	simulation, err := model.NewRecipeSimulation(recipe, changes, substitutes)
+	require.NoError(t, err)
+	assert.Equal(t, []model.RecipeIngredientView{{ID: 3, Name: "tofu", Price: 0.01, Units: 150}}, simulation.Ingredients)
+	assert.Len(t, recipe.Ingredients, 2)
+	assert.Equal(t, int64(1), recipe.Ingredients[0].ID)
+	assert.InDelta(t, 1.5, simulation.Simulated.Cost, 0.0001)
+	assert.InDelta(t, -1.0, simulation.CostDelta, 0.0001)
+	assert.InDelta(t, 1.0, simulation.MarginDelta, 0.0001)
+
+	merged, err := model.RecipeChanges{Substitutions: []model.IngredientSubstitution{{ID: 1, SubstituteID: 2}}}.Apply(recipe, map[int64]model.RecipeIngredientView{2: {ID: 2, Price: 0.01}})
+	require.NoError(t, err)
+	assert.Equal(t, []model.RecipeIngredientView{{ID: 2, Units: 150, Price: 0.01}}, merged)
+
+	price := 0.03
+	_, err = model.RecipeChanges{Prices: []model.IngredientPrice{{ID: 1, Price: price}, {ID: 2, Price: price}, {ID: 1, Price: price}}}.Apply(recipe, substitutes)
+	assert.Equal(t, errs.ErrRepeatedPrice.At("prices[2].id"), err)
+}
+
+func TestPriceChangeApply(t *testing.T) {
+
+	price, percentage := 0.02, -25.0
+	newPrice, err := model.PriceChange{ID: 1, Price: &price}.Apply(0.01)
+	require.NoError(t, err)
+	assert.Equal(t, 0.02, newPrice)
+	newPrice, err = model.PriceChange{ID: 1, Percentage: &percentage}.Apply(0.04)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.03, newPrice, 0.000001)
+	_, err = model.PriceChange{ID: 1}.Apply(0.04)
+	assert.Equal(t, errs.ErrBadPriceChange, err)
+}
+
+func TestNewSearchTerms(t *testing.T) {
+
+	terms, err := model.NewSearchTerms(` Crème "brûlée" OR*`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"crème", "brûlée", "or"}, terms)
+	_, err = model.NewSearchTerms(" - ")
+	assert.Equal(t, errs.ErrBadQuery, err)
+}
+
+func TestParseQuantity(t *testing.T) {
+	for quantity, expected := range map[string]model.Quantity{
+		"1,5 kg":    {Amount: 1.5, Unit: model.Kilogram},
+		"500g":      {Amount: 500, Unit: model.Gram},
+		"250":       {Amount: 250, Unit: model.Gram},
+		" 2 Units ": {Amount: 2, Unit: model.Units},
+		"0.75 L":    {Amount: 0.75, Unit: model.Liter},
+	} {
+		parsed, err := model.ParseQuantity(quantity)
+		require.NoError(t, err, quantity)
+		assert.Equal(t, expected, parsed, quantity)
+	}
+	for _, quantity := range []string{"", "kg", "-1 kg", "2 cups", "1,5,0 kg"} {
+		_, err := model.ParseQuantity(quantity)
+		assert.Equal(t, errs.ErrBadQuantity, err, quantity)
+	}
+	grams, err := model.Quantity{Amount: 1.5, Unit: model.Kilogram}.Grams()
+	require.NoError(t, err)
+	assert.Equal(t, 1500.0, grams)
+	_, err = model.Quantity{Amount: 1, Unit: model.Liter}.Grams()
+	assert.Equal(t, errs.ErrBadUnit, err)
+}
+
+func TestMatchName(t *testing.T) {
+	names := []string{"tomato", "olive oil", "potato"}
+	for name, expected := range map[string]int{
+		"Tomato":       0,
+		"tomatoes":     0,
+		" Olive  Oil ": 1,
+		"olive oli":    1,
+		"potatoe":      2,
+	} {
+		index, err := model.MatchName(name, names)
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, index, name)
+	}
+	_, err := model.MatchName("sunflower oil", names)
+	assert.Equal(t, errs.ErrUnmatchedIngr, err)
+}
+
+func TestNewRecipeCard(t *testing.T) {
+	card := model.NewRecipeCard(model.RecipeView{
+		ID:       1,
+		Name:     "flan",
+		Price:    6,
+		Portions: 4,
+		Ingredients: []model.RecipeIngredientView{
+			{ID: 1, Name: "milk", Unit: model.Gram, Price: 0.004, Units: 500, Allergens: []model.Allergen{model.Milk}},
+			{ID: 2, Name: "sugar", Unit: model.Gram, Price: 0.0012, Units: 1250, Allergens: []model.Allergen{}},
+		},
+	})
+	assert.Equal(t, model.RecipeCardIngredient{
+		Name:      "sugar",
+		Quantity:  model.Quantity{Amount: 1.25, Unit: model.Kilogram},
+		UnitCost:  1.2,
+		CostUnit:  model.Kilogram,
+		Cost:      1.5,
+		Allergens: []model.Allergen{},
+	}, card.Ingredients[1])
+	assert.Equal(t, 3.5, card.Cost)
+	assert.Equal(t, 0.88, card.PortionCost)
+	assert.Equal(t, 58.3, card.FoodCostPercentage)
+	assert.Equal(t, []model.Allergen{model.Milk}, card.Allergens)
 }
@@ -0,0 +1,177 @@
+package model
+
+import (
+	"costly/core/errs"
+	"math"
+)
+
+const kilojoulesPerKilocalorie = 4.184
+
+// Nutrition holds nutritional values per 100 g when attached to an ingredient,
+// and absolute values when computed for a recipe or a portion of it. Energy is
+// expressed in kcal and the rest of the values in grams.
+type Nutrition struct {
+	Energy       float64 `json:"energy_kcal"`
+	Protein      float64 `json:"protein"`
+	Fat          float64 `json:"fat"`
+	SaturatedFat float64 `json:"saturated_fat"`
+	Carbohydrate float64 `json:"carbohydrate"`
+	Sugar        float64 `json:"sugar"`
+	Salt         float64 `json:"salt"`
+	Fibre        float64 `json:"fibre"`
+}
+
+// Validate reports every invalid value at once, each one at its path in the
+// nutrition of an ingredient.
+func (n Nutrition) Validate() error {
+	var validation errs.ValidationErrors
+	values := []struct {
+		field string
+		value float64
+	}{
+		{"energy_kcal", n.Energy}, {"protein", n.Protein}, {"fat", n.Fat}, {"saturated_fat", n.SaturatedFat},
+		{"carbohydrate", n.Carbohydrate}, {"sugar", n.Sugar}, {"salt", n.Salt}, {"fibre", n.Fibre},
+	}
+	for _, v := range values {
+		if v.value < 0 {
+			validation.Add(errs.ErrBadNutrition.At("nutrition." + v.field))
+		}
+	}
+	if n.SaturatedFat > n.Fat {
+		validation.Add(errs.ErrBadNutrition.At("nutrition.saturated_fat"))
+	}
+	if n.Sugar > n.Carbohydrate {
+		validation.Add(errs.ErrBadNutrition.At("nutrition.sugar"))
+	}
+	return validation.Err()
+}
+
+func (n Nutrition) Add(other Nutrition) Nutrition {
+	return Nutrition{
+		Energy:       n.Energy + other.Energy,
+		Protein:      n.Protein + other.Protein,
+		Fat:          n.Fat + other.Fat,
+		SaturatedFat: n.SaturatedFat + other.SaturatedFat,
+		Carbohydrate: n.Carbohydrate + other.Carbohydrate,
+		Sugar:        n.Sugar + other.Sugar,
+		Salt:         n.Salt + other.Salt,
+		Fibre:        n.Fibre + other.Fibre,
+	}
+}
+
+func (n Nutrition) Scale(factor float64) Nutrition {
+	return Nutrition{
+		Energy:       n.Energy * factor,
+		Protein:      n.Protein * factor,
+		Fat:          n.Fat * factor,
+		SaturatedFat: n.SaturatedFat * factor,
+		Carbohydrate: n.Carbohydrate * factor,
+		Sugar:        n.Sugar * factor,
+		Salt:         n.Salt * factor,
+		Fibre:        n.Fibre * factor,
+	}
+}
+
+// Weight returns the weight of the recipe in grams.
+func (recipe *RecipeView) Weight() float64 {
+	weight := 0.0
+	for _, ingredient := range recipe.Ingredients {
+		weight += float64(ingredient.Units)
+	}
+	return weight
+}
+
+// Nutrition returns the nutrition of the whole recipe. As with Cost, every
+// ingredient contributes proportionally to its units, which are grams while
+// nutrition is given per 100 g.
+func (recipe *RecipeView) Nutrition() Nutrition {
+	nutrition := Nutrition{}
+	for _, ingredient := range recipe.Ingredients {
+		nutrition = nutrition.Add(ingredient.Nutrition.Scale(float64(ingredient.Units) / 100))
+	}
+	return nutrition
+}
+
+func (recipe *RecipeView) PortionNutrition() Nutrition {
+	return recipe.Nutrition().Scale(1 / float64(recipe.portions()))
+}
+
+func (recipe *RecipeView) portions() int {
+	if recipe.Portions <= 0 {
+		return 1
+	}
+	return recipe.Portions
+}
+
+// NutritionFacts are the values printed in a nutrition declaration, rounded
+// following the EU guidance on tolerances and rounding.
+type NutritionFacts struct {
+	EnergyKJ float64 `json:"energy_kj"`
+	Nutrition
+}
+
+func NewNutritionFacts(nutrition Nutrition) NutritionFacts {
+	return NutritionFacts{
+		EnergyKJ: math.Round(nutrition.Energy * kilojoulesPerKilocalorie),
+		Nutrition: Nutrition{
+			Energy:       math.Round(nutrition.Energy),
+			Protein:      roundGrams(nutrition.Protein),
+			Fat:          roundGrams(nutrition.Fat),
+			SaturatedFat: roundGrams(nutrition.SaturatedFat),
+			Carbohydrate: roundGrams(nutrition.Carbohydrate),
+			Sugar:        roundGrams(nutrition.Sugar),
+			Salt:         roundSalt(nutrition.Salt),
+			Fibre:        roundGrams(nutrition.Fibre),
+		},
+	}
+}
+
+func roundGrams(grams float64) float64 {
+	if grams >= 10 {
+		return math.Round(grams)
+	}
+	if grams < 0.5 {
+		return 0
+	}
+	return math.Round(grams*10) / 10
+}
+
+func roundSalt(grams float64) float64 {
+	if grams >= 1 {
+		return math.Round(grams*10) / 10
+	}
+	if grams < 0.0125 {
+		return 0
+	}
+	return math.Round(grams*100) / 100
+}
+
+// NutritionLabel is a label-ready nutrition declaration of a recipe.
+type NutritionLabel struct {
+	RecipeID      int64          `json:"recipe_id"`
+	Name          string         `json:"name"`
+	Portions      int            `json:"portions"`
+	NetWeight     float64        `json:"net_weight"`
+	PortionWeight float64        `json:"portion_weight"`
+	Per100g       NutritionFacts `json:"per_100g"`
+	PerPortion    NutritionFacts `json:"per_portion"`
+	Allergens     []Allergen     `json:"allergens"`
+}
+
+func NewNutritionLabel(recipe RecipeView) NutritionLabel {
+	weight := recipe.Weight()
+	per100g := Nutrition{}
+	if weight > 0 {
+		per100g = recipe.Nutrition().Scale(100 / weight)
+	}
+	return NutritionLabel{
+		RecipeID:      recipe.ID,
+		Name:          recipe.Name,
+		Portions:      recipe.portions(),
+		NetWeight:     weight,
+		PortionWeight: weight / float64(recipe.portions()),
+		Per100g:       NewNutritionFacts(per100g),
+		PerPortion:    NewNutritionFacts(recipe.PortionNutrition()),
+		Allergens:     recipe.Allergens(),
+	}
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"costly/core/errs"
+	"slices"
+)
+
+type SortField string
+
+const (
+	SortByName         SortField = "name"
+	SortByPrice        SortField = "price"
+	SortByCost         SortField = "cost"
+	SortByLastModified SortField = "last_modified"
+)
+
+type Order string
+
+const (
+	Ascending  Order = "asc"
+	Descending Order = "desc"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 100
+)
+
+// PageOptions select a page of a list sorted by a field, and then by id to
+// break ties. Cursor is the one returned with the previous page, if any.
+type PageOptions struct {
+	Limit  int
+	Cursor string
+	SortBy SortField
+	Order  Order
+}
+
+// NewPageOptions validates the options against the fields the list can be
+// sorted by. A limit of 0 takes the default one, and lists without a sort field
+// are sorted by id alone, in ascending order unless told otherwise.
+func NewPageOptions(limit int, cursor string, sortBy SortField, order Order, sortable []SortField) (PageOptions, error) {
+	if limit == 0 {
+		limit = DefaultPageLimit
+	}
+	if limit < 0 || limit > MaxPageLimit {
+		return PageOptions{}, errs.ErrBadLimit
+	}
+	if sortBy != "" && !slices.Contains(sortable, sortBy) {
+		return PageOptions{}, errs.ErrBadSort
+	}
+	if order == "" {
+		order = Ascending
+	}
+	if order != Ascending && order != Descending {
+		return PageOptions{}, errs.ErrBadOrder
+	}
+	return PageOptions{Limit: limit, Cursor: cursor, SortBy: sortBy, Order: order}, nil
+}
+
+// Page is a page of a list. NextCursor gets the following page and is nil on
+// the last one.
+type Page[T any] struct {
+	Data       []T     `json:"data"`
+	NextCursor *string `json:"next_cursor"`
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+type RecipeRevisionIngredient struct {
+	ID    int64   `json:"id"`
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+	Units int     `json:"units"`
+}
+
+// RecipeRevision is an immutable snapshot of a recipe bill of materials, priced
+// at the moment it was taken. A new revision is made every time the recipe
+// changes, so sales can be costed against the recipe they were made with.
+type RecipeRevision struct {
+	ID          int64                      `json:"-"`
+	RecipeID    int64                      `json:"recipe_id"`
+	Version     int                        `json:"version"`
+	Name        string                     `json:"name"`
+	Portions    int                        `json:"portions"`
+	Ingredients []RecipeRevisionIngredient `json:"ingredients"`
+	Cost        float64                    `json:"cost"`
+	CreatedAt   time.Time                  `json:"created_at"`
+}
+
+// NewRecipeRevision snapshots the recipe. Its version is given when stored.
+func NewRecipeRevision(recipe RecipeView, now time.Time) *RecipeRevision {
+	ingredients := []RecipeRevisionIngredient{}
+	for _, ingredient := range recipe.Ingredients {
+		ingredients = append(ingredients, RecipeRevisionIngredient{
+			ID:    ingredient.ID,
+			Name:  ingredient.Name,
+			Price: ingredient.Price,
+			Units: ingredient.Units,
+		})
+	}
+	return &RecipeRevision{
+		ID:          -1,
+		RecipeID:    recipe.ID,
+		Name:        recipe.Name,
+		Portions:    recipe.Portions,
+		Ingredients: ingredients,
+		Cost:        recipe.Cost(),
+		CreatedAt:   now,
+	}
+}
+
+type Change[T comparable] struct {
+	From T `json:"from"`
+	To   T `json:"to"`
+}
+
+type RecipeRevisionIngredientChange struct {
+	ID    int64           `json:"id"`
+	Name  string          `json:"name"`
+	Units Change[int]     `json:"units"`
+	Price Change[float64] `json:"price"`
+}
+
+// RecipeRevisionDiff is what changed between two revisions of a recipe. Name
+// and portions are only present when they changed.
+type RecipeRevisionDiff struct {
+	RecipeID int64                            `json:"recipe_id"`
+	From     int                              `json:"from"`
+	To       int                              `json:"to"`
+	Name     *Change[string]                  `json:"name,omitempty"`
+	Portions *Change[int]                     `json:"portions,omitempty"`
+	Cost     Change[float64]                  `json:"cost"`
+	Added    []RecipeRevisionIngredient       `json:"added"`
+	Removed  []RecipeRevisionIngredient       `json:"removed"`
+	Changed  []RecipeRevisionIngredientChange `json:"changed"`
+}
+
+func NewRecipeRevisionDiff(from RecipeRevision, to RecipeRevision) RecipeRevisionDiff {
+	diff := RecipeRevisionDiff{
+		RecipeID: to.RecipeID,
+		From:     from.Version,
+		To:       to.Version,
+		Cost:     Change[float64]{From: from.Cost, To: to.Cost},
+		Added:    []RecipeRevisionIngredient{},
+		Removed:  []RecipeRevisionIngredient{},
+		Changed:  []RecipeRevisionIngredientChange{},
+	}
+	if from.Name != to.Name {
+		diff.Name = &Change[string]{From: from.Name, To: to.Name}
+	}
+	if from.Portions != to.Portions {
+		diff.Portions = &Change[int]{From: from.Portions, To: to.Portions}
+	}
+	for _, toIngredient := range to.Ingredients {
+		i := slices.IndexFunc(from.Ingredients, func(ingredient RecipeRevisionIngredient) bool { return ingredient.ID == toIngredient.ID })
+		if i < 0 {
+			diff.Added = append(diff.Added, toIngredient)
+			continue
+		}
+		fromIngredient := from.Ingredients[i]
+		if fromIngredient.Units != toIngredient.Units || fromIngredient.Price != toIngredient.Price {
+			diff.Changed = append(diff.Changed, RecipeRevisionIngredientChange{
+				ID:    toIngredient.ID,
+				Name:  toIngredient.Name,
+				Units: Change[int]{From: fromIngredient.Units, To: toIngredient.Units},
+				Price: Change[float64]{From: fromIngredient.Price, To: toIngredient.Price},
+			})
+		}
+	}
+	for _, fromIngredient := range from.Ingredients {
+		if !slices.ContainsFunc(to.Ingredients, func(ingredient RecipeRevisionIngredient) bool { return ingredient.ID == fromIngredient.ID }) {
+			diff.Removed = append(diff.Removed, fromIngredient)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"costly/core/errs"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Quantity is an amount expressed in a given unit.
+type Quantity struct {
+	Amount float64 `json:"amount"`
+	Unit   Unit    `json:"unit"`
+}
+
+// Normalize expresses the quantity in the most readable unit of its dimension,
+// so 1500 gr become 1.5 kg and 0.25 L become 250 ml.
+func (q Quantity) Normalize() Quantity {
+	switch {
+	case q.Unit == Gram && q.Amount >= 1000:
+		return Quantity{Amount: roundQuantity(q.Amount / 1000), Unit: Kilogram}
+	case q.Unit == Kilogram && q.Amount < 1:
+		return Quantity{Amount: roundQuantity(q.Amount * 1000), Unit: Gram}
+	case q.Unit == Milliliter && q.Amount >= 1000:
+		return Quantity{Amount: roundQuantity(q.Amount / 1000), Unit: Liter}
+	case q.Unit == Liter && q.Amount < 1:
+		return Quantity{Amount: roundQuantity(q.Amount * 1000), Unit: Milliliter}
+	}
+	return Quantity{Amount: roundQuantity(q.Amount), Unit: q.Unit}
+}
+
+func roundQuantity(amount float64) float64 {
+	return math.Round(amount*1000) / 1000
+}
+
+var unitNames = map[string]Unit{
+	"":          Gram,
+	"g":         Gram,
+	"gr":        Gram,
+	"grs":       Gram,
+	"gram":      Gram,
+	"grams":     Gram,
+	"kg":        Kilogram,
+	"kgs":       Kilogram,
+	"kilo":      Kilogram,
+	"kilos":     Kilogram,
+	"kilogram":  Kilogram,
+	"kilograms": Kilogram,
+	"l":         Liter,
+	"lt":        Liter,
+	"liter":     Liter,
+	"liters":    Liter,
+	"litre":     Liter,
+	"litres":    Liter,
+	"ml":        Milliliter,
+	"u":         Units,
+	"unit":      Units,
+	"units":     Units,
+}
+
+// ParseQuantity parses an amount followed by its unit as written by hand, such
+// as "1,5 kg", "500g" or "2 units". An amount without unit is in grams.
+func ParseQuantity(quantity string) (Quantity, error) {
+	quantity = strings.TrimSpace(quantity)
+	split := strings.LastIndexFunc(quantity, func(r rune) bool {
+		return unicode.IsDigit(r) || r == '.' || r == ','
+	})
+	if split < 0 {
+		return Quantity{}, errs.ErrBadQuantity
+	}
+	amount, err := ParseDecimal(quantity[:split+1])
+	if err != nil || amount <= 0 {
+		return Quantity{}, errs.ErrBadQuantity
+	}
+	unit, ok := unitNames[strings.ToLower(strings.TrimSpace(quantity[split+1:]))]
+	if !ok {
+		return Quantity{}, errs.ErrBadQuantity
+	}
+	return Quantity{Amount: amount, Unit: unit}, nil
+}
+
+// Grams is the quantity in grams, which is the unit of every ingredient. Only
+// quantities of weight can be expressed in grams.
+func (q Quantity) Grams() (float64, error) {
+	switch q.Unit {
+	case Gram:
+		return q.Amount, nil
+	case Kilogram:
+		return q.Amount * 1000, nil
+	}
+	return 0, errs.ErrBadUnit
+}
+
+type ScaledIngredient struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Quantity
+	Cost float64 `json:"cost"`
+}
+
+// ScaledRecipe is a recipe resized to another yield. It is never stored.
+type ScaledRecipe struct {
+	RecipeID    int64              `json:"recipe_id"`
+	Name        string             `json:"name"`
+	Factor      float64            `json:"factor"`
+	Portions    float64            `json:"portions"`
+	Weight      float64            `json:"weight"`
+	Ingredients []ScaledIngredient `json:"ingredients"`
+	Cost        float64            `json:"cost"`
+}
+
+// ScaleToPortions resizes the recipe so it yields the given portions.
+func (recipe *RecipeView) ScaleToPortions(portions int) (*ScaledRecipe, error) {
+	if portions <= 0 {
+		return &ScaledRecipe{}, errs.ErrBadPortions
+	}
+	return recipe.scale(float64(portions) / float64(recipe.portions())), nil
+}
+
+// ScaleToWeight resizes the recipe so it yields the given weight in grams.
+func (recipe *RecipeView) ScaleToWeight(weight float64) (*ScaledRecipe, error) {
+	if weight <= 0 || math.IsNaN(weight) || math.IsInf(weight, 0) || recipe.Weight() == 0 {
+		return &ScaledRecipe{}, errs.ErrBadYield
+	}
+	return recipe.scale(weight / recipe.Weight()), nil
+}
+
+func (recipe *RecipeView) scale(factor float64) *ScaledRecipe {
+	ingredients := []ScaledIngredient{}
+	for _, ingredient := range recipe.Ingredients {
+		units := float64(ingredient.Units) * factor
+		ingredients = append(ingredients, ScaledIngredient{
+			ID:       ingredient.ID,
+			Name:     ingredient.Name,
+			Quantity: Quantity{Amount: units, Unit: ingredient.Unit}.Normalize(),
+			Cost:     ingredient.Price * units,
+		})
+	}
+	return &ScaledRecipe{
+		RecipeID:    recipe.ID,
+		Name:        recipe.Name,
+		Factor:      factor,
+		Portions:    float64(recipe.portions()) * factor,
+		Weight:      recipe.Weight() * factor,
+		Ingredients: ingredients,
+		Cost:        recipe.Cost() * factor,
+	}
+}
@@ -0,0 +1,122 @@
+package model
+
+import "costly/core/errs"
+
+// PriceChange sets the price of an ingredient either to an absolute Price or
+// changing it by a Percentage of the current one, such as 10 or -5.
+type PriceChange struct {
+	ID         int64    `json:"id"`
+	Price      *float64 `json:"price"`
+	Percentage *float64 `json:"percentage"`
+}
+
+func (change PriceChange) Apply(price float64) (float64, error) {
+	if (change.Price == nil) == (change.Percentage == nil) {
+		return 0, errs.ErrBadPriceChange
+	}
+	if change.Price != nil {
+		price = *change.Price
+	} else {
+		price *= 1 + *change.Percentage/100
+	}
+	if price <= 0 {
+		return 0, errs.ErrBadPrice
+	}
+	return price, nil
+}
+
+type RecipeImpact struct {
+	RecipeID    int64         `json:"recipe_id"`
+	Name        string        `json:"name"`
+	Price       float64       `json:"price"`
+	Current     RecipeCosting `json:"current"`
+	Simulated   RecipeCosting `json:"simulated"`
+	CostDelta   float64       `json:"cost_delta"`
+	MarginDelta float64       `json:"margin_delta"`
+	// SoldUnits are the units sold in the period the impact is weighted by.
+	SoldUnits           int     `json:"sold_units"`
+	WeightedCostDelta   float64 `json:"weighted_cost_delta"`
+	WeightedMarginDelta float64 `json:"weighted_margin_delta"`
+}
+
+type MenuImpact struct {
+	MenuID                      int64   `json:"menu_id"`
+	Name                        string  `json:"name"`
+	CurrentCost                 float64 `json:"current_cost"`
+	SimulatedCost               float64 `json:"simulated_cost"`
+	CurrentFoodCostPercentage   float64 `json:"current_food_cost_percentage"`
+	SimulatedFoodCostPercentage float64 `json:"simulated_food_cost_percentage"`
+}
+
+// PriceShock is the impact of some ingredient price changes on every recipe and
+// menu. Totals add one unit of every recipe, while weighted totals add the
+// units sold of them.
+type PriceShock struct {
+	Recipes             []RecipeImpact `json:"recipes"`
+	Menus               []MenuImpact   `json:"menus"`
+	CostDelta           float64        `json:"cost_delta"`
+	MarginDelta         float64        `json:"margin_delta"`
+	SoldUnits           int            `json:"sold_units"`
+	WeightedCostDelta   float64        `json:"weighted_cost_delta"`
+	WeightedMarginDelta float64        `json:"weighted_margin_delta"`
+}
+
+// NewPriceShock applies the price changes to the ingredients of all recipes,
+// which must include all the recipes in the menus.
+func NewPriceShock(recipes []RecipeView, menus []Menu, changes []PriceChange, soldUnits map[int64]int) (PriceShock, error) {
+	changesByID := map[int64]PriceChange{}
+	for _, change := range changes {
+		// Validated up front so that changes to unused ingredients fail too.
+		if _, err := change.Apply(1); err != nil {
+			return PriceShock{}, err
+		}
+		changesByID[change.ID] = change
+	}
+	shock := PriceShock{Recipes: []RecipeImpact{}, Menus: []MenuImpact{}}
+	current, simulated := map[int64]RecipeView{}, map[int64]RecipeView{}
+	for _, recipe := range recipes {
+		shocked := recipe
+		shocked.Ingredients = []RecipeIngredientView{}
+		for _, ingredient := range recipe.Ingredients {
+			if change, ok := changesByID[ingredient.ID]; ok {
+				price, err := change.Apply(ingredient.Price)
+				if err != nil {
+					return PriceShock{}, err
+				}
+				ingredient.Price = price
+			}
+			shocked.Ingredients = append(shocked.Ingredients, ingredient)
+		}
+		impact := RecipeImpact{
+			RecipeID:  recipe.ID,
+			Name:      recipe.Name,
+			Price:     recipe.Price,
+			Current:   NewRecipeCosting(recipe),
+			Simulated: NewRecipeCosting(shocked),
+			SoldUnits: soldUnits[recipe.ID],
+		}
+		impact.CostDelta = impact.Simulated.Cost - impact.Current.Cost
+		impact.MarginDelta = impact.Simulated.Margin - impact.Current.Margin
+		impact.WeightedCostDelta = impact.CostDelta * float64(impact.SoldUnits)
+		impact.WeightedMarginDelta = impact.MarginDelta * float64(impact.SoldUnits)
+		shock.Recipes = append(shock.Recipes, impact)
+		shock.CostDelta += impact.CostDelta
+		shock.MarginDelta += impact.MarginDelta
+		shock.SoldUnits += impact.SoldUnits
+		shock.WeightedCostDelta += impact.WeightedCostDelta
+		shock.WeightedMarginDelta += impact.WeightedMarginDelta
+		current[recipe.ID], simulated[recipe.ID] = recipe, shocked
+	}
+	for _, menu := range menus {
+		currentCosting, simulatedCosting := NewMenuCosting(menu, current), NewMenuCosting(menu, simulated)
+		shock.Menus = append(shock.Menus, MenuImpact{
+			MenuID:                      menu.ID,
+			Name:                        menu.Name,
+			CurrentCost:                 currentCosting.Cost,
+			SimulatedCost:               simulatedCosting.Cost,
+			CurrentFoodCostPercentage:   currentCosting.FoodCostPercentage,
+			SimulatedFoodCostPercentage: simulatedCosting.FoodCostPercentage,
+		})
+	}
+	return shock, nil
+}
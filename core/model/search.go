@@ -0,0 +1,32 @@
+package model
+
+import (
+	"costly/core/errs"
+	"strings"
+	"unicode"
+)
+
+type SearchKind string
+
+const (
+	IngredientResult SearchKind = "ingredient"
+	RecipeResult     SearchKind = "recipe"
+)
+
+type SearchResult struct {
+	Kind SearchKind `json:"kind"`
+	ID   int64      `json:"id"`
+	Name string     `json:"name"`
+}
+
+// NewSearchTerms splits a search query into lower case words, leaving out any
+// punctuation so that it can not be taken as search syntax.
+func NewSearchTerms(query string) ([]string, error) {
+	terms := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(terms) == 0 {
+		return nil, errs.ErrBadQuery
+	}
+	return terms, nil
+}
@@ -0,0 +1,152 @@
+package model
+
+import (
+	"costly/core/errs"
+	"fmt"
+	"slices"
+)
+
+// IngredientSubstitution swaps an ingredient of a recipe for another one. Units
+// are the units of the substitute, the same as the substituted if not given.
+type IngredientSubstitution struct {
+	ID           int64 `json:"id"`
+	SubstituteID int64 `json:"substitute_id"`
+	Units        *int  `json:"units"`
+}
+
+type IngredientPrice struct {
+	ID    int64   `json:"id"`
+	Price float64 `json:"price"`
+}
+
+// RecipeChanges are hypothetical changes to a recipe. Substitutions are applied
+// first, then quantities and finally prices, so quantities and prices may refer
+// to substitutes. A substitute already in the recipe gets the units of the
+// substitution added to its own, and a quantity of 0 units removes the
+// ingredient. The price of an ingredient can only be changed once.
+type RecipeChanges struct {
+	Substitutions []IngredientSubstitution `json:"substitutions"`
+	Quantities    []RecipeIngredient       `json:"quantities"`
+	Prices        []IngredientPrice        `json:"prices"`
+}
+
+// Apply returns the ingredients of the recipe with the changes applied, given
+// the substitutes by id.
+func (changes RecipeChanges) Apply(recipe RecipeView, substitutes map[int64]RecipeIngredientView) ([]RecipeIngredientView, error) {
+	ingredients := append([]RecipeIngredientView{}, recipe.Ingredients...)
+	find := func(id int64) int {
+		for i := range ingredients {
+			if ingredients[i].ID == id {
+				return i
+			}
+		}
+		return -1
+	}
+	for _, substitution := range changes.Substitutions {
+		i := find(substitution.ID)
+		if i < 0 {
+			return nil, errs.ErrBadRecipeIngr
+		}
+		substitute, ok := substitutes[substitution.SubstituteID]
+		if !ok {
+			return nil, errs.ErrBadIngredient
+		}
+		substitute.Units = ingredients[i].Units
+		if substitution.Units != nil {
+			if *substitution.Units <= 0 {
+				return nil, errs.ErrBadStockUnits
+			}
+			substitute.Units = *substitution.Units
+		}
+		if j := find(substitute.ID); j >= 0 && j != i {
+			ingredients[j].Units += substitute.Units
+			ingredients = slices.Delete(ingredients, i, i+1)
+		} else {
+			ingredients[i] = substitute
+		}
+	}
+	for _, quantity := range changes.Quantities {
+		i := find(quantity.ID)
+		if i < 0 {
+			return nil, errs.ErrBadRecipeIngr
+		}
+		if quantity.Units < 0 {
+			return nil, errs.ErrBadStockUnits
+		}
+		ingredients[i].Units = quantity.Units
+	}
+	for i, price := range changes.Prices {
+		if slices.ContainsFunc(changes.Prices[:i], func(previous IngredientPrice) bool { return previous.ID == price.ID }) {
+			return nil, errs.ErrRepeatedPrice.At(fmt.Sprintf("prices[%d].id", i))
+		}
+	}
+	for _, price := range changes.Prices {
+		i := find(price.ID)
+		if i < 0 {
+			return nil, errs.ErrBadRecipeIngr
+		}
+		if price.Price <= 0 {
+			return nil, errs.ErrBadPrice
+		}
+		ingredients[i].Price = price.Price
+	}
+	kept := []RecipeIngredientView{}
+	for _, ingredient := range ingredients {
+		if ingredient.Units > 0 {
+			kept = append(kept, ingredient)
+		}
+	}
+	if len(kept) == 0 {
+		return nil, errs.ErrBadIngrs
+	}
+	return kept, nil
+}
+
+type RecipeCosting struct {
+	Cost float64 `json:"cost"`
+	// Margin is what is left of the recipe price after its cost.
+	Margin             float64 `json:"margin"`
+	FoodCostPercentage float64 `json:"food_cost_percentage"`
+}
+
+func NewRecipeCosting(recipe RecipeView) RecipeCosting {
+	cost := recipe.Cost()
+	return RecipeCosting{
+		Cost:               cost,
+		Margin:             recipe.Price - cost,
+		FoodCostPercentage: recipe.FoodCostPercentage(),
+	}
+}
+
+// RecipeSimulation compares the current costing of a recipe with the one it
+// would have after some hypothetical changes.
+type RecipeSimulation struct {
+	RecipeID    int64                  `json:"recipe_id"`
+	Name        string                 `json:"name"`
+	Price       float64                `json:"price"`
+	Ingredients []RecipeIngredientView `json:"ingredients"`
+	Current     RecipeCosting          `json:"current"`
+	Simulated   RecipeCosting          `json:"simulated"`
+	CostDelta   float64                `json:"cost_delta"`
+	MarginDelta float64                `json:"margin_delta"`
+}
+
+func NewRecipeSimulation(recipe RecipeView, changes RecipeChanges, substitutes map[int64]RecipeIngredientView) (RecipeSimulation, error) {
+	ingredients, err := changes.Apply(recipe, substitutes)
+	if err != nil {
+		return RecipeSimulation{}, err
+	}
+	simulated := recipe
+	simulated.Ingredients = ingredients
+	simulation := RecipeSimulation{
+		RecipeID:    recipe.ID,
+		Name:        recipe.Name,
+		Price:       recipe.Price,
+		Ingredients: ingredients,
+		Current:     NewRecipeCosting(recipe),
+		Simulated:   NewRecipeCosting(simulated),
+	}
+	simulation.CostDelta = simulation.Simulated.Cost - simulation.Current.Cost
+	simulation.MarginDelta = simulation.Simulated.Margin - simulation.Current.Margin
+	return simulation, nil
+}
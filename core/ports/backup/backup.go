@@ -0,0 +1,120 @@
+package backup
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	"costly/core/ports/database"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	namePrefix = "costly-"
+	nameSuffix = ".db"
+	timeLayout = "20060102-150405.000"
+	// legacyTimeLayout names the backups created before names had milliseconds.
+	legacyTimeLayout = "20060102-150405"
+)
+
+// ErrExists is returned rather than overwriting a backup created at the same
+// millisecond.
+var ErrExists = errs.NewConflictError("backup", "", "a backup was already created at that moment")
+
+type Backups interface {
+	// Create snapshots the database and then deletes the oldest backups but the
+	// retained ones. Backups are named after the millisecond they are created
+	// at, so a second one at the same millisecond fails with ErrExists.
+	Create(ctx context.Context, at time.Time) (model.Backup, error)
+	// List gets the backups there are, newest first.
+	List(ctx context.Context) ([]model.Backup, error)
+}
+
+type diskBackups struct {
+	db        database.Database
+	dir       string
+	retention int
+}
+
+// New returns Backups that keeps the newest retention snapshots of db as files
+// under dir.
+func New(db database.Database, dir string, retention int) Backups {
+	return &diskBackups{db, dir, retention}
+}
+
+func (b *diskBackups) Create(ctx context.Context, at time.Time) (model.Backup, error) {
+	if err := os.MkdirAll(b.dir, 0o755); err != nil {
+		return model.Backup{}, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	name := namePrefix + at.UTC().Format(timeLayout) + nameSuffix
+	path := filepath.Join(b.dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return model.Backup{}, ErrExists
+	} else if !os.IsNotExist(err) {
+		return model.Backup{}, err
+	}
+	if err := database.Snapshot(ctx, b.db, path); err != nil {
+		return model.Backup{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return model.Backup{}, err
+	}
+	if err := b.prune(ctx); err != nil {
+		return model.Backup{}, err
+	}
+	return model.Backup{Name: name, Size: info.Size(), CreatedAt: at.UTC().Truncate(time.Millisecond)}, nil
+}
+
+func (b *diskBackups) List(ctx context.Context) ([]model.Backup, error) {
+	entries, err := os.ReadDir(b.dir)
+	if os.IsNotExist(err) {
+		return []model.Backup{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	backups := []model.Backup{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, namePrefix) || !strings.HasSuffix(name, nameSuffix) {
+			continue
+		}
+		createdAt, err := createdAtOf(name)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, model.Backup{Name: name, Size: info.Size(), CreatedAt: createdAt})
+	}
+	slices.SortFunc(backups, func(a, b model.Backup) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return backups, nil
+}
+
+// createdAtOf reads the moment a backup was created at from its name.
+func createdAtOf(name string) (time.Time, error) {
+	at := strings.TrimSuffix(strings.TrimPrefix(name, namePrefix), nameSuffix)
+	if createdAt, err := time.Parse(timeLayout, at); err == nil {
+		return createdAt, nil
+	}
+	return time.Parse(legacyTimeLayout, at)
+}
+
+func (b *diskBackups) prune(ctx context.Context) error {
+	backups, err := b.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, backup := range backups[min(b.retention, len(backups)):] {
+		if err := os.Remove(filepath.Join(b.dir, backup.Name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
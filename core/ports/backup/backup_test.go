@@ -0,0 +1,104 @@
+package backup_test
+
+import (
+	"context"
+	"costly/core/model"
+	"costly/core/ports/backup"
+	"costly/core/ports/database"
+	"costly/core/ports/logger"
+	ingredientrepo "costly/core/ports/repository/ingredient"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBackup(t *testing.T) {
+	logger, _ := logger.New("debug")
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("should snapshot the database", func(t *testing.T) {
+		db, err := database.New(filepath.Join(t.TempDir(), "costly.db"), logger)
+		require.NoError(t, err)
+		ingredient, _ := model.NewIngredient("meat", model.Gram, 0.01, now)
+		require.NoError(t, ingredientrepo.New(db).Add(ctx, ingredient))
+		dir := t.TempDir()
+
+		created, err := backup.New(db, dir, 7).Create(ctx, now)
+
+		require.NoError(t, err)
+		assert.Equal(t, "costly-20240301-120000.000.db", created.Name)
+		assert.Equal(t, now, created.CreatedAt)
+		assert.Positive(t, created.Size)
+		path := filepath.Join(dir, created.Name)
+		require.NoError(t, database.CheckIntegrity(ctx, path))
+		snapshot, err := database.New(path, logger)
+		require.NoError(t, err)
+		found, err := ingredientrepo.New(snapshot).Find(ctx, ingredient.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "meat", found.Name)
+	})
+
+	t.Run("should only keep the newest backups", func(t *testing.T) {
+		db, err := database.New(filepath.Join(t.TempDir(), "costly.db"), logger)
+		require.NoError(t, err)
+		backups := backup.New(db, t.TempDir(), 2)
+
+		for _, hours := range []int{0, 2, 1} {
+			_, err := backups.Create(ctx, now.Add(time.Duration(hours)*time.Hour))
+			require.NoError(t, err)
+		}
+
+		listed, err := backups.List(ctx)
+		require.NoError(t, err)
+		names := []string{}
+		for _, listed := range listed {
+			names = append(names, listed.Name)
+		}
+		assert.Equal(t, []string{"costly-20240301-140000.000.db", "costly-20240301-130000.000.db"}, names)
+	})
+
+	t.Run("should keep two backups created in a row", func(t *testing.T) {
+		db, err := database.New(filepath.Join(t.TempDir(), "costly.db"), logger)
+		require.NoError(t, err)
+		backups := backup.New(db, t.TempDir(), 7)
+
+		first, err := backups.Create(ctx, now)
+		require.NoError(t, err)
+		second, err := backups.Create(ctx, now.Add(250*time.Millisecond))
+		require.NoError(t, err)
+		_, err = backups.Create(ctx, now.Add(250*time.Millisecond))
+
+		assert.Equal(t, backup.ErrExists, err)
+		listed, err := backups.List(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []model.Backup{second, first}, listed)
+	})
+
+	t.Run("should list the backups named before names had milliseconds", func(t *testing.T) {
+		db, err := database.New(filepath.Join(t.TempDir(), "costly.db"), logger)
+		require.NoError(t, err)
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "costly-20240229-120000.db"), []byte("backup"), 0o644))
+
+		listed, err := backup.New(db, dir, 7).List(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, []model.Backup{{Name: "costly-20240229-120000.db", Size: 6, CreatedAt: now.AddDate(0, 0, -1)}}, listed)
+	})
+
+	t.Run("should list no backups before the first one", func(t *testing.T) {
+		db, err := database.New(filepath.Join(t.TempDir(), "costly.db"), logger)
+		require.NoError(t, err)
+
+		listed, err := backup.New(db, filepath.Join(t.TempDir(), "backups"), 7).List(ctx)
+
+		require.NoError(t, err)
+		assert.Empty(t, listed)
+	})
+}
@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Snapshot copies the database into a new file at path with the SQLite online
+// backup API, so it can keep being used meanwhile. The file only appears once
+// the copy is complete.
+func Snapshot(ctx context.Context, db Database, path string) error {
+	pool, ok := db.(*pooldb)
+	if !ok {
+		return fmt.Errorf("can not snapshot the database within a transaction")
+	}
+	tmpPath := path + ".tmp"
+	if err := snapshot(ctx, pool.sqlDB, tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to snapshot db: %w", err)
+	}
+	return os.Rename(tmpPath, path)
+}
+
+func snapshot(ctx context.Context, srcDB *sql.DB, path string) error {
+	destDB, err := sql.Open("sqlite3", "file:"+path)
+	if err != nil {
+		return err
+	}
+	defer destDB.Close()
+	destConn, err := destDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer destConn.Close()
+	srcConn, err := srcDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer srcConn.Close()
+	return destConn.Raw(func(dest any) error {
+		return srcConn.Raw(func(src any) error {
+			backup, err := dest.(*sqlite3.SQLiteConn).Backup("main", src.(*sqlite3.SQLiteConn), "main")
+			if err != nil {
+				return err
+			}
+			if _, err := backup.Step(-1); err != nil {
+				backup.Finish()
+				return err
+			}
+			return backup.Finish()
+		})
+	})
+}
+
+// CheckIntegrity runs PRAGMA integrity_check on the database file at path,
+// failing with the problems found if any.
+func CheckIntegrity(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	rows, err := db.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return fmt.Errorf("failed to check integrity of %s: %w", path, err)
+	}
+	defer rows.Close()
+	problems := []string{}
+	for rows.Next() {
+		var problem string
+		if err := rows.Scan(&problem); err != nil {
+			return err
+		}
+		if problem != "ok" {
+			problems = append(problems, problem)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to check integrity of %s: %w", path, err)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s is corrupt: %s", path, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Restore replaces the database of the connection string with the snapshot,
+// checking the integrity of a copy of it before swapping the files. It must not
+// be run while the database is open.
+func Restore(ctx context.Context, snapshotPath string, connectionString string) error {
+	tmpPath := connectionString + ".restoring"
+	if err := copyFile(snapshotPath, tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to copy snapshot: %w", err)
+	}
+	if err := CheckIntegrity(ctx, tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	// a journal left by the replaced database would be rolled back into the
+	// restored one
+	for _, suffix := range []string{"-journal", "-wal", "-shm"} {
+		if err := os.Remove(connectionString + suffix); err != nil && !os.IsNotExist(err) {
+			os.Remove(tmpPath)
+			return err
+		}
+	}
+	return os.Rename(tmpPath, connectionString)
+}
+
+func copyFile(srcPath string, destPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dest, err := os.Create(destPath)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dest, src); err != nil {
+		dest.Close()
+		return err
+	}
+	if err := dest.Sync(); err != nil {
+		dest.Close()
+		return err
+	}
+	return dest.Close()
+}
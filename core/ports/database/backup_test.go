@@ -0,0 +1,65 @@
+package database_test
+
+import (
+	"context"
+	"costly/core/ports/database"
+	"costly/core/ports/logger"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestore(t *testing.T) {
+	logger, _ := logger.New("debug")
+	ctx := context.Background()
+
+	t.Run("should replace the database with the snapshot", func(t *testing.T) {
+		dir := t.TempDir()
+		db, err := database.New(filepath.Join(dir, "costly.db"), logger)
+		require.NoError(t, err)
+		_, err = db.ExecContext(ctx, "INSERT INTO category (name, created_at) VALUES ('tapas', CURRENT_TIMESTAMP)")
+		require.NoError(t, err)
+		snapshotPath := filepath.Join(dir, "snapshot.db")
+		require.NoError(t, database.Snapshot(ctx, db, snapshotPath))
+		restoredPath := filepath.Join(dir, "restored.db")
+		require.NoError(t, os.WriteFile(restoredPath+"-journal", []byte("stale"), 0o644))
+
+		err = database.Restore(ctx, snapshotPath, restoredPath)
+
+		require.NoError(t, err)
+		assert.NoFileExists(t, restoredPath+"-journal")
+		restored, err := database.New(restoredPath, logger)
+		require.NoError(t, err)
+		var categories int
+		require.NoError(t, restored.QueryRowContext(ctx, "SELECT COUNT(*) FROM category").Scan(&categories))
+		assert.Equal(t, 5, categories)
+	})
+
+	t.Run("should keep the database if the snapshot is corrupt", func(t *testing.T) {
+		dir := t.TempDir()
+		snapshotPath := filepath.Join(dir, "snapshot.db")
+		require.NoError(t, os.WriteFile(snapshotPath, []byte("not a database"), 0o644))
+		path := filepath.Join(dir, "costly.db")
+		require.NoError(t, os.WriteFile(path, []byte("current"), 0o644))
+
+		err := database.Restore(ctx, snapshotPath, path)
+
+		require.Error(t, err)
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, "current", string(content))
+		assert.NoFileExists(t, path+".restoring")
+	})
+
+	t.Run("should fail if the snapshot does not exist", func(t *testing.T) {
+		dir := t.TempDir()
+
+		err := database.Restore(ctx, filepath.Join(dir, "missing.db"), filepath.Join(dir, "costly.db"))
+
+		require.Error(t, err)
+		assert.NoFileExists(t, filepath.Join(dir, "costly.db"))
+	})
+}
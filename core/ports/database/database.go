@@ -5,6 +5,7 @@ import (
 	"costly/core/ports/logger"
 	sql2 "costly/sql"
 	"database/sql"
+	"errors"
 	"fmt"
 )
 
@@ -27,6 +28,10 @@ func NewFromDatasource(datasourceName string, logger logger.Logger) (Database, e
 		return nil, fmt.Errorf("failed to ping db: %w", err)
 	}
 
+	if err := requireFTS5(db); err != nil {
+		return nil, err
+	}
+
 	_, err = sql2.RunMigrations(db, logger)
 	if err != nil {
 		return nil, fmt.Errorf("error running migrations: %w", err)
@@ -35,6 +40,23 @@ func NewFromDatasource(datasourceName string, logger logger.Logger) (Database, e
 	return newPoolDB(db), nil
 }
 
+// ErrNoFTS5 is returned when go-sqlite3 was built without FTS5, which the
+// search index is made of, as it is only built with the sqlite_fts5 tag.
+var ErrNoFTS5 = errors.New("SQLite was built without FTS5, build costly with -tags sqlite_fts5")
+
+// requireFTS5 fails before migrating if the search index can not be created,
+// rather than leaving the database half migrated.
+func requireFTS5(db *sql.DB) error {
+	var enabled bool
+	if err := db.QueryRowContext(context.Background(), "SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled); err != nil {
+		return fmt.Errorf("failed to check SQLite compile options: %w", err)
+	}
+	if !enabled {
+		return ErrNoFTS5
+	}
+	return nil
+}
+
 type pooldb struct {
 	sqlDB *sql.DB
 	dbSession
@@ -66,6 +88,8 @@ func (db *pooldb) WithTx(ctx context.Context, op func(tx Database) error) error
 type dbtx struct {
 	sqlTx *sql.Tx
 	dbSession
+	// savepoints is the number of savepoints open in the transaction.
+	savepoints int
 }
 
 func newTX(sqltx *sql.Tx) Database {
@@ -75,6 +99,24 @@ func newTX(sqltx *sql.Tx) Database {
 	}
 }
 
+// WithTx runs op within a savepoint of the transaction, so that when op fails
+// only its changes are rolled back and the transaction can go on.
 func (tx *dbtx) WithTx(ctx context.Context, op func(tx Database) error) error {
-	return op(tx)
+	tx.savepoints++
+	defer func() { tx.savepoints-- }()
+	savepoint := fmt.Sprintf("sp%d", tx.savepoints)
+	if _, err := tx.sqlTx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return fmt.Errorf("failed to start savepoint: %w", err)
+	}
+	if err := op(tx); err != nil {
+		if _, rbErr := tx.sqlTx.ExecContext(ctx, "ROLLBACK TO "+savepoint); rbErr != nil {
+			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
+		}
+		tx.sqlTx.ExecContext(ctx, "RELEASE "+savepoint)
+		return err
+	}
+	if _, err := tx.sqlTx.ExecContext(ctx, "RELEASE "+savepoint); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+	return nil
 }
@@ -35,13 +35,34 @@ func newDBSession(db sqlDBSession) dbSession {
 }
 
 func (db *databaseSession) QueryRowContext(ctx context.Context, query string, args ...any) RowScanner {
-	return db.session.QueryRowContext(ctx, query, args...)
+	return &translatedRow{row: db.session.QueryRowContext(ctx, query, args...)}
 }
 
 func (db *databaseSession) QueryContext(ctx context.Context, query string, args ...any) (RowsScanner, error) {
-	return db.session.QueryContext(ctx, query, args...)
+	rows, err := db.session.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return rows, nil
 }
 
 func (db *databaseSession) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
-	return db.session.ExecContext(ctx, query, args...)
+	result, err := db.session.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return result, nil
+}
+
+// translatedRow translates the errors of statements run by a single row
+// query, such as an update returning the updated row.
+type translatedRow struct {
+	row *sql.Row
+}
+
+func (r *translatedRow) Scan(dest ...any) error {
+	if err := r.row.Scan(dest...); err != nil {
+		return translateError(err)
+	}
+	return nil
 }
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"costly/core/errs"
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// translateError turns the constraint errors of the driver into typed errors:
+// a uniqueness violation into a conflict naming the entity and field, and a
+// foreign key violation into a reference error. SQLite does not tell which
+// foreign key failed, so the use cases check the references they can name
+// before writing, and this error is only the last line of defense.
+func translateError(err error) error {
+	sqlError, ok := err.(sqlite3.Error)
+	if !ok || sqlError.Code != sqlite3.ErrConstraint {
+		return err
+	}
+	switch sqlError.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		return conflictOf(sqlError)
+	case sqlite3.ErrConstraintForeignKey:
+		return errs.NewReferenceError("", "", "a referenced entity does not exist")
+	}
+	return err
+}
+
+// conflictOf reads the columns from messages such as "UNIQUE constraint
+// failed: recipe.name".
+func conflictOf(sqlError sqlite3.Error) error {
+	_, columns, _ := strings.Cut(sqlError.Error(), "constraint failed: ")
+	entity, fields := "", []string{}
+	for _, column := range strings.Split(columns, ", ") {
+		table, field, _ := strings.Cut(column, ".")
+		entity = entityOf(table)
+		fields = append(fields, field)
+	}
+	if len(fields) == 1 {
+		return errs.NewConflictError(entity, fields[0], fmt.Sprintf("%s with this %s already exists", withArticle(entity), fields[0]))
+	}
+	return errs.NewConflictError(entity, "", fmt.Sprintf("the %s already exists", entity))
+}
+
+// entityOf names the entity stored in the table, such as "recipe revision"
+// for recipe_revision.
+func entityOf(table string) string {
+	return strings.ReplaceAll(table, "_", " ")
+}
+
+// withArticle prefixes the entity with its indefinite article.
+func withArticle(entity string) string {
+	if strings.IndexAny(entity, "aeiou") == 0 {
+		return "an " + entity
+	}
+	return "a " + entity
+}
@@ -0,0 +1,59 @@
+package database_test
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/ports/database"
+	"costly/core/ports/logger"
+	"errors"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateErrors(t *testing.T) {
+	logger, _ := logger.New("debug")
+	ctx := context.Background()
+	db, err := database.NewFromDatasource(":memory:", logger)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "INSERT INTO recipe (name, created_at, last_modified) VALUES ('flan', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)")
+	require.NoError(t, err)
+
+	t.Run("should name the entity and field of a unique constraint", func(t *testing.T) {
+		_, err := db.ExecContext(ctx, "INSERT INTO category (name, created_at) VALUES (?, CURRENT_TIMESTAMP)", "starters")
+
+		assert.ErrorIs(t, err, errs.ErrConflict)
+		assert.Equal(t, errs.NewConflictError("category", "name", "a category with this name already exists"), err)
+	})
+
+	t.Run("should not name the field of a composite key", func(t *testing.T) {
+		_, err := db.ExecContext(ctx, "INSERT INTO recipe_tag (recipe_id, tag) VALUES (1, 'sweet'), (1, 'sweet')")
+
+		assert.Equal(t, errs.NewConflictError("recipe tag", "", "the recipe tag already exists"), err)
+	})
+
+	t.Run("should not name the entity nor the field of a reference", func(t *testing.T) {
+		_, err := db.ExecContext(ctx, "INSERT INTO recipe_ingredient (recipe_id, ingredient_id, units) VALUES (?, ?, ?)", 1, 123, 5)
+
+		assert.ErrorIs(t, err, errs.ErrBadReference)
+		assert.Equal(t, errs.NewReferenceError("", "", "a referenced entity does not exist"), err)
+	})
+
+	t.Run("should translate errors of transactions", func(t *testing.T) {
+		err := db.WithTx(ctx, func(tx database.Database) error {
+			_, err := tx.ExecContext(ctx, "INSERT INTO recipe (name, created_at, last_modified) VALUES (?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)", "flan")
+			return err
+		})
+
+		assert.Equal(t, errs.NewConflictError("recipe", "name", "a recipe with this name already exists"), err)
+	})
+
+	t.Run("should keep other errors as they are", func(t *testing.T) {
+		_, err := db.ExecContext(ctx, "INSERT INTO recipe (name) VALUES (?)", "custard")
+
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, errs.ErrConflict) || errors.Is(err, errs.ErrBadReference))
+	})
+}
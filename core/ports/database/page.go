@@ -0,0 +1,141 @@
+package database
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PageQuery is a query whose rows are listed in pages using keyset pagination,
+// so that every page is found by an index seek instead of an offset.
+type PageQuery struct {
+	// Select are the columns mapped to every row, with a leading SELECT.
+	Select string
+	// From is the rest of the query before any condition, with a leading FROM.
+	From       string
+	Conditions []string
+	Args       []any
+	// ID is the unique column that breaks ties between rows.
+	ID string
+	// SortBy are the expressions of every field the rows can be sorted by.
+	SortBy map[model.SortField]string
+}
+
+type cursor struct {
+	SortBy model.SortField `json:"s"`
+	Order  model.Order     `json:"o"`
+	Value  any             `json:"v"`
+	// Time tells whether the value is a timestamp, as they are not kept by JSON.
+	Time bool  `json:"t,omitempty"`
+	ID   int64 `json:"id"`
+}
+
+type keyedRow[T any] struct {
+	row T
+	key any
+	id  int64
+}
+
+// keyScanner scans the sort key and the id after the columns of the row mapper.
+type keyScanner struct {
+	RowScanner
+	key *any
+	id  *int64
+}
+
+func (s keyScanner) Scan(dest ...any) error {
+	return s.RowScanner.Scan(append(dest, s.key, s.id)...)
+}
+
+// QueryPage gets the page of rows selected by the options, which must have been
+// validated with model.NewPageOptions.
+func QueryPage[T any](ctx context.Context, db Database, rowMapper RowMapper[T], query PageQuery, opts model.PageOptions) (model.Page[T], error) {
+	sortExpr := query.ID
+	if opts.SortBy != "" {
+		sortExpr = query.SortBy[opts.SortBy]
+	}
+	comparison, direction := ">", "ASC"
+	if opts.Order == model.Descending {
+		comparison, direction = "<", "DESC"
+	}
+	conditions, args := append([]string{}, query.Conditions...), append([]any{}, query.Args...)
+	if opts.Cursor != "" {
+		after, err := decodeCursor(opts.Cursor)
+		if err != nil || after.SortBy != opts.SortBy || after.Order != opts.Order {
+			return model.Page[T]{}, errs.ErrBadCursor
+		}
+		conditions = append(conditions, "("+sortExpr+" "+comparison+" ? OR ("+sortExpr+" = ? AND "+query.ID+" "+comparison+" ?))")
+		args = append(args, after.Value, after.Value, after.ID)
+	}
+	sql := query.Select + ", " + sortExpr + ", " + query.ID + " " + query.From
+	if len(conditions) > 0 {
+		sql += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	sql += " ORDER BY " + sortExpr + " " + direction + ", " + query.ID + " " + direction + " LIMIT " + strconv.Itoa(opts.Limit+1)
+	rows, err := QueryAndMap(ctx, db, func(rowScanner RowScanner) (keyedRow[T], error) {
+		var keyed keyedRow[T]
+		row, err := rowMapper(keyScanner{rowScanner, &keyed.key, &keyed.id})
+		keyed.row = row
+		return keyed, err
+	}, sql, args...)
+	if err != nil {
+		return model.Page[T]{}, err
+	}
+	page := model.Page[T]{Data: []T{}}
+	for i, keyed := range rows {
+		if i == opts.Limit {
+			last := rows[i-1]
+			next, err := encodeCursor(cursor{SortBy: opts.SortBy, Order: opts.Order, Value: last.key, ID: last.id})
+			if err != nil {
+				return model.Page[T]{}, err
+			}
+			page.NextCursor = &next
+			break
+		}
+		page.Data = append(page.Data, keyed.row)
+	}
+	return page, nil
+}
+
+func encodeCursor(c cursor) (string, error) {
+	if t, ok := c.Value.(time.Time); ok {
+		c.Value, c.Time = t.UTC().Format(time.RFC3339Nano), true
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, err
+	}
+	if c.Time {
+		value, _ := c.Value.(string)
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return c, err
+		}
+		c.Value = t
+	}
+	return c, nil
+}
+
+// ContainsPattern gets the LIKE pattern, escaped with a backslash, matching the
+// values that contain s.
+func ContainsPattern(s string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+	return "%" + escaped + "%"
+}
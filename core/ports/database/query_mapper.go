@@ -2,6 +2,8 @@ package database
 
 import (
 	"context"
+	"strconv"
+	"strings"
 )
 
 type RowMapper[T any] func(rowScanner RowScanner) (T, error)
@@ -26,3 +28,14 @@ func QueryAndMap[T any](ctx context.Context, db Database, rowMapper RowMapper[T]
 	}
 	return ts, nil
 }
+
+// IDList gets the ids as a JSON array to read with json_each, such as in
+// "WHERE id IN (SELECT value FROM json_each(?))", so that any number of them
+// fits in a single parameter.
+func IDList(ids []int64) string {
+	items := make([]string, len(ids))
+	for i, id := range ids {
+		items[i] = strconv.FormatInt(id, 10)
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
@@ -0,0 +1,223 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 pages in points, with the same margin on every side.
+const (
+	pageWidth    = 595.0
+	pageHeight   = 842.0
+	pageMargin   = 55.0
+	contentWidth = pageWidth - 2*pageMargin
+	lineSpacing  = 1.4
+)
+
+// pdf lays out text top to bottom on as many pages as needed, using the
+// standard Helvetica fonts so that no font has to be embedded.
+type pdf struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+type pdfColumn struct {
+	width float64
+	right bool
+}
+
+func newPDF() *pdf {
+	doc := &pdf{}
+	doc.newPage()
+	return doc
+}
+
+func (doc *pdf) newPage() {
+	doc.pages = append(doc.pages, &bytes.Buffer{})
+	doc.y = pageHeight - pageMargin
+}
+
+func (doc *pdf) page() *bytes.Buffer {
+	return doc.pages[len(doc.pages)-1]
+}
+
+// advance moves down a line of the given height, starting a new page when it
+// does not fit in the current one.
+func (doc *pdf) advance(height float64) {
+	if doc.y-height < pageMargin {
+		doc.newPage()
+	}
+	doc.y -= height
+}
+
+func (doc *pdf) space(height float64) {
+	doc.y -= height
+}
+
+// text writes a paragraph, wrapped to the width of the page.
+func (doc *pdf) text(text string, size float64, bold bool) {
+	for _, line := range wrapText(text, contentWidth, size, bold) {
+		doc.advance(size * lineSpacing)
+		doc.show(pageMargin, line, size, bold)
+	}
+}
+
+// row writes a line of a table, cutting every cell to fit its column.
+func (doc *pdf) row(columns []pdfColumn, size float64, bold bool, cells ...string) {
+	doc.advance(size * lineSpacing)
+	x := pageMargin
+	for i, column := range columns {
+		cell := wrapText(cells[i], column.width-4, size, bold)[0]
+		cellX := x
+		if column.right {
+			cellX = x + column.width - textWidth(cell, size, bold)
+		}
+		doc.show(cellX, cell, size, bold)
+		x += column.width
+	}
+}
+
+// rule draws a horizontal line across the page under the last line written.
+func (doc *pdf) rule() {
+	y := doc.y - 4
+	fmt.Fprintf(doc.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", pageMargin, y, pageWidth-pageMargin, y)
+	doc.y -= 4
+}
+
+func (doc *pdf) show(x float64, text string, size float64, bold bool) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(doc.page(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, doc.y, escapePDF(text))
+}
+
+// write writes the whole document, with the cross reference table pointing at
+// the offset of every object.
+func (doc *pdf) write(w io.Writer) error {
+	out := &bytes.Buffer{}
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	out.WriteString("%PDF-1.4\n")
+	kids := []string{}
+	for i := range doc.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(doc.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range doc.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+	xref := out.Len()
+	fmt.Fprintf(out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	_, err := out.WriteTo(w)
+	return err
+}
+
+// wrapText splits the text in lines no wider than width, breaking between
+// words. A word wider than a line is cut.
+func wrapText(text string, width float64, size float64, bold bool) []string {
+	lines := []string{}
+	line := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if textWidth(candidate, size, bold) <= width {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		for textWidth(word, size, bold) > width {
+			cut := len([]rune(word)) - 1
+			for cut > 1 && textWidth(string([]rune(word)[:cut]), size, bold) > width {
+				cut--
+			}
+			lines = append(lines, string([]rune(word)[:cut]))
+			word = string([]rune(word)[cut:])
+		}
+		line = word
+	}
+	return append(lines, line)
+}
+
+// textWidth is the width of the text in points.
+func textWidth(text string, size float64, bold bool) float64 {
+	widths := helveticaWidths
+	if bold {
+		widths = helveticaBoldWidths
+	}
+	width := 0
+	for _, r := range text {
+		if r >= 32 && r < 127 {
+			width += widths[r-32]
+		} else {
+			width += 556
+		}
+	}
+	return float64(width) * size / 1000
+}
+
+// winAnsi are the characters of the WinAnsi encoding that are not at the same
+// code as in Unicode.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '•': 0x95, '–': 0x96, '—': 0x97,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, 'Œ': 0x8c, 'œ': 0x9c, '™': 0x99,
+}
+
+// escapePDF encodes the text as a PDF string in the WinAnsi encoding of the
+// fonts. Characters it does not have are written as question marks.
+func escapePDF(text string) string {
+	var escaped strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			escaped.WriteByte('\\')
+			escaped.WriteRune(r)
+		case r >= 32 && r < 127:
+			escaped.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&escaped, "\\%03o", r)
+		case winAnsi[r] != 0:
+			fmt.Fprintf(&escaped, "\\%03o", winAnsi[r])
+		default:
+			escaped.WriteByte('?')
+		}
+	}
+	return escaped.String()
+}
+
+// The widths of the printable ASCII characters of the standard fonts, from
+// their Adobe font metrics, in thousandths of the font size.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
@@ -0,0 +1,103 @@
+package document
+
+import (
+	"costly/core/model"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+)
+
+//go:embed recipe_card.html
+var recipeCardHTML string
+
+var recipeCardTemplate = template.Must(template.New("recipe_card").Funcs(template.FuncMap{
+	"money":    formatMoney,
+	"quantity": formatQuantity,
+	"join":     joinAllergens,
+}).Parse(recipeCardHTML))
+
+// WriteRecipeCardHTML writes the recipe card as a standalone HTML page.
+func WriteRecipeCardHTML(w io.Writer, card model.RecipeCard) error {
+	return recipeCardTemplate.Execute(w, card)
+}
+
+// WriteRecipeCardPDF writes the recipe card as an A4 PDF document, using the
+// fonts every PDF reader has so nothing needs to be embedded.
+func WriteRecipeCardPDF(w io.Writer, card model.RecipeCard) error {
+	doc := newPDF()
+	doc.text(card.Name, 20, true)
+	summary := fmt.Sprintf("%d %s", card.Portions, plural(card.Portions, "portion"))
+	if card.PrepMinutes > 0 {
+		summary += fmt.Sprintf(" - prep %d min", card.PrepMinutes)
+	}
+	if card.CookMinutes > 0 {
+		summary += fmt.Sprintf(" - cook %d min", card.CookMinutes)
+	}
+	doc.text(summary, 10, false)
+	doc.space(12)
+
+	columns := []pdfColumn{{width: 235}, {width: 80, right: true}, {width: 90, right: true}, {width: 80, right: true}}
+	doc.row(columns, 10, true, "Ingredient", "Quantity", "Unit cost", "Cost")
+	doc.rule()
+	for _, ingredient := range card.Ingredients {
+		doc.row(columns, 10, false,
+			ingredient.Name,
+			formatQuantity(ingredient.Quantity),
+			formatMoney(ingredient.UnitCost)+" / "+string(ingredient.CostUnit),
+			formatMoney(ingredient.Cost),
+		)
+	}
+	doc.rule()
+	totals := []pdfColumn{{width: 405}, {width: 80, right: true}}
+	doc.row(totals, 10, true, "Total cost", formatMoney(card.Cost))
+	doc.row(totals, 10, true, "Cost per portion", formatMoney(card.PortionCost))
+	if card.Price > 0 {
+		doc.row(totals, 10, true, fmt.Sprintf("Price - food cost %s%%", strconv.FormatFloat(card.FoodCostPercentage, 'f', -1, 64)), formatMoney(card.Price))
+	}
+	doc.space(12)
+	allergens := "none"
+	if len(card.Allergens) > 0 {
+		allergens = joinAllergens(card.Allergens)
+	}
+	doc.text("Allergens: "+allergens, 10, true)
+
+	if len(card.Steps) > 0 {
+		doc.space(12)
+		doc.text("Method", 14, true)
+		for i, step := range card.Steps {
+			doc.text(fmt.Sprintf("%d. %s", i+1, step), 10, false)
+		}
+	}
+	if card.PlatingNotes != "" {
+		doc.space(12)
+		doc.text("Plating", 14, true)
+		doc.text(card.PlatingNotes, 10, false)
+	}
+	return doc.write(w)
+}
+
+func formatMoney(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+func formatQuantity(quantity model.Quantity) string {
+	return strconv.FormatFloat(quantity.Amount, 'f', -1, 64) + " " + string(quantity.Unit)
+}
+
+func joinAllergens(allergens []model.Allergen) string {
+	names := make([]string, len(allergens))
+	for i, allergen := range allergens {
+		names[i] = string(allergen)
+	}
+	return strings.Join(names, ", ")
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
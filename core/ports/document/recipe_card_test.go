@@ -0,0 +1,89 @@
+package document_test
+
+import (
+	"bytes"
+	"costly/core/model"
+	"costly/core/ports/document"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var card = model.NewRecipeCard(model.RecipeView{
+	ID:       1,
+	Name:     "Crème brûlée",
+	Price:    6,
+	Portions: 4,
+	Ingredients: []model.RecipeIngredientView{
+		{ID: 1, Name: "cream", Unit: model.Gram, Price: 0.004, Units: 500, Allergens: []model.Allergen{model.Milk}},
+		{ID: 2, Name: "sugar", Unit: model.Gram, Price: 0.0012, Units: 1250, Allergens: []model.Allergen{}},
+	},
+	RecipeMethod: model.RecipeMethod{
+		Steps:        []string{"heat the cream (do not boil)", "caramelize <the> sugar"},
+		PlatingNotes: "serve cold",
+		PrepMinutes:  20,
+	},
+})
+
+// pdfObjects checks that the cross reference table points at every object,
+// and returns how many pages the document has.
+func pdfObjects(t *testing.T, pdf []byte) int {
+	require.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	require.NotNil(t, startxref)
+	xref, err := strconv.Atoi(string(startxref[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(pdf[xref:], []byte("xref\n")))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[xref:], -1)
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(string(entry[1]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(pdf[offset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))), "object %d", i+1)
+	}
+	count := regexp.MustCompile(`/Count (\d+)`).FindSubmatch(pdf)
+	require.NotNil(t, count)
+	pages, err := strconv.Atoi(string(count[1]))
+	require.NoError(t, err)
+	return pages
+}
+
+func TestWriteRecipeCardHTML(t *testing.T) {
+	html := &bytes.Buffer{}
+	require.NoError(t, document.WriteRecipeCardHTML(html, card))
+	assert.Contains(t, html.String(), "<h1>Crème brûlée</h1>")
+	assert.Contains(t, html.String(), "4 portions · prep 20 min</p>")
+	assert.Contains(t, html.String(), `<td>sugar</td><td class="number">1.25 kg</td><td class="number">1.20 / kg</td><td class="number">1.50</td>`)
+	assert.Contains(t, html.String(), `<td colspan="3">Cost per portion</td><td class="number">0.88</td>`)
+	assert.Contains(t, html.String(), `Price · food cost 58.3%</td><td class="number">6.00</td>`)
+	assert.Contains(t, html.String(), "Allergens: milk</p>")
+	assert.Contains(t, html.String(), "<li>caramelize &lt;the&gt; sugar</li>")
+}
+
+func TestWriteRecipeCardPDF(t *testing.T) {
+	t.Run("should write a single page card", func(t *testing.T) {
+		pdf := &bytes.Buffer{}
+		require.NoError(t, document.WriteRecipeCardPDF(pdf, card))
+		assert.Equal(t, 1, pdfObjects(t, pdf.Bytes()))
+		assert.Contains(t, pdf.String(), `(Cr\350me br\373l\351e) Tj`)
+		assert.Contains(t, pdf.String(), `(1. heat the cream \(do not boil\)) Tj`)
+		assert.Contains(t, pdf.String(), `(1.20 / kg) Tj`)
+		assert.Contains(t, pdf.String(), `(Allergens: milk) Tj`)
+	})
+
+	t.Run("should break long methods in pages", func(t *testing.T) {
+		long := card
+		long.Steps = []string{}
+		for i := 0; i < 40; i++ {
+			long.Steps = append(long.Steps, strings.Repeat("stir the custard gently ", 10))
+		}
+		pdf := &bytes.Buffer{}
+		require.NoError(t, document.WriteRecipeCardPDF(pdf, long))
+		assert.Equal(t, 3, pdfObjects(t, pdf.Bytes()))
+	})
+}
@@ -1,9 +1,11 @@
 package ports
 
 import (
+	"costly/core/ports/backup"
 	"costly/core/ports/clock"
 	"costly/core/ports/database"
 	"costly/core/ports/logger"
+	"costly/core/ports/storage"
 	"fmt"
 )
 
@@ -11,9 +13,11 @@ type Ports struct {
 	Database database.Database
 	Clock    clock.Clock
 	Logger   logger.Logger
+	Storage  storage.Storage
+	Backups  backup.Backups
 }
 
-func New(logLevel string, connectionString string) (*Ports, error) {
+func New(logLevel string, connectionString string, storageDir string, backupDir string, backupRetention int) (*Ports, error) {
 	logger, err := logger.New(logLevel)
 	if err != nil {
 		return &Ports{}, fmt.Errorf("could not create logger, Err: %s", err)
@@ -27,5 +31,7 @@ func New(logLevel string, connectionString string) (*Ports, error) {
 		Database: database,
 		Clock:    clock,
 		Logger:   logger,
+		Storage:  storage.New(storageDir),
+		Backups:  backup.New(database, backupDir, backupRetention),
 	}, nil
 }
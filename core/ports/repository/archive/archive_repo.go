@@ -0,0 +1,263 @@
+package archiverepo
+
+import (
+	"cmp"
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	"costly/core/ports/database"
+	categoryrepo "costly/core/ports/repository/category"
+	ingredientrepo "costly/core/ports/repository/ingredient"
+	menurepo "costly/core/ports/repository/menu"
+	reciperepo "costly/core/ports/repository/recipe"
+	revisionrepo "costly/core/ports/repository/revision"
+	searchrepo "costly/core/ports/repository/search"
+	"errors"
+	"slices"
+)
+
+type ArchiveRepository interface {
+	// Dump reads every entity but attachments, sorted by id.
+	Dump(ctx context.Context) (model.Archive, error)
+	// IsEmpty tells whether there are no ingredients, recipes nor menus.
+	IsEmpty(ctx context.Context) (bool, error)
+	// Restore stores the archived entities with their ids, timestamps and
+	// versions, which archives of version 1 lack and so start at 1, replacing
+	// the categories there are. It must be run on an empty database.
+	Restore(ctx context.Context, archive model.Archive) error
+}
+
+type repository struct {
+	db database.Database
+}
+
+func New(db database.Database) ArchiveRepository {
+	return &repository{db}
+}
+
+func (r *repository) Dump(ctx context.Context) (model.Archive, error) {
+	var archive model.Archive
+	var err error
+	if archive.Categories, err = categoryrepo.New(r.db).FindAll(ctx); err != nil {
+		return model.Archive{}, err
+	}
+	ingredients, err := ingredientrepo.New(r.db).FindAll(ctx, ingredientrepo.Filter{})
+	if err != nil {
+		return model.Archive{}, err
+	}
+	for _, ingredient := range ingredients {
+		archive.Ingredients = append(archive.Ingredients, model.ArchivedIngredient{Ingredient: ingredient, Version: ingredient.Version})
+	}
+	slices.SortFunc(archive.Ingredients, func(a, b model.ArchivedIngredient) int { return cmp.Compare(a.ID, b.ID) })
+	if archive.IngredientPrices, err = database.QueryAndMap(ctx, r.db, mapToArchivedPrice, "SELECT id, ingredient_id, price, created_at FROM ingredient_price_history ORDER BY id"); err != nil {
+		return model.Archive{}, err
+	}
+	if archive.Recipes, archive.RecipeRevisions, err = r.dumpRecipes(ctx); err != nil {
+		return model.Archive{}, err
+	}
+	if archive.Stock, err = database.QueryAndMap(ctx, r.db, mapToIngredientStock, "SELECT id, ingredient_id, units, price, created_at FROM stock_history ORDER BY id"); err != nil {
+		return model.Archive{}, err
+	}
+	if archive.Sales, err = database.QueryAndMap(ctx, r.db, mapToRecipeSales, "SELECT id, recipe_id, COALESCE(revision_id, 0), units, created_at FROM sold_recipes_history ORDER BY id"); err != nil {
+		return model.Archive{}, err
+	}
+	if archive.Productions, err = database.QueryAndMap(ctx, r.db, mapToProduction, "SELECT id, recipe_id, ingredient_id, batches, units, price, created_at FROM production_history ORDER BY id"); err != nil {
+		return model.Archive{}, err
+	}
+	if archive.Menus, err = menurepo.New(r.db).FindAll(ctx); err != nil {
+		return model.Archive{}, err
+	}
+	return archive, nil
+}
+
+func (r *repository) dumpRecipes(ctx context.Context) ([]model.ArchivedRecipe, []model.ArchivedRecipeRevision, error) {
+	recipeIDs, err := database.QueryAndMap(ctx, r.db, mapToID, "SELECT id FROM recipe ORDER BY id")
+	if err != nil {
+		return nil, nil, err
+	}
+	recipes, revisions := []model.ArchivedRecipe{}, []model.ArchivedRecipeRevision{}
+	for _, recipeID := range recipeIDs {
+		recipe, err := reciperepo.New(r.db).Find(ctx, recipeID)
+		if err != nil {
+			return nil, nil, err
+		}
+		recipes = append(recipes, model.ArchivedRecipe{Recipe: recipe, Version: recipe.Version})
+		recipeRevisions, err := revisionrepo.New(r.db).FindAll(ctx, recipeID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, revision := range recipeRevisions {
+			revisions = append(revisions, model.ArchivedRecipeRevision{ID: revision.ID, RecipeRevision: revision})
+		}
+	}
+	slices.SortFunc(revisions, func(a, b model.ArchivedRecipeRevision) int { return cmp.Compare(a.ID, b.ID) })
+	return recipes, revisions, nil
+}
+
+func (r *repository) IsEmpty(ctx context.Context) (bool, error) {
+	var empty bool
+	err := r.db.QueryRowContext(ctx, "SELECT NOT EXISTS (SELECT 1 FROM ingredient) AND NOT EXISTS (SELECT 1 FROM recipe) AND NOT EXISTS (SELECT 1 FROM menu)").Scan(&empty)
+	return empty, err
+}
+
+func (r *repository) Restore(ctx context.Context, archive model.Archive) error {
+	return r.db.WithTx(ctx, func(tx database.Database) error {
+		err := restore(ctx, tx, archive)
+		if errors.Is(err, errs.ErrConflict) || errors.Is(err, errs.ErrBadReference) {
+			return errs.ErrBadArchive
+		}
+		return err
+	})
+}
+
+func restore(ctx context.Context, tx database.Database, archive model.Archive) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM category"); err != nil {
+		return err
+	}
+	// parents are created before their subcategories, so they have lower ids
+	for _, category := range archive.Categories {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO category (id, name, parent_id, created_at) VALUES (?, ?, ?, ?)", category.ID, category.Name, category.ParentID, category.CreatedAt); err != nil {
+			return err
+		}
+	}
+	for _, ingredient := range archive.Ingredients {
+		if err := restoreIngredient(ctx, tx, ingredient); err != nil {
+			return err
+		}
+	}
+	for _, price := range archive.IngredientPrices {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO ingredient_price_history (id, ingredient_id, price, created_at) VALUES (?, ?, ?, ?)", price.ID, price.IngredientID, price.Price, price.CreatedAt); err != nil {
+			return err
+		}
+	}
+	for _, recipe := range archive.Recipes {
+		if err := restoreRecipe(ctx, tx, recipe); err != nil {
+			return err
+		}
+	}
+	for _, revision := range archive.RecipeRevisions {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO recipe_revision (id, recipe_id, version, name, portions, cost, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			revision.ID, revision.RecipeID, revision.Version, revision.Name, revision.Portions, revision.Cost, revision.CreatedAt); err != nil {
+			return err
+		}
+		for _, ingredient := range revision.Ingredients {
+			if _, err := tx.ExecContext(ctx, "INSERT INTO recipe_revision_ingredient (revision_id, ingredient_id, name, price, units) VALUES (?, ?, ?, ?, ?)", revision.ID, ingredient.ID, ingredient.Name, ingredient.Price, ingredient.Units); err != nil {
+				return err
+			}
+		}
+	}
+	for _, stock := range archive.Stock {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO stock_history (id, ingredient_id, units, price, created_at) VALUES (?, ?, ?, ?, ?)", stock.ID, stock.IngredientID, stock.Units, stock.Price, stock.CreatedAt); err != nil {
+			return err
+		}
+	}
+	for _, sales := range archive.Sales {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO sold_recipes_history (id, recipe_id, revision_id, units, created_at) VALUES (?, ?, NULLIF(?, 0), ?, ?)", sales.ID, sales.RecipeID, sales.RevisionID, sales.Units, sales.CreatedAt); err != nil {
+			return err
+		}
+	}
+	for _, production := range archive.Productions {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO production_history (id, recipe_id, ingredient_id, batches, units, price, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			production.ID, production.RecipeID, production.IngredientID, production.Batches, production.Units, production.Price, production.CreatedAt); err != nil {
+			return err
+		}
+	}
+	for _, menu := range archive.Menus {
+		if err := restoreMenu(ctx, tx, menu); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func restoreIngredient(ctx context.Context, tx database.Database, ingredient model.ArchivedIngredient) error {
+	nutrition := ingredient.Nutrition
+	if _, err := tx.ExecContext(ctx, "INSERT INTO ingredient (id, name, unit, price, units_in_stock, energy, protein, fat, saturated_fat, carbohydrate, sugar, salt, fibre, category_id, created_at, last_modified, version) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		ingredient.ID, ingredient.Name, ingredient.Unit, ingredient.Price, ingredient.UnitsInStock,
+		nutrition.Energy, nutrition.Protein, nutrition.Fat, nutrition.SaturatedFat, nutrition.Carbohydrate, nutrition.Sugar, nutrition.Salt, nutrition.Fibre,
+		ingredient.CategoryID, ingredient.CreatedAt, ingredient.LastModified, max(ingredient.Version, 1)); err != nil {
+		return err
+	}
+	for _, allergen := range ingredient.Allergens {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO ingredient_allergen (ingredient_id, allergen) VALUES (?, ?)", ingredient.ID, allergen); err != nil {
+			return err
+		}
+	}
+	for _, tag := range ingredient.Tags {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO ingredient_tag (ingredient_id, tag) VALUES (?, ?)", ingredient.ID, tag); err != nil {
+			return err
+		}
+	}
+	return searchrepo.IndexIngredient(ctx, tx, ingredient.ID)
+}
+
+func restoreRecipe(ctx context.Context, tx database.Database, recipe model.ArchivedRecipe) error {
+	if _, err := tx.ExecContext(ctx, "INSERT INTO recipe (id, name, price, portions, plating_notes, prep_minutes, cook_minutes, category_id, created_at, last_modified, version) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		recipe.ID, recipe.Name, recipe.Price, recipe.Portions, recipe.PlatingNotes, recipe.PrepMinutes, recipe.CookMinutes, recipe.CategoryID, recipe.CreatedAt, recipe.LastModified, max(recipe.Version, 1)); err != nil {
+		return err
+	}
+	for _, recipeIngredient := range recipe.Ingredients {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO recipe_ingredient (recipe_id, ingredient_id, units) VALUES (?, ?, ?)", recipe.ID, recipeIngredient.ID, recipeIngredient.Units); err != nil {
+			return err
+		}
+	}
+	for position, step := range recipe.Steps {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO recipe_step (recipe_id, position, description) VALUES (?, ?, ?)", recipe.ID, position, step); err != nil {
+			return err
+		}
+	}
+	for _, tag := range recipe.Tags {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO recipe_tag (recipe_id, tag) VALUES (?, ?)", recipe.ID, tag); err != nil {
+			return err
+		}
+	}
+	return searchrepo.IndexRecipe(ctx, tx, recipe.ID)
+}
+
+func restoreMenu(ctx context.Context, tx database.Database, menu model.Menu) error {
+	if _, err := tx.ExecContext(ctx, "INSERT INTO menu (id, name, channel, valid_from, valid_until, created_at, last_modified) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		menu.ID, menu.Name, menu.Channel, menu.ValidFrom, menu.ValidUntil, menu.CreatedAt, menu.LastModified); err != nil {
+		return err
+	}
+	for position, item := range menu.Items {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO menu_item (id, menu_id, position, name, price) VALUES (?, ?, ?, ?, ?)", item.ID, menu.ID, position, item.Name, item.Price); err != nil {
+			return err
+		}
+		for _, recipe := range item.Recipes {
+			if _, err := tx.ExecContext(ctx, "INSERT INTO menu_item_recipe (menu_item_id, recipe_id, units) VALUES (?, ?, ?)", item.ID, recipe.ID, recipe.Units); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func mapToID(rowScanner database.RowScanner) (int64, error) {
+	var id int64
+	err := rowScanner.Scan(&id)
+	return id, err
+}
+
+func mapToArchivedPrice(rowScanner database.RowScanner) (model.ArchivedPrice, error) {
+	var price model.ArchivedPrice
+	err := rowScanner.Scan(&price.ID, &price.IngredientID, &price.Price, &price.CreatedAt)
+	return price, err
+}
+
+func mapToIngredientStock(rowScanner database.RowScanner) (model.IngredientStock, error) {
+	var stock model.IngredientStock
+	err := rowScanner.Scan(&stock.ID, &stock.IngredientID, &stock.Units, &stock.Price, &stock.CreatedAt)
+	return stock, err
+}
+
+func mapToRecipeSales(rowScanner database.RowScanner) (model.RecipeSales, error) {
+	var sales model.RecipeSales
+	err := rowScanner.Scan(&sales.ID, &sales.RecipeID, &sales.RevisionID, &sales.Units, &sales.CreatedAt)
+	return sales, err
+}
+
+func mapToProduction(rowScanner database.RowScanner) (model.Production, error) {
+	var production model.Production
+	err := rowScanner.Scan(&production.ID, &production.RecipeID, &production.IngredientID, &production.Batches, &production.Units, &production.Price, &production.CreatedAt)
+	return production, err
+}
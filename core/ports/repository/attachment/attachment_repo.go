@@ -0,0 +1,79 @@
+package attachmentrepo
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	"costly/core/ports/database"
+	"database/sql"
+	"errors"
+)
+
+type RecipeAttachmentRepository interface {
+	Add(ctx context.Context, attachment *model.RecipeAttachment) error
+	Find(ctx context.Context, recipeID int64, attachmentID int64) (model.RecipeAttachment, error)
+	FindByRecipe(ctx context.Context, recipeID int64) ([]model.RecipeAttachment, error)
+	FindAll(ctx context.Context) ([]model.RecipeAttachment, error)
+	Delete(ctx context.Context, recipeID int64, attachmentID int64) error
+}
+
+type repository struct {
+	db database.Database
+}
+
+func New(db database.Database) RecipeAttachmentRepository {
+	return &repository{db}
+}
+
+func (r *repository) Add(ctx context.Context, attachment *model.RecipeAttachment) error {
+	result, err := r.db.ExecContext(ctx, "INSERT INTO recipe_attachment (recipe_id, file_name, content_type, size, storage_key, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		attachment.RecipeID, attachment.FileName, attachment.ContentType, attachment.Size, attachment.StorageKey, attachment.CreatedAt)
+	if err != nil {
+		if errors.Is(err, errs.ErrBadReference) {
+			return errs.ErrNotFound
+		}
+		return err
+	}
+	attachmentID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	attachment.ID = attachmentID
+	return nil
+}
+
+func (r *repository) Find(ctx context.Context, recipeID int64, attachmentID int64) (model.RecipeAttachment, error) {
+	attachment, err := database.QueryRowAndMap(ctx, r.db, mapToRecipeAttachment, "SELECT * FROM recipe_attachment WHERE recipe_id = ? AND id = ?", recipeID, attachmentID)
+	if err == sql.ErrNoRows {
+		return model.RecipeAttachment{}, errs.ErrNotFound
+	} else if err != nil {
+		return model.RecipeAttachment{}, err
+	}
+	return attachment, nil
+}
+
+func (r *repository) FindByRecipe(ctx context.Context, recipeID int64) ([]model.RecipeAttachment, error) {
+	return database.QueryAndMap(ctx, r.db, mapToRecipeAttachment, "SELECT * FROM recipe_attachment WHERE recipe_id = ? ORDER BY id", recipeID)
+}
+
+func (r *repository) FindAll(ctx context.Context) ([]model.RecipeAttachment, error) {
+	return database.QueryAndMap(ctx, r.db, mapToRecipeAttachment, "SELECT * FROM recipe_attachment ORDER BY id")
+}
+
+func (r *repository) Delete(ctx context.Context, recipeID int64, attachmentID int64) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM recipe_attachment WHERE recipe_id = ? AND id = ?", recipeID, attachmentID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+	return nil
+}
+
+func mapToRecipeAttachment(rowScanner database.RowScanner) (model.RecipeAttachment, error) {
+	var attachment model.RecipeAttachment
+	err := rowScanner.Scan(&attachment.ID, &attachment.RecipeID, &attachment.FileName, &attachment.ContentType, &attachment.Size, &attachment.StorageKey, &attachment.CreatedAt)
+	return attachment, err
+}
@@ -0,0 +1,54 @@
+package categoryrepo
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	"costly/core/ports/database"
+	"database/sql"
+)
+
+type CategoryRepository interface {
+	Add(ctx context.Context, category *model.Category) error
+	Find(ctx context.Context, id int64) (model.Category, error)
+	FindAll(ctx context.Context) ([]model.Category, error)
+}
+
+type repository struct {
+	db database.Database
+}
+
+func New(db database.Database) CategoryRepository {
+	return &repository{db}
+}
+
+func (r *repository) Add(ctx context.Context, category *model.Category) error {
+	result, err := r.db.ExecContext(ctx, "INSERT INTO category (name, parent_id, created_at) VALUES (?, ?, ?)", category.Name, category.ParentID, category.CreatedAt)
+	if err != nil {
+		return err
+	}
+	categoryID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	category.ID = categoryID
+	return nil
+}
+
+func (r *repository) Find(ctx context.Context, id int64) (model.Category, error) {
+	category, err := database.QueryRowAndMap(ctx, r.db, mapToCategory, "SELECT * FROM category WHERE id = ?", id)
+	if err == sql.ErrNoRows {
+		return model.Category{}, errs.ErrNotFound
+	}
+	return category, err
+}
+
+func (r *repository) FindAll(ctx context.Context) ([]model.Category, error) {
+	return database.QueryAndMap(ctx, r.db, mapToCategory, "SELECT * FROM category ORDER BY id")
+}
+
+func mapToCategory(rowScanner database.RowScanner) (model.Category, error) {
+	var category model.Category
+	err := rowScanner.Scan(&category.ID, &category.Name, &category.ParentID, &category.CreatedAt)
+	return category, err
+}
@@ -5,7 +5,9 @@ import (
 	"costly/core/errs"
 	"costly/core/model"
 	"costly/core/ports/database"
+	searchrepo "costly/core/ports/repository/search"
 	"database/sql"
+	"strings"
 	"time"
 )
 
@@ -13,9 +15,29 @@ type IngredientRepository interface {
 	Add(ctx context.Context, ingredient *model.Ingredient) error
 	Update(ctx context.Context, ingredientID int64, updateFunc func(ingredient *model.Ingredient) error) error
 	Find(ctx context.Context, id int64) (model.Ingredient, error)
-	FindAll(ctx context.Context) ([]model.Ingredient, error)
+	FindAll(ctx context.Context, filter Filter) ([]model.Ingredient, error)
+	FindPage(ctx context.Context, filter Filter, opts model.PageOptions) (model.Page[model.Ingredient], error)
+	// IncreaseStockAndUpdatePrice adds units bought at the price, which becomes
+	// the price of the ingredient. Neither this nor taking units out of the
+	// stock changes the version of the ingredient.
 	IncreaseStockAndUpdatePrice(ctx context.Context, ingredientID int64, units int, price float64, now time.Time) error
+	// DecreaseStock takes units out of the stock even if there are not as many,
+	// as it records what already happened, such as a sale.
 	DecreaseStock(ctx context.Context, ingredientID int64, unitsToDecrease int, now time.Time) error
+	// ConsumeStock takes units out of the stock only if there are as many, or
+	// fails with an InsufficientStockError.
+	ConsumeStock(ctx context.Context, ingredientID int64, units int, now time.Time) error
+}
+
+const ingredientColumns = "id, name, unit, price, units_in_stock, energy, protein, fat, saturated_fat, carbohydrate, sugar, salt, fibre, category_id, created_at, last_modified, version"
+
+type Filter struct {
+	// Name keeps only ingredients whose name contains it, ignoring case.
+	Name string
+	// CategoryID keeps only ingredients in the category or any of its descendants.
+	CategoryID *int64
+	// Tags keeps only ingredients having all of them.
+	Tags []string
 }
 
 type ingredientRepository struct {
@@ -27,56 +49,176 @@ func New(db database.Database) IngredientRepository {
 }
 
 func (r *ingredientRepository) Find(ctx context.Context, id int64) (model.Ingredient, error) {
-	ingredient, err := database.QueryRowAndMap(ctx, r.db, mapToIngredient, "SELECT * FROM ingredient WHERE id = ?", id)
+	ingredient, err := database.QueryRowAndMap(ctx, r.db, mapToIngredient, "SELECT "+ingredientColumns+" FROM ingredient WHERE id = ?", id)
 	if err == sql.ErrNoRows {
 		return model.Ingredient{}, errs.ErrNotFound
 	} else if err != nil {
 		return model.Ingredient{}, err
 	}
+	allergens, err := FindAllergens(ctx, r.db, "SELECT * FROM ingredient_allergen WHERE ingredient_id = ?", id)
+	if err != nil {
+		return model.Ingredient{}, err
+	}
+	ingredient.Allergens = allergensOf(allergens, ingredient.ID)
+	tags, err := r.findTags(ctx, "SELECT * FROM ingredient_tag WHERE ingredient_id = ?", id)
+	if err != nil {
+		return model.Ingredient{}, err
+	}
+	ingredient.Tags = tagsOf(tags, ingredient.ID)
 	return ingredient, nil
 }
 
-func (r *ingredientRepository) FindAll(ctx context.Context) ([]model.Ingredient, error) {
-	ingredients, err := database.QueryAndMap(ctx, r.db, mapToIngredient, "SELECT * FROM ingredient")
+func (r *ingredientRepository) FindAll(ctx context.Context, filter Filter) ([]model.Ingredient, error) {
+	conditions, args := filter.conditions()
+	query := "SELECT " + ingredientColumns + " FROM ingredient"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	ingredients, err := database.QueryAndMap(ctx, r.db, mapToIngredient, query, args...)
 	if err != nil {
 		return nil, err
 	}
-	return ingredients, nil
+	return ingredients, r.withAllergensAndTags(ctx, ingredients)
 }
 
-func (r *ingredientRepository) Add(ctx context.Context, ingredient *model.Ingredient) error {
-	result, err := r.db.ExecContext(ctx, "INSERT INTO ingredient (name, unit, price, units_in_stock, created_at, last_modified) VALUES (?, ?, ?, ?, ?, ?)",
-		ingredient.Name, ingredient.Unit, ingredient.Price, ingredient.UnitsInStock, ingredient.CreatedAt, ingredient.LastModified)
-
+func (r *ingredientRepository) FindPage(ctx context.Context, filter Filter, opts model.PageOptions) (model.Page[model.Ingredient], error) {
+	conditions, args := filter.conditions()
+	page, err := database.QueryPage(ctx, r.db, mapToIngredient, database.PageQuery{
+		Select:     "SELECT " + ingredientColumns,
+		From:       "FROM ingredient",
+		Conditions: conditions,
+		Args:       args,
+		ID:         "id",
+		SortBy: map[model.SortField]string{
+			model.SortByName:         "name",
+			model.SortByPrice:        "price",
+			model.SortByLastModified: "last_modified",
+		},
+	}, opts)
 	if err != nil {
-		return err
+		return model.Page[model.Ingredient]{}, err
 	}
+	return page, r.withAllergensAndTags(ctx, page.Data)
+}
 
-	ingredientID, err := result.LastInsertId()
-
-	if err != nil {
-		return err
+func (filter Filter) conditions() ([]string, []any) {
+	conditions, args := []string{}, []any{}
+	if filter.Name != "" {
+		conditions = append(conditions, "name LIKE ? ESCAPE '\\'")
+		args = append(args, database.ContainsPattern(filter.Name))
 	}
-	ingredient.ID = ingredientID
-	return nil
+	if filter.CategoryID != nil {
+		conditions = append(conditions, "category_id IN (WITH RECURSIVE descendant(id) AS (SELECT ? UNION SELECT c.id FROM category c JOIN descendant d ON c.parent_id = d.id) SELECT id FROM descendant)")
+		args = append(args, *filter.CategoryID)
+	}
+	for _, tag := range filter.Tags {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM ingredient_tag it WHERE it.ingredient_id = ingredient.id AND it.tag = ?)")
+		args = append(args, tag)
+	}
+	return conditions, args
 }
 
-func (r *ingredientRepository) Update(ctx context.Context, ingredientID int64, updateFunc func(ingredient *model.Ingredient) error) error {
-	ingredient, err := r.Find(ctx, ingredientID)
+// withAllergensAndTags gets the allergens and tags of just the given ingredients.
+func (r *ingredientRepository) withAllergensAndTags(ctx context.Context, ingredients []model.Ingredient) error {
+	ids := []int64{}
+	for _, ingredient := range ingredients {
+		ids = append(ids, ingredient.ID)
+	}
+	idList := database.IDList(ids)
+	tags, err := r.findTags(ctx, "SELECT * FROM ingredient_tag WHERE ingredient_id IN (SELECT value FROM json_each(?))", idList)
 	if err != nil {
 		return err
 	}
-	updateFunc(&ingredient)
-	_, err = database.QueryRowAndMap(ctx, r.db, mapToIngredient, "UPDATE ingredient SET name = ?, unit = ?, price = ?, units_in_stock = ?, last_modified = ? WHERE id = ? RETURNING *",
-		ingredient.Name, ingredient.Unit, ingredient.Price, ingredient.UnitsInStock, ingredient.LastModified, ingredient.ID)
-	if err == sql.ErrNoRows {
-		return errs.ErrNotFound
-	} else if err != nil {
+	allergens, err := FindAllergens(ctx, r.db, "SELECT * FROM ingredient_allergen WHERE ingredient_id IN (SELECT value FROM json_each(?))", idList)
+	if err != nil {
 		return err
 	}
+	for i := range ingredients {
+		ingredients[i].Allergens = allergensOf(allergens, ingredients[i].ID)
+		ingredients[i].Tags = tagsOf(tags, ingredients[i].ID)
+	}
 	return nil
 }
 
+func (r *ingredientRepository) Add(ctx context.Context, ingredient *model.Ingredient) error {
+	return r.db.WithTx(ctx, func(tx database.Database) error {
+		nutrition := ingredient.Nutrition
+		result, err := tx.ExecContext(ctx, "INSERT INTO ingredient (name, unit, price, units_in_stock, energy, protein, fat, saturated_fat, carbohydrate, sugar, salt, fibre, category_id, created_at, last_modified) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			ingredient.Name, ingredient.Unit, ingredient.Price, ingredient.UnitsInStock,
+			nutrition.Energy, nutrition.Protein, nutrition.Fat, nutrition.SaturatedFat, nutrition.Carbohydrate, nutrition.Sugar, nutrition.Salt, nutrition.Fibre,
+			ingredient.CategoryID, ingredient.CreatedAt, ingredient.LastModified)
+
+		if err != nil {
+			return err
+		}
+
+		ingredientID, err := result.LastInsertId()
+
+		if err != nil {
+			return err
+		}
+		if err := addAllergens(ctx, tx, ingredientID, ingredient.Allergens); err != nil {
+			return err
+		}
+		if err := addTags(ctx, tx, ingredientID, ingredient.Tags); err != nil {
+			return err
+		}
+		if err := addPrice(ctx, tx, ingredientID, ingredient.Price, ingredient.CreatedAt); err != nil {
+			return err
+		}
+		if err := searchrepo.IndexIngredient(ctx, tx, ingredientID); err != nil {
+			return err
+		}
+		ingredient.ID = ingredientID
+		ingredient.Version = 1
+		return nil
+	})
+}
+
+func (r *ingredientRepository) Update(ctx context.Context, ingredientID int64, updateFunc func(ingredient *model.Ingredient) error) error {
+	return r.db.WithTx(ctx, func(tx database.Database) error {
+		ingredient, err := New(tx).Find(ctx, ingredientID)
+		if err != nil {
+			return err
+		}
+		previousPrice := ingredient.Price
+		if err := updateFunc(&ingredient); err != nil {
+			return err
+		}
+		nutrition := ingredient.Nutrition
+		// the version is checked again as the ingredient may have been changed
+		// since it was found, while the stock is left to the stock operations,
+		// which do not change the version
+		_, err = database.QueryRowAndMap(ctx, tx, mapToIngredient, "UPDATE ingredient SET name = ?, unit = ?, price = ?, energy = ?, protein = ?, fat = ?, saturated_fat = ?, carbohydrate = ?, sugar = ?, salt = ?, fibre = ?, category_id = ?, last_modified = ?, version = version + 1 WHERE id = ? AND version = ? RETURNING "+ingredientColumns,
+			ingredient.Name, ingredient.Unit, ingredient.Price,
+			nutrition.Energy, nutrition.Protein, nutrition.Fat, nutrition.SaturatedFat, nutrition.Carbohydrate, nutrition.Sugar, nutrition.Salt, nutrition.Fibre,
+			ingredient.CategoryID, ingredient.LastModified, ingredient.ID, ingredient.Version)
+		if err == sql.ErrNoRows {
+			return errs.NewStaleError("ingredient")
+		} else if err != nil {
+			return err
+		}
+		if ingredient.Price != previousPrice {
+			if err := addPrice(ctx, tx, ingredient.ID, ingredient.Price, ingredient.LastModified); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM ingredient_allergen WHERE ingredient_id = ?", ingredient.ID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM ingredient_tag WHERE ingredient_id = ?", ingredient.ID); err != nil {
+			return err
+		}
+		if err := addTags(ctx, tx, ingredient.ID, ingredient.Tags); err != nil {
+			return err
+		}
+		if err := addAllergens(ctx, tx, ingredient.ID, ingredient.Allergens); err != nil {
+			return err
+		}
+		return searchrepo.IndexIngredient(ctx, tx, ingredient.ID)
+	})
+}
+
 func (r *ingredientRepository) IncreaseStockAndUpdatePrice(ctx context.Context, ingredientID int64, units int, price float64, now time.Time) error {
 	result, err := r.db.ExecContext(ctx, "UPDATE ingredient SET units_in_stock = units_in_stock + ?, price = ?, last_modified = ? WHERE id = ?", units, price, now, ingredientID)
 	if err != nil {
@@ -86,7 +228,7 @@ func (r *ingredientRepository) IncreaseStockAndUpdatePrice(ctx context.Context,
 	if err != nil || rowsAffected == 0 {
 		return errs.ErrNotFound
 	}
-	return nil
+	return addPrice(ctx, r.db, ingredientID, price, now)
 }
 
 func (r *ingredientRepository) DecreaseStock(ctx context.Context, ingredientID int64, unitsToDecrease int, timeOfDecrease time.Time) error {
@@ -101,8 +243,120 @@ func (r *ingredientRepository) DecreaseStock(ctx context.Context, ingredientID i
 	return nil
 }
 
+func (r *ingredientRepository) ConsumeStock(ctx context.Context, ingredientID int64, units int, now time.Time) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE ingredient SET units_in_stock = units_in_stock - ?, last_modified = ? WHERE id = ? AND units_in_stock >= ?", units, now, ingredientID, units)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+	var available int
+	err = r.db.QueryRowContext(ctx, "SELECT units_in_stock FROM ingredient WHERE id = ?", ingredientID).Scan(&available)
+	if err == sql.ErrNoRows {
+		return errs.ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	return &errs.InsufficientStockError{IngredientID: ingredientID, Available: available, Required: units}
+}
+
 func mapToIngredient(rowScanner database.RowScanner) (model.Ingredient, error) {
 	var ingredient model.Ingredient
-	err := rowScanner.Scan(&ingredient.ID, &ingredient.Name, &ingredient.Unit, &ingredient.Price, &ingredient.CreatedAt, &ingredient.LastModified, &ingredient.UnitsInStock)
+	nutrition := &ingredient.Nutrition
+	err := rowScanner.Scan(&ingredient.ID, &ingredient.Name, &ingredient.Unit, &ingredient.Price, &ingredient.UnitsInStock,
+		&nutrition.Energy, &nutrition.Protein, &nutrition.Fat, &nutrition.SaturatedFat, &nutrition.Carbohydrate, &nutrition.Sugar, &nutrition.Salt, &nutrition.Fibre,
+		&ingredient.CategoryID, &ingredient.CreatedAt, &ingredient.LastModified, &ingredient.Version)
 	return ingredient, err
 }
+
+func addAllergens(ctx context.Context, db database.Database, ingredientID int64, allergens []model.Allergen) error {
+	for _, allergen := range allergens {
+		if _, err := db.ExecContext(ctx, "INSERT INTO ingredient_allergen (ingredient_id, allergen) VALUES (?, ?)", ingredientID, allergen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addPrice records the price the ingredient has from the given moment on, so
+// that costs can be computed as they were at any point in time.
+func addPrice(ctx context.Context, db database.Database, ingredientID int64, price float64, from time.Time) error {
+	_, err := db.ExecContext(ctx, "INSERT INTO ingredient_price_history (ingredient_id, price, created_at) VALUES (?, ?, ?)", ingredientID, price, from)
+	return err
+}
+
+type ingredientAllergen struct {
+	ingredientID int64
+	allergen     model.Allergen
+}
+
+// FindAllergens gets the allergens of the ingredient_allergen rows selected by
+// the query, indexed by ingredient id.
+func FindAllergens(ctx context.Context, db database.Database, query string, args ...any) (map[int64][]model.Allergen, error) {
+	ingredientAllergens, err := database.QueryAndMap(ctx, db, mapToIngredientAllergen, query+" ORDER BY allergen", args...)
+	if err != nil {
+		return nil, err
+	}
+	allergens := map[int64][]model.Allergen{}
+	for _, ia := range ingredientAllergens {
+		allergens[ia.ingredientID] = append(allergens[ia.ingredientID], ia.allergen)
+	}
+	return allergens, nil
+}
+
+func allergensOf(allergens map[int64][]model.Allergen, ingredientID int64) []model.Allergen {
+	if ingredientAllergens, ok := allergens[ingredientID]; ok {
+		return ingredientAllergens
+	}
+	return []model.Allergen{}
+}
+
+func mapToIngredientAllergen(rowScanner database.RowScanner) (ingredientAllergen, error) {
+	var ia ingredientAllergen
+	err := rowScanner.Scan(&ia.ingredientID, &ia.allergen)
+	return ia, err
+}
+
+func addTags(ctx context.Context, db database.Database, ingredientID int64, tags []string) error {
+	for _, tag := range tags {
+		if _, err := db.ExecContext(ctx, "INSERT INTO ingredient_tag (ingredient_id, tag) VALUES (?, ?)", ingredientID, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type ingredientTag struct {
+	ingredientID int64
+	tag          string
+}
+
+func (r *ingredientRepository) findTags(ctx context.Context, query string, args ...any) (map[int64][]string, error) {
+	ingredientTags, err := database.QueryAndMap(ctx, r.db, mapToIngredientTag, query+" ORDER BY tag", args...)
+	if err != nil {
+		return nil, err
+	}
+	tags := map[int64][]string{}
+	for _, it := range ingredientTags {
+		tags[it.ingredientID] = append(tags[it.ingredientID], it.tag)
+	}
+	return tags, nil
+}
+
+func tagsOf(tags map[int64][]string, ingredientID int64) []string {
+	if ingredientTags, ok := tags[ingredientID]; ok {
+		return ingredientTags
+	}
+	return []string{}
+}
+
+func mapToIngredientTag(rowScanner database.RowScanner) (ingredientTag, error) {
+	var it ingredientTag
+	err := rowScanner.Scan(&it.ingredientID, &it.tag)
+	return it, err
+}
@@ -11,6 +11,7 @@ import (
 	"costly/core/ports/logger"
 	ingredientrepo "costly/core/ports/repository/ingredient"
 	"testing"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
@@ -21,9 +22,7 @@ func setupTest(t *testing.T) (ingredientrepo.IngredientRepository, clock.Clock,
 	logger, _ := logger.New("debug")
 	clock := clock.New()
 	db, err := database.NewFromDatasource(":memory:", logger)
-	if err != nil {
-		t.FailNow()
-	}
+	require.NoError(t, err)
 	ingredientRepository := ingredientrepo.New(db)
 	ctx := context.Background()
 	return ingredientRepository, clock, ctx
@@ -124,6 +123,28 @@ func TestUpdateIngredient(t *testing.T) {
 	})
 }
 
+func TestIngredientAllergens(t *testing.T) {
+
+	t.Run("should persist and replace ingredient allergens", func(t *testing.T) {
+		ingredientRepository, clock, ctx := setupTest(t)
+		ingredient, _ := model.NewIngredient("ing1", model.Gram, 1.0, clock.Now())
+		ingredient.Allergens = []model.Allergen{model.Gluten, model.Milk}
+		require.NoError(t, ingredientRepository.Add(ctx, ingredient))
+
+		ingr1Get, err := ingredientRepository.Find(ctx, ingredient.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []model.Allergen{model.Gluten, model.Milk}, ingr1Get.Allergens)
+
+		require.NoError(t, ingredientRepository.Update(ctx, ingredient.ID, func(ingredient *model.Ingredient) error {
+			ingredient.Allergens = []model.Allergen{model.Eggs}
+			return nil
+		}))
+		ingredients, err := ingredientRepository.FindAll(ctx, ingredientrepo.Filter{})
+		require.NoError(t, err)
+		assert.Equal(t, []model.Allergen{model.Eggs}, ingredients[0].Allergens)
+	})
+}
+
 func TestFindAllIngredients(t *testing.T) {
 
 	t.Run("should get list of existent ingredients", func(t *testing.T) {
@@ -136,7 +157,7 @@ func TestFindAllIngredients(t *testing.T) {
 		err = ingredientRepository.Add(ctx, ingredient2)
 		require.NoError(t, err)
 
-		ingredients, err := ingredientRepository.FindAll(ctx)
+		ingredients, err := ingredientRepository.FindAll(ctx, ingredientrepo.Filter{})
 		require.NoError(t, err)
 
 		assert.Equal(t, ingredient, &ingredients[0])
@@ -144,6 +165,48 @@ func TestFindAllIngredients(t *testing.T) {
 	})
 }
 
+func TestFindIngredientsPage(t *testing.T) {
+
+	t.Run("should page ingredients sorted by last modified following the cursor", func(t *testing.T) {
+		ingredientRepository, clock, ctx := setupTest(t)
+		now := clock.Now()
+		for i, name := range []string{"salt", "pepper", "saffron"} {
+			ingredient, _ := model.NewIngredient(name, model.Gram, 1.0, now.Add(-time.Duration(i)*time.Hour))
+			require.NoError(t, ingredientRepository.Add(ctx, ingredient))
+		}
+		opts, err := model.NewPageOptions(2, "", model.SortByLastModified, model.Ascending, []model.SortField{model.SortByLastModified})
+		require.NoError(t, err)
+
+		page, err := ingredientRepository.FindPage(ctx, ingredientrepo.Filter{}, opts)
+		require.NoError(t, err)
+		require.Len(t, page.Data, 2)
+		assert.Equal(t, "saffron", page.Data[0].Name)
+		assert.Equal(t, "pepper", page.Data[1].Name)
+		require.NotNil(t, page.NextCursor)
+
+		opts.Cursor = *page.NextCursor
+		page, err = ingredientRepository.FindPage(ctx, ingredientrepo.Filter{}, opts)
+		require.NoError(t, err)
+		require.Len(t, page.Data, 1)
+		assert.Equal(t, "salt", page.Data[0].Name)
+		assert.Nil(t, page.NextCursor)
+	})
+
+	t.Run("should match names literally", func(t *testing.T) {
+		ingredientRepository, clock, ctx := setupTest(t)
+		for _, name := range []string{"100% cocoa", "1000 island dressing"} {
+			ingredient, _ := model.NewIngredient(name, model.Gram, 1.0, clock.Now())
+			require.NoError(t, ingredientRepository.Add(ctx, ingredient))
+		}
+		opts, err := model.NewPageOptions(0, "", "", "", nil)
+		require.NoError(t, err)
+		page, err := ingredientRepository.FindPage(ctx, ingredientrepo.Filter{Name: "0%"}, opts)
+		require.NoError(t, err)
+		require.Len(t, page.Data, 1)
+		assert.Equal(t, "100% cocoa", page.Data[0].Name)
+	})
+}
+
 func TestIncreaseStockAndUpdatePrice(t *testing.T) {
 
 	t.Run("should increase stock if existent", func(t *testing.T) {
@@ -175,22 +238,37 @@ func TestIncreaseStockAndUpdatePrice(t *testing.T) {
 
 func TestDecreaseStock(t *testing.T) {
 
-	t.Run("should increase stock if existent", func(t *testing.T) {
+	t.Run("should decrease stock if existent", func(t *testing.T) {
 		ingredientRepository, clock, ctx := setupTest(t)
 		now := clock.Now()
 		ingredient, _ := model.NewIngredient("ing1", model.Gram, 1.0, now)
 		require.NoError(t, ingredientRepository.Add(ctx, ingredient))
+		require.NoError(t, ingredientRepository.IncreaseStockAndUpdatePrice(ctx, ingredient.ID, 10, 1.0, now))
 
 		modifiedTime := clock.Now()
-		ingredientRepository.DecreaseStock(ctx, ingredient.ID, 4, modifiedTime)
+		require.NoError(t, ingredientRepository.DecreaseStock(ctx, ingredient.ID, 4, modifiedTime))
 
 		ingr1Get, err := ingredientRepository.Find(ctx, ingredient.ID)
 		require.NoError(t, err)
 
-		assert.Equal(t, ingredient.UnitsInStock-4, ingr1Get.UnitsInStock)
+		assert.Equal(t, 6, ingr1Get.UnitsInStock)
 		assert.Equal(t, modifiedTime, ingr1Get.LastModified)
 	})
 
+	t.Run("should decrease stock below zero", func(t *testing.T) {
+		ingredientRepository, clock, ctx := setupTest(t)
+		now := clock.Now()
+		ingredient, _ := model.NewIngredient("ing1", model.Gram, 1.0, now)
+		require.NoError(t, ingredientRepository.Add(ctx, ingredient))
+		require.NoError(t, ingredientRepository.IncreaseStockAndUpdatePrice(ctx, ingredient.ID, 3, 1.0, now))
+
+		require.NoError(t, ingredientRepository.DecreaseStock(ctx, ingredient.ID, 4, clock.Now()))
+
+		ingr1Get, err := ingredientRepository.Find(ctx, ingredient.ID)
+		require.NoError(t, err)
+		assert.Equal(t, -1, ingr1Get.UnitsInStock)
+	})
+
 	t.Run("should return error not found if unexistent", func(t *testing.T) {
 		ingredientRepository, clock, ctx := setupTest(t)
 
@@ -199,3 +277,30 @@ func TestDecreaseStock(t *testing.T) {
 		assert.Equal(t, err, errs.ErrNotFound)
 	})
 }
+
+func TestConsumeStock(t *testing.T) {
+
+	t.Run("should return error if there is not enough stock", func(t *testing.T) {
+		ingredientRepository, clock, ctx := setupTest(t)
+		now := clock.Now()
+		ingredient, _ := model.NewIngredient("ing1", model.Gram, 1.0, now)
+		require.NoError(t, ingredientRepository.Add(ctx, ingredient))
+		require.NoError(t, ingredientRepository.IncreaseStockAndUpdatePrice(ctx, ingredient.ID, 3, 1.0, now))
+
+		err := ingredientRepository.ConsumeStock(ctx, ingredient.ID, 4, clock.Now())
+
+		assert.ErrorIs(t, err, errs.ErrInsufficientStock)
+		assert.Equal(t, &errs.InsufficientStockError{IngredientID: ingredient.ID, Available: 3, Required: 4}, err)
+		ingr1Get, err := ingredientRepository.Find(ctx, ingredient.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 3, ingr1Get.UnitsInStock)
+	})
+
+	t.Run("should return error not found if unexistent", func(t *testing.T) {
+		ingredientRepository, clock, ctx := setupTest(t)
+
+		err := ingredientRepository.ConsumeStock(ctx, 1, 2, clock.Now())
+
+		assert.Equal(t, errs.ErrNotFound, err)
+	})
+}
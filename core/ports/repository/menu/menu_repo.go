@@ -0,0 +1,131 @@
+package menurepo
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	"costly/core/ports/database"
+	"database/sql"
+)
+
+type MenuRepository interface {
+	Add(ctx context.Context, menu *model.Menu) error
+	Find(ctx context.Context, id int64) (model.Menu, error)
+	FindAll(ctx context.Context) ([]model.Menu, error)
+}
+
+type repository struct {
+	db database.Database
+}
+
+func New(db database.Database) MenuRepository {
+	return &repository{db}
+}
+
+func (r *repository) Add(ctx context.Context, menu *model.Menu) error {
+	return r.db.WithTx(ctx, func(tx database.Database) error {
+		result, err := tx.ExecContext(ctx, "INSERT INTO menu (name, channel, valid_from, valid_until, created_at, last_modified) VALUES (?, ?, ?, ?, ?, ?)",
+			menu.Name, menu.Channel, menu.ValidFrom, menu.ValidUntil, menu.CreatedAt, menu.LastModified)
+		if err != nil {
+			return err
+		}
+		menuID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		for position := range menu.Items {
+			item := &menu.Items[position]
+			result, err := tx.ExecContext(ctx, "INSERT INTO menu_item (menu_id, position, name, price) VALUES (?, ?, ?, ?)", menuID, position, item.Name, item.Price)
+			if err != nil {
+				return err
+			}
+			itemID, err := result.LastInsertId()
+			if err != nil {
+				return err
+			}
+			for _, recipe := range item.Recipes {
+				if _, err := tx.ExecContext(ctx, "INSERT INTO menu_item_recipe (menu_item_id, recipe_id, units) VALUES (?, ?, ?)", itemID, recipe.ID, recipe.Units); err != nil {
+					return err
+				}
+			}
+			item.ID = itemID
+		}
+		menu.ID = menuID
+		return nil
+	})
+}
+
+func (r *repository) Find(ctx context.Context, id int64) (model.Menu, error) {
+	menu, err := database.QueryRowAndMap(ctx, r.db, mapToMenu, "SELECT * FROM menu WHERE id = ?", id)
+	if err == sql.ErrNoRows {
+		return model.Menu{}, errs.ErrNotFound
+	} else if err != nil {
+		return model.Menu{}, err
+	}
+	items, err := r.findItems(ctx, "WHERE mi.menu_id = ?", id)
+	if err != nil {
+		return model.Menu{}, err
+	}
+	menu.Items = itemsOf(items, menu.ID)
+	return menu, nil
+}
+
+func (r *repository) FindAll(ctx context.Context) ([]model.Menu, error) {
+	menus, err := database.QueryAndMap(ctx, r.db, mapToMenu, "SELECT * FROM menu ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	items, err := r.findItems(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for i := range menus {
+		menus[i].Items = itemsOf(items, menus[i].ID)
+	}
+	return menus, nil
+}
+
+type menuItemRecipe struct {
+	menuID int64
+	item   model.MenuItem
+	recipe model.MenuItemRecipe
+}
+
+// findItems gets the items of the menus with their recipes, grouped by menu.
+func (r *repository) findItems(ctx context.Context, where string, args ...any) (map[int64][]model.MenuItem, error) {
+	itemRecipes, err := database.QueryAndMap(ctx, r.db, mapToMenuItemRecipe, "SELECT mi.menu_id, mi.id, mi.name, mi.price, mir.recipe_id, mir.units FROM menu_item mi JOIN menu_item_recipe mir ON mir.menu_item_id = mi.id "+where+" ORDER BY mi.menu_id, mi.position, mir.recipe_id", args...)
+	if err != nil {
+		return nil, err
+	}
+	items := map[int64][]model.MenuItem{}
+	for _, ir := range itemRecipes {
+		menuItems := items[ir.menuID]
+		if len(menuItems) == 0 || menuItems[len(menuItems)-1].ID != ir.item.ID {
+			ir.item.Recipes = []model.MenuItemRecipe{}
+			menuItems = append(menuItems, ir.item)
+		}
+		last := &menuItems[len(menuItems)-1]
+		last.Recipes = append(last.Recipes, ir.recipe)
+		items[ir.menuID] = menuItems
+	}
+	return items, nil
+}
+
+func itemsOf(items map[int64][]model.MenuItem, menuID int64) []model.MenuItem {
+	if menuItems, ok := items[menuID]; ok {
+		return menuItems
+	}
+	return []model.MenuItem{}
+}
+
+func mapToMenu(rowScanner database.RowScanner) (model.Menu, error) {
+	var menu model.Menu
+	err := rowScanner.Scan(&menu.ID, &menu.Name, &menu.Channel, &menu.ValidFrom, &menu.ValidUntil, &menu.CreatedAt, &menu.LastModified)
+	return menu, err
+}
+
+func mapToMenuItemRecipe(rowScanner database.RowScanner) (menuItemRecipe, error) {
+	var ir menuItemRecipe
+	err := rowScanner.Scan(&ir.menuID, &ir.item.ID, &ir.item.Name, &ir.item.Price, &ir.recipe.ID, &ir.recipe.Units)
+	return ir, err
+}
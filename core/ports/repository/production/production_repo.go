@@ -0,0 +1,52 @@
+package productionrepo
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	"costly/core/ports/database"
+	"database/sql"
+)
+
+type ProductionRepository interface {
+	Add(ctx context.Context, production *model.Production) error
+	Find(ctx context.Context, productionID int64) (model.Production, error)
+}
+
+type repository struct {
+	db database.Database
+}
+
+func New(db database.Database) ProductionRepository {
+	return &repository{db}
+}
+
+func (r *repository) Add(ctx context.Context, production *model.Production) error {
+	result, err := r.db.ExecContext(ctx, "INSERT INTO production_history (recipe_id, ingredient_id, batches, units, price, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		production.RecipeID, production.IngredientID, production.Batches, production.Units, production.Price, production.CreatedAt)
+	if err != nil {
+		return err
+	}
+	productionID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	production.ID = productionID
+	return nil
+}
+
+func (r *repository) Find(ctx context.Context, productionID int64) (model.Production, error) {
+	production, err := database.QueryRowAndMap(ctx, r.db, mapToProduction, "SELECT * FROM production_history WHERE id = ?", productionID)
+	if err == sql.ErrNoRows {
+		return model.Production{}, errs.ErrNotFound
+	} else if err != nil {
+		return model.Production{}, err
+	}
+	return production, nil
+}
+
+func mapToProduction(rowScanner database.RowScanner) (model.Production, error) {
+	var production model.Production
+	err := rowScanner.Scan(&production.ID, &production.RecipeID, &production.IngredientID, &production.Batches, &production.Units, &production.Price, &production.CreatedAt)
+	return production, err
+}
@@ -5,11 +5,21 @@ import (
 	"costly/core/errs"
 	"costly/core/model"
 	"costly/core/ports/database"
+	searchrepo "costly/core/ports/repository/search"
+	"encoding/json"
 	"time"
 )
 
+// stepsColumn selects the steps of recipe r as a JSON array, so that they can be
+// read along with the rest of the recipe in a single query.
+const stepsColumn = "(SELECT json_group_array(description) FROM (SELECT description FROM recipe_step WHERE recipe_id = r.id ORDER BY position))"
+
+// tagsColumn selects the tags of recipe r as a JSON array, as stepsColumn does.
+const tagsColumn = "(SELECT json_group_array(tag) FROM (SELECT tag FROM recipe_tag WHERE recipe_id = r.id ORDER BY tag))"
+
 type RecipeRepository interface {
 	Add(ctx context.Context, recipe *model.Recipe) error
+	Update(ctx context.Context, recipeID int64, updateFunc func(recipe *model.Recipe) error) error
 	Find(ctx context.Context, id int64) (model.Recipe, error)
 	FindIngredients(ctx context.Context, recipeID int64) ([]model.RecipeIngredient, error)
 }
@@ -24,7 +34,8 @@ func New(db database.Database) RecipeRepository {
 
 func (r *repository) Add(ctx context.Context, recipe *model.Recipe) error {
 	return r.db.WithTx(ctx, func(tx database.Database) error {
-		result, err := tx.ExecContext(ctx, "INSERT INTO recipe (name, created_at, last_modified) VALUES (?, ?, ?)", recipe.Name, recipe.CreatedAt, recipe.LastModified)
+		result, err := tx.ExecContext(ctx, "INSERT INTO recipe (name, price, portions, plating_notes, prep_minutes, cook_minutes, category_id, created_at, last_modified) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			recipe.Name, recipe.Price, recipe.Portions, recipe.PlatingNotes, recipe.PrepMinutes, recipe.CookMinutes, recipe.CategoryID, recipe.CreatedAt, recipe.LastModified)
 		if err != nil {
 			return err
 		}
@@ -33,21 +44,81 @@ func (r *repository) Add(ctx context.Context, recipe *model.Recipe) error {
 			return err
 		}
 
-		for _, recipeIngredient := range recipe.Ingredients {
-			_, err := tx.ExecContext(ctx, "INSERT INTO recipe_ingredient (recipe_id, ingredient_id, units) VALUES (?, ?, ?)", recipeID, recipeIngredient.ID, recipeIngredient.Units)
-			if err != nil {
-				return err
-			}
+		if err := addIngredientsStepsAndTags(ctx, tx, recipeID, recipe); err != nil {
+			return err
+		}
+		if err := searchrepo.IndexRecipe(ctx, tx, recipeID); err != nil {
+			return err
 		}
 
 		recipe.ID = recipeID
+		recipe.Version = 1
 		return nil
 	})
 }
 
+func (r *repository) Update(ctx context.Context, recipeID int64, updateFunc func(recipe *model.Recipe) error) error {
+	return r.db.WithTx(ctx, func(tx database.Database) error {
+		recipe, err := New(tx).Find(ctx, recipeID)
+		if err != nil {
+			return err
+		}
+		if err := updateFunc(&recipe); err != nil {
+			return err
+		}
+		// the version is checked again as the recipe may have been changed since
+		// it was found
+		result, err := tx.ExecContext(ctx, "UPDATE recipe SET name = ?, price = ?, portions = ?, plating_notes = ?, prep_minutes = ?, cook_minutes = ?, category_id = ?, last_modified = ?, version = version + 1 WHERE id = ? AND version = ?",
+			recipe.Name, recipe.Price, recipe.Portions, recipe.PlatingNotes, recipe.PrepMinutes, recipe.CookMinutes, recipe.CategoryID, recipe.LastModified, recipe.ID, recipe.Version)
+		if err != nil {
+			return err
+		}
+		if rowsAffected, err := result.RowsAffected(); err != nil {
+			return err
+		} else if rowsAffected == 0 {
+			return errs.NewStaleError("recipe")
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM recipe_ingredient WHERE recipe_id = ?", recipe.ID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM recipe_step WHERE recipe_id = ?", recipe.ID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM recipe_tag WHERE recipe_id = ?", recipe.ID); err != nil {
+			return err
+		}
+		if err := addIngredientsStepsAndTags(ctx, tx, recipe.ID, &recipe); err != nil {
+			return err
+		}
+		return searchrepo.IndexRecipe(ctx, tx, recipe.ID)
+	})
+}
+
+func addIngredientsStepsAndTags(ctx context.Context, tx database.Database, recipeID int64, recipe *model.Recipe) error {
+	for _, recipeIngredient := range recipe.Ingredients {
+		_, err := tx.ExecContext(ctx, "INSERT INTO recipe_ingredient (recipe_id, ingredient_id, units) VALUES (?, ?, ?)", recipeID, recipeIngredient.ID, recipeIngredient.Units)
+		if err != nil {
+			return err
+		}
+	}
+
+	for position, step := range recipe.Steps {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO recipe_step (recipe_id, position, description) VALUES (?, ?, ?)", recipeID, position, step); err != nil {
+			return err
+		}
+	}
+
+	for _, tag := range recipe.Tags {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO recipe_tag (recipe_id, tag) VALUES (?, ?)", recipeID, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *repository) Find(ctx context.Context, id int64) (model.Recipe, error) {
 	// This was carefully made to make only one query when selecting only one recipe.
-	recipeWithIngredients, err := database.QueryAndMap(ctx, r.db, mapToRecipeWithIngredientsDB, "SELECT r.*, ri.ingredient_id, ri.units FROM recipe r JOIN recipe_ingredient ri ON r.id = ri.recipe_id WHERE r.id = ?", id)
+	recipeWithIngredients, err := database.QueryAndMap(ctx, r.db, mapToRecipeWithIngredientsDB, "SELECT r.id, r.name, r.price, r.portions, r.plating_notes, r.prep_minutes, r.cook_minutes, "+stepsColumn+", r.category_id, "+tagsColumn+", r.created_at, r.last_modified, r.version, ri.ingredient_id, ri.units FROM recipe r JOIN recipe_ingredient ri ON r.id = ri.recipe_id WHERE r.id = ?", id)
 	if err != nil {
 		return model.Recipe{}, err
 	}
@@ -59,11 +130,16 @@ func (r *repository) Find(ctx context.Context, id int64) (model.Recipe, error) {
 		recipeIngredients = append(recipeIngredients, model.RecipeIngredient{ID: ri.ingredientId, Units: ri.units})
 	}
 	return model.Recipe{
-		ID:           recipeWithIngredients[0].id,
-		Name:         recipeWithIngredients[0].name,
-		Ingredients:  recipeIngredients,
-		CreatedAt:    recipeWithIngredients[0].createdAt,
-		LastModified: recipeWithIngredients[0].lastModified,
+		ID:             recipeWithIngredients[0].id,
+		Name:           recipeWithIngredients[0].name,
+		Price:          recipeWithIngredients[0].price,
+		Portions:       recipeWithIngredients[0].portions,
+		Ingredients:    recipeIngredients,
+		RecipeMethod:   recipeWithIngredients[0].method,
+		Classification: recipeWithIngredients[0].classification,
+		CreatedAt:      recipeWithIngredients[0].createdAt,
+		LastModified:   recipeWithIngredients[0].lastModified,
+		Version:        recipeWithIngredients[0].version,
 	}, nil
 }
 
@@ -76,17 +152,35 @@ func (r *repository) FindIngredients(ctx context.Context, recipeID int64) ([]mod
 }
 
 type recipeWithIngredient struct {
-	id           int64
-	name         string
-	createdAt    time.Time
-	lastModified time.Time
-	ingredientId int64
-	units        int
+	id             int64
+	name           string
+	price          float64
+	portions       int
+	method         model.RecipeMethod
+	classification model.Classification
+	createdAt      time.Time
+	lastModified   time.Time
+	version        int
+	ingredientId   int64
+	units          int
 }
 
 func mapToRecipeWithIngredientsDB(rowScanner database.RowScanner) (recipeWithIngredient, error) {
 	var recipeWithIngredient recipeWithIngredient
-	return recipeWithIngredient, rowScanner.Scan(&recipeWithIngredient.id, &recipeWithIngredient.name, &recipeWithIngredient.createdAt, &recipeWithIngredient.lastModified, &recipeWithIngredient.ingredientId, &recipeWithIngredient.units)
+	var steps, tags string
+	method, classification := &recipeWithIngredient.method, &recipeWithIngredient.classification
+	if err := rowScanner.Scan(&recipeWithIngredient.id, &recipeWithIngredient.name, &recipeWithIngredient.price, &recipeWithIngredient.portions, &method.PlatingNotes, &method.PrepMinutes, &method.CookMinutes, &steps, &classification.CategoryID, &tags, &recipeWithIngredient.createdAt, &recipeWithIngredient.lastModified, &recipeWithIngredient.version, &recipeWithIngredient.ingredientId, &recipeWithIngredient.units); err != nil {
+		return recipeWithIngredient, err
+	}
+	if err := unmarshalList(steps, &method.Steps); err != nil {
+		return recipeWithIngredient, err
+	}
+	return recipeWithIngredient, unmarshalList(tags, &classification.Tags)
+}
+
+func unmarshalList(list string, dest *[]string) error {
+	*dest = []string{}
+	return json.Unmarshal([]byte(list), dest)
 }
 
 func mapToRecipeIngredient(rowScanner database.RowScanner) (model.RecipeIngredient, error) {
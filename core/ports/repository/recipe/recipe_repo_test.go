@@ -16,7 +16,8 @@ import (
 )
 
 func createDBWithIngredients(t *testing.T, logger logger.Logger, clock clock.Clock) database.Database {
-	db, _ := database.NewFromDatasource(":memory:", logger)
+	db, err := database.NewFromDatasource(":memory:", logger)
+	require.NoError(t, err)
 	ingredientUseCases := ingredients.New(db, clock)
 	ctx := context.Background()
 	var ingredientOpts = []ingredients.CreateIngredientOptions{
@@ -4,14 +4,52 @@ import (
 	"context"
 	"costly/core/model"
 	"costly/core/ports/database"
+	ingredientrepo "costly/core/ports/repository/ingredient"
+	"encoding/json"
+	"strings"
 	"time"
 )
 
 type RecipeViewRepository interface {
 	FindIngredients(ctx context.Context, recipeID int64) ([]model.RecipeIngredientView, error)
-	FindAll(ctx context.Context) ([]model.RecipeView, error)
+	FindAll(ctx context.Context, filter Filter) ([]model.RecipeView, error)
+	FindPage(ctx context.Context, filter Filter, opts model.PageOptions) (model.Page[model.RecipeView], error)
+	// FindRevisionIngredients gets the ingredients of a recipe revision priced as
+	// they were at the given moment.
+	FindRevisionIngredients(ctx context.Context, revisionID int64, asOf time.Time) ([]model.RecipeIngredientView, error)
 }
 
+type Filter struct {
+	// IDs keeps only the recipes with any of them if given.
+	IDs []int64
+	// Name keeps only recipes whose name contains it, ignoring case.
+	Name string
+	// ExcludedAllergens leaves out recipes having an ingredient with any of them.
+	ExcludedAllergens []model.Allergen
+	// CategoryID keeps only recipes in the category or any of its descendants.
+	CategoryID *int64
+	// Tags keeps only recipes having all of them.
+	Tags []string
+	// AsOf keeps only recipes that existed at that moment if given, and then
+	// excluded allergens are those of the revision active at that moment.
+	AsOf time.Time
+}
+
+const recipeColumns = "r.id, r.name, r.price, r.portions, r.plating_notes, r.prep_minutes, r.cook_minutes, (SELECT json_group_array(description) FROM (SELECT description FROM recipe_step WHERE recipe_id = r.id ORDER BY position)), r.category_id, (SELECT json_group_array(tag) FROM (SELECT tag FROM recipe_tag WHERE recipe_id = r.id ORDER BY tag)), r.created_at, r.last_modified"
+
+// costColumn sums the current cost of the ingredients of recipe r.
+const costColumn = "(SELECT COALESCE(SUM(i.price * ri.units), 0) FROM recipe_ingredient ri JOIN ingredient i ON i.id = ri.ingredient_id WHERE ri.recipe_id = r.id)"
+
+const recipeIngredientColumns = "i.id, i.name, i.unit, i.price, i.energy, i.protein, i.fat, i.saturated_fat, i.carbohydrate, i.sugar, i.salt, i.fibre, ri.units"
+
+// priceAsOfColumn selects the last price of ingredient i recorded before a given
+// moment, falling back to the price snapshotted in the revision.
+const priceAsOfColumn = "COALESCE((SELECT ph.price FROM ingredient_price_history ph WHERE ph.ingredient_id = i.id AND ph.created_at <= ? ORDER BY ph.created_at DESC, ph.id DESC LIMIT 1), rri.price)"
+
+// revisionAsOfColumn selects the revision of recipe r that was active at a
+// given moment.
+const revisionAsOfColumn = "(SELECT rv.id FROM recipe_revision rv WHERE rv.recipe_id = r.id AND rv.created_at <= ? ORDER BY rv.version DESC LIMIT 1)"
+
 type repository struct {
 	db database.Database
 }
@@ -21,52 +59,213 @@ func New(db database.Database) RecipeViewRepository {
 }
 
 func (r *repository) FindIngredients(ctx context.Context, recipeID int64) ([]model.RecipeIngredientView, error) {
-	recipeIngredients, err := database.QueryAndMap(ctx, r.db, mapToRecipeIngredientView, "SELECT i.*, ri.units FROM ingredient i JOIN recipe_ingredient ri ON i.id = ri.ingredient_id AND ri.recipe_id = ?", recipeID)
+	recipeIngredients, err := database.QueryAndMap(ctx, r.db, mapToRecipeIngredientView, "SELECT "+recipeIngredientColumns+" FROM ingredient i JOIN recipe_ingredient ri ON i.id = ri.ingredient_id AND ri.recipe_id = ?", recipeID)
+	if err != nil {
+		return nil, err
+	}
+	allergens, err := ingredientrepo.FindAllergens(ctx, r.db, "SELECT ia.* FROM ingredient_allergen ia JOIN recipe_ingredient ri ON ia.ingredient_id = ri.ingredient_id AND ri.recipe_id = ?", recipeID)
+	if err != nil {
+		return nil, err
+	}
+	return withAllergens(recipeIngredients, allergens), nil
+}
+
+func (r *repository) FindRevisionIngredients(ctx context.Context, revisionID int64, asOf time.Time) ([]model.RecipeIngredientView, error) {
+	recipeIngredients, err := database.QueryAndMap(ctx, r.db, mapToRecipeIngredientView, "SELECT i.id, rri.name, i.unit, "+priceAsOfColumn+", i.energy, i.protein, i.fat, i.saturated_fat, i.carbohydrate, i.sugar, i.salt, i.fibre, rri.units FROM recipe_revision_ingredient rri JOIN ingredient i ON i.id = rri.ingredient_id WHERE rri.revision_id = ? ORDER BY i.id", asOf, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	allergens, err := ingredientrepo.FindAllergens(ctx, r.db, "SELECT ia.* FROM ingredient_allergen ia JOIN recipe_revision_ingredient rri ON ia.ingredient_id = rri.ingredient_id AND rri.revision_id = ?", revisionID)
+	if err != nil {
+		return nil, err
+	}
+	return withAllergens(recipeIngredients, allergens), nil
+}
+
+func (r *repository) FindAll(ctx context.Context, filter Filter) ([]model.RecipeView, error) {
+	conditions, args := filter.conditions()
+	query := "SELECT " + recipeColumns + " FROM recipe r"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	recipesDB, err := database.QueryAndMap(ctx, r.db, mapToRecipeDB, query, args...)
 	if err != nil {
 		return nil, err
 	}
-	return recipeIngredients, nil
+	return r.views(ctx, recipesDB)
+}
+
+func (r *repository) FindPage(ctx context.Context, filter Filter, opts model.PageOptions) (model.Page[model.RecipeView], error) {
+	conditions, args := filter.conditions()
+	page, err := database.QueryPage(ctx, r.db, mapToRecipeDB, database.PageQuery{
+		Select:     "SELECT " + recipeColumns,
+		From:       "FROM recipe r",
+		Conditions: conditions,
+		Args:       args,
+		ID:         "r.id",
+		SortBy: map[model.SortField]string{
+			model.SortByName:         "r.name",
+			model.SortByPrice:        "r.price",
+			model.SortByCost:         costColumn,
+			model.SortByLastModified: "r.last_modified",
+		},
+	}, opts)
+	if err != nil {
+		return model.Page[model.RecipeView]{}, err
+	}
+	recipes, err := r.views(ctx, page.Data)
+	if err != nil {
+		return model.Page[model.RecipeView]{}, err
+	}
+	return model.Page[model.RecipeView]{Data: recipes, NextCursor: page.NextCursor}, nil
+}
+
+func (filter Filter) conditions() ([]string, []any) {
+	conditions, args := []string{}, []any{}
+	if len(filter.IDs) > 0 {
+		conditions = append(conditions, "r.id IN ("+placeholders(len(filter.IDs))+")")
+		for _, id := range filter.IDs {
+			args = append(args, id)
+		}
+	}
+	if filter.Name != "" {
+		conditions = append(conditions, "r.name LIKE ? ESCAPE '\\'")
+		args = append(args, database.ContainsPattern(filter.Name))
+	}
+	if !filter.AsOf.IsZero() {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM recipe_revision rv WHERE rv.recipe_id = r.id AND rv.created_at <= ?)")
+		args = append(args, filter.AsOf)
+	}
+	if len(filter.ExcludedAllergens) > 0 && filter.AsOf.IsZero() {
+		conditions = append(conditions, "NOT EXISTS (SELECT 1 FROM recipe_ingredient ri JOIN ingredient_allergen ia ON ri.ingredient_id = ia.ingredient_id WHERE ri.recipe_id = r.id AND ia.allergen IN ("+placeholders(len(filter.ExcludedAllergens))+"))")
+		for _, allergen := range filter.ExcludedAllergens {
+			args = append(args, allergen)
+		}
+	} else if len(filter.ExcludedAllergens) > 0 {
+		conditions = append(conditions, "NOT EXISTS (SELECT 1 FROM recipe_revision_ingredient rri JOIN ingredient_allergen ia ON rri.ingredient_id = ia.ingredient_id WHERE rri.revision_id = "+revisionAsOfColumn+" AND ia.allergen IN ("+placeholders(len(filter.ExcludedAllergens))+"))")
+		args = append(args, filter.AsOf)
+		for _, allergen := range filter.ExcludedAllergens {
+			args = append(args, allergen)
+		}
+	}
+	if filter.CategoryID != nil {
+		conditions = append(conditions, "r.category_id IN (WITH RECURSIVE descendant(id) AS (SELECT ? UNION SELECT c.id FROM category c JOIN descendant d ON c.parent_id = d.id) SELECT id FROM descendant)")
+		args = append(args, *filter.CategoryID)
+	}
+	for _, tag := range filter.Tags {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM recipe_tag rt WHERE rt.recipe_id = r.id AND rt.tag = ?)")
+		args = append(args, tag)
+	}
+	return conditions, args
 }
 
-func (r *repository) FindAll(ctx context.Context) ([]model.RecipeView, error) {
-	recipesDB, err := database.QueryAndMap(ctx, r.db, mapToRecipeDB, "SELECT * FROM recipe")
+// views gets the ingredients and attachments of the recipes in a constant
+// number of queries, however many recipes there are.
+func (r *repository) views(ctx context.Context, recipesDB []recipeDB) ([]model.RecipeView, error) {
+	ids := []int64{}
+	for _, recipeDB := range recipesDB {
+		ids = append(ids, recipeDB.id)
+	}
+	idList := database.IDList(ids)
+	ingredients, err := database.QueryAndMap(ctx, r.db, mapToRecipeIngredient, "SELECT ri.recipe_id, "+recipeIngredientColumns+" FROM json_each(?) ids JOIN recipe_ingredient ri ON ri.recipe_id = ids.value JOIN ingredient i ON i.id = ri.ingredient_id ORDER BY ri.recipe_id, ri.ingredient_id", idList)
+	if err != nil {
+		return nil, err
+	}
+	allergens, err := ingredientrepo.FindAllergens(ctx, r.db, "SELECT DISTINCT ia.* FROM ingredient_allergen ia JOIN recipe_ingredient ri ON ia.ingredient_id = ri.ingredient_id WHERE ri.recipe_id IN (SELECT value FROM json_each(?))", idList)
+	if err != nil {
+		return nil, err
+	}
+	recipeIngredients := map[int64][]model.RecipeIngredientView{}
+	for _, ingredient := range ingredients {
+		recipeIngredients[ingredient.recipeID] = append(recipeIngredients[ingredient.recipeID], ingredient.RecipeIngredientView)
+	}
+	attachments, err := database.QueryAndMap(ctx, r.db, mapToRecipeAttachment, "SELECT * FROM recipe_attachment WHERE recipe_id IN (SELECT value FROM json_each(?)) ORDER BY id", idList)
 	if err != nil {
 		return nil, err
 	}
+	recipeAttachments := map[int64][]model.RecipeAttachment{}
+	for _, attachment := range attachments {
+		recipeAttachments[attachment.RecipeID] = append(recipeAttachments[attachment.RecipeID], attachment)
+	}
 	recipes := []model.RecipeView{}
 	for _, recipeDB := range recipesDB {
-		recipeIngredients, err := database.QueryAndMap(ctx, r.db, mapToRecipeIngredientView, "SELECT i.*, ri.units FROM ingredient i JOIN recipe_ingredient ri ON i.id = ri.ingredient_id AND ri.recipe_id = ?", recipeDB.id)
-		if err != nil {
-			return nil, err
-		}
 		recipes = append(recipes, model.RecipeView{
-			ID:           recipeDB.id,
-			Name:         recipeDB.name,
-			Ingredients:  recipeIngredients,
-			CreatedAt:    recipeDB.createdAt,
-			LastModified: recipeDB.lastModified,
+			ID:             recipeDB.id,
+			Name:           recipeDB.name,
+			Price:          recipeDB.price,
+			Portions:       recipeDB.portions,
+			Ingredients:    withAllergens(ingredientsOf(recipeIngredients, recipeDB.id), allergens),
+			RecipeMethod:   recipeDB.method,
+			Classification: recipeDB.classification,
+			Attachments:    attachmentsOf(recipeAttachments, recipeDB.id),
+			CreatedAt:      recipeDB.createdAt,
+			LastModified:   recipeDB.lastModified,
 		})
 	}
 	return recipes, nil
 }
 
+type recipeIngredient struct {
+	recipeID int64
+	model.RecipeIngredientView
+}
+
+func mapToRecipeIngredient(rowScanner database.RowScanner) (recipeIngredient, error) {
+	var ingredient recipeIngredient
+	view, err := mapToRecipeIngredientView(recipeIDScanner{rowScanner, &ingredient.recipeID})
+	ingredient.RecipeIngredientView = view
+	return ingredient, err
+}
+
+// recipeIDScanner scans the recipe id before the columns of the ingredient.
+type recipeIDScanner struct {
+	database.RowScanner
+	recipeID *int64
+}
+
+func (s recipeIDScanner) Scan(dest ...any) error {
+	return s.RowScanner.Scan(append([]any{s.recipeID}, dest...)...)
+}
+
+func ingredientsOf(ingredients map[int64][]model.RecipeIngredientView, recipeID int64) []model.RecipeIngredientView {
+	if recipeIngredients, ok := ingredients[recipeID]; ok {
+		return recipeIngredients
+	}
+	return []model.RecipeIngredientView{}
+}
+
 func mapToRecipeIngredientView(rowScanner database.RowScanner) (model.RecipeIngredientView, error) {
-	var ingredient model.Ingredient
-	var recipeUnits int
-	err := rowScanner.Scan(&ingredient.ID, &ingredient.Name, &ingredient.Unit, &ingredient.Price, &ingredient.CreatedAt, &ingredient.LastModified, &ingredient.UnitsInStock, &recipeUnits)
-	return model.RecipeIngredientView{
-		ID:    ingredient.ID,
-		Name:  ingredient.Name,
-		Price: ingredient.Price,
-		Units: recipeUnits,
-	}, err
+	var ingredient model.RecipeIngredientView
+	nutrition := &ingredient.Nutrition
+	err := rowScanner.Scan(&ingredient.ID, &ingredient.Name, &ingredient.Unit, &ingredient.Price,
+		&nutrition.Energy, &nutrition.Protein, &nutrition.Fat, &nutrition.SaturatedFat, &nutrition.Carbohydrate, &nutrition.Sugar, &nutrition.Salt, &nutrition.Fibre,
+		&ingredient.Units)
+	return ingredient, err
+}
+
+func withAllergens(recipeIngredients []model.RecipeIngredientView, allergens map[int64][]model.Allergen) []model.RecipeIngredientView {
+	for i := range recipeIngredients {
+		recipeIngredients[i].Allergens = []model.Allergen{}
+		if ingredientAllergens, ok := allergens[recipeIngredients[i].ID]; ok {
+			recipeIngredients[i].Allergens = ingredientAllergens
+		}
+	}
+	return recipeIngredients
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
 }
 
 type recipeDB struct {
-	id           int64
-	name         string
-	createdAt    time.Time
-	lastModified time.Time
+	id             int64
+	name           string
+	price          float64
+	portions       int
+	method         model.RecipeMethod
+	classification model.Classification
+	createdAt      time.Time
+	lastModified   time.Time
 }
 
 type recipeViewDB struct {
@@ -78,6 +277,27 @@ type recipeViewDB struct {
 
 func mapToRecipeDB(rowScanner database.RowScanner) (recipeDB, error) {
 	var recipe recipeDB
-	err := rowScanner.Scan(&recipe.id, &recipe.name, &recipe.createdAt, &recipe.lastModified)
-	return recipe, err
+	var steps, tags string
+	method, classification := &recipe.method, &recipe.classification
+	if err := rowScanner.Scan(&recipe.id, &recipe.name, &recipe.price, &recipe.portions, &method.PlatingNotes, &method.PrepMinutes, &method.CookMinutes, &steps, &classification.CategoryID, &tags, &recipe.createdAt, &recipe.lastModified); err != nil {
+		return recipe, err
+	}
+	method.Steps, classification.Tags = []string{}, []string{}
+	if err := json.Unmarshal([]byte(steps), &method.Steps); err != nil {
+		return recipe, err
+	}
+	return recipe, json.Unmarshal([]byte(tags), &classification.Tags)
+}
+
+func attachmentsOf(attachments map[int64][]model.RecipeAttachment, recipeID int64) []model.RecipeAttachment {
+	if recipeAttachments, ok := attachments[recipeID]; ok {
+		return recipeAttachments
+	}
+	return []model.RecipeAttachment{}
+}
+
+func mapToRecipeAttachment(rowScanner database.RowScanner) (model.RecipeAttachment, error) {
+	var attachment model.RecipeAttachment
+	err := rowScanner.Scan(&attachment.ID, &attachment.RecipeID, &attachment.FileName, &attachment.ContentType, &attachment.Size, &attachment.StorageKey, &attachment.CreatedAt)
+	return attachment, err
 }
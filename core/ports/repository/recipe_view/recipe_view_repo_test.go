@@ -1 +1,134 @@
 package recipeviewrepo_test
+
+import (
+	"context"
+	"costly/core/model"
+	"costly/core/ports/clock"
+	"costly/core/ports/database"
+	"costly/core/ports/logger"
+	ingredientrepo "costly/core/ports/repository/ingredient"
+	reciperepo "costly/core/ports/repository/recipe"
+	recipeviewrepo "costly/core/ports/repository/recipe_view"
+	"fmt"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ingredientsPerRecipe = 5
+
+// countingDB counts the queries made through it, including the ones in its
+// transactions.
+type countingDB struct {
+	database.Database
+	queries *int
+}
+
+func (db countingDB) QueryRowContext(ctx context.Context, query string, args ...any) database.RowScanner {
+	*db.queries++
+	return db.Database.QueryRowContext(ctx, query, args...)
+}
+
+func (db countingDB) QueryContext(ctx context.Context, query string, args ...any) (database.RowsScanner, error) {
+	*db.queries++
+	return db.Database.QueryContext(ctx, query, args...)
+}
+
+func (db countingDB) WithTx(ctx context.Context, op func(tx database.Database) error) error {
+	return db.Database.WithTx(ctx, func(tx database.Database) error {
+		return op(countingDB{tx, db.queries})
+	})
+}
+
+// seedDB makes a database with the given number of recipes, each one having a
+// few of a hundred ingredients with allergens.
+func seedDB(tb testing.TB, recipes int) database.Database {
+	logger, _ := logger.New("error")
+	db, err := database.NewFromDatasource(":memory:", logger)
+	require.NoError(tb, err)
+	now := clock.New().Now()
+	ctx := context.Background()
+	require.NoError(tb, db.WithTx(ctx, func(tx database.Database) error {
+		for i := range 100 {
+			ingredient, err := model.NewIngredient(fmt.Sprintf("ingredient%d", i), model.Gram, 0.01*float64(i+1), now)
+			if err != nil {
+				return err
+			}
+			ingredient.Allergens = []model.Allergen{model.EUAllergens[i%len(model.EUAllergens)]}
+			if err := ingredientrepo.New(tx).Add(ctx, ingredient); err != nil {
+				return err
+			}
+		}
+		for i := range recipes {
+			recipeIngredients := []model.RecipeIngredient{}
+			for j := range ingredientsPerRecipe {
+				recipeIngredients = append(recipeIngredients, model.RecipeIngredient{ID: int64((i+j*20)%100 + 1), Units: 100})
+			}
+			recipe, err := model.NewRecipe(fmt.Sprintf("recipe%d", i), recipeIngredients, now)
+			if err != nil {
+				return err
+			}
+			if err := reciperepo.New(tx).Add(ctx, recipe); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	return db
+}
+
+func TestFindAll(t *testing.T) {
+
+	t.Run("should load every recipe with its ingredients and allergens", func(t *testing.T) {
+		recipes, err := recipeviewrepo.New(seedDB(t, 30)).FindAll(context.Background(), recipeviewrepo.Filter{})
+		require.NoError(t, err)
+		require.Len(t, recipes, 30)
+		for _, recipe := range recipes {
+			assert.Len(t, recipe.Ingredients, ingredientsPerRecipe)
+			assert.NotEmpty(t, recipe.Allergens())
+		}
+		ids := []int64{}
+		for _, ingredient := range recipes[0].Ingredients {
+			ids = append(ids, ingredient.ID)
+		}
+		assert.Equal(t, []int64{1, 21, 41, 61, 81}, ids)
+	})
+
+	t.Run("should make the same number of queries however many recipes there are", func(t *testing.T) {
+		queriesFor := func(recipes int) int {
+			queries := 0
+			repository := recipeviewrepo.New(countingDB{seedDB(t, recipes), &queries})
+			found, err := repository.FindAll(context.Background(), recipeviewrepo.Filter{})
+			require.NoError(t, err)
+			require.Len(t, found, recipes)
+			return queries
+		}
+		assert.Equal(t, queriesFor(5), queriesFor(200))
+	})
+}
+
+func BenchmarkFindAll(b *testing.B) {
+	repository := recipeviewrepo.New(seedDB(b, 2000))
+	ctx := context.Background()
+	b.ResetTimer()
+	for range b.N {
+		if _, err := repository.FindAll(ctx, recipeviewrepo.Filter{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFindPage(b *testing.B) {
+	repository := recipeviewrepo.New(seedDB(b, 2000))
+	ctx := context.Background()
+	opts, err := model.NewPageOptions(model.MaxPageLimit, "", model.SortByCost, model.Descending, []model.SortField{model.SortByCost})
+	require.NoError(b, err)
+	b.ResetTimer()
+	for range b.N {
+		if _, err := repository.FindPage(ctx, recipeviewrepo.Filter{}, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -3,19 +3,33 @@ package repo
 import (
 	"context"
 	"costly/core/ports/database"
+	archiverepo "costly/core/ports/repository/archive"
+	attachmentrepo "costly/core/ports/repository/attachment"
+	categoryrepo "costly/core/ports/repository/category"
 	ingredientrepo "costly/core/ports/repository/ingredient"
+	menurepo "costly/core/ports/repository/menu"
+	productionrepo "costly/core/ports/repository/production"
 	reciperepo "costly/core/ports/repository/recipe"
 	recipeviewrepo "costly/core/ports/repository/recipe_view"
+	revisionrepo "costly/core/ports/repository/revision"
 	salesrepo "costly/core/ports/repository/sales"
+	searchrepo "costly/core/ports/repository/search"
 	stockrepo "costly/core/ports/repository/stock"
 )
 
 type Repository interface {
+	Archives() archiverepo.ArchiveRepository
+	Categories() categoryrepo.CategoryRepository
 	IngredientStocks() stockrepo.IngredientStockRepository
 	Ingredients() ingredientrepo.IngredientRepository
+	Menus() menurepo.MenuRepository
+	Productions() productionrepo.ProductionRepository
 	Recipes() reciperepo.RecipeRepository
 	RecipeSales() salesrepo.RecipeSalesRepository
 	RecipeViews() recipeviewrepo.RecipeViewRepository
+	RecipeAttachments() attachmentrepo.RecipeAttachmentRepository
+	RecipeRevisions() revisionrepo.RecipeRevisionRepository
+	Search() searchrepo.SearchRepository
 	Atomic(ctx context.Context, fn func(repo Repository) error) error
 }
 
@@ -28,6 +42,14 @@ func New(db database.Database) Repository {
 	return &repository{db, db}
 }
 
+func (r *repository) Archives() archiverepo.ArchiveRepository {
+	return archiverepo.New(r.session)
+}
+
+func (r *repository) Categories() categoryrepo.CategoryRepository {
+	return categoryrepo.New(r.session)
+}
+
 func (r *repository) IngredientStocks() stockrepo.IngredientStockRepository {
 	return stockrepo.New(r.session)
 }
@@ -36,6 +58,14 @@ func (r *repository) Ingredients() ingredientrepo.IngredientRepository {
 	return ingredientrepo.New(r.session)
 }
 
+func (r *repository) Menus() menurepo.MenuRepository {
+	return menurepo.New(r.session)
+}
+
+func (r *repository) Productions() productionrepo.ProductionRepository {
+	return productionrepo.New(r.session)
+}
+
 func (r *repository) Recipes() reciperepo.RecipeRepository {
 	return reciperepo.New(r.session)
 }
@@ -48,8 +78,22 @@ func (r *repository) RecipeViews() recipeviewrepo.RecipeViewRepository {
 	return recipeviewrepo.New(r.session)
 }
 
+func (r *repository) RecipeAttachments() attachmentrepo.RecipeAttachmentRepository {
+	return attachmentrepo.New(r.session)
+}
+
+func (r *repository) RecipeRevisions() revisionrepo.RecipeRevisionRepository {
+	return revisionrepo.New(r.session)
+}
+
+func (r *repository) Search() searchrepo.SearchRepository {
+	return searchrepo.New(r.session)
+}
+
+// Atomic runs fn within a transaction, or within a savepoint of the current
+// one when the repository is already in a transaction.
 func (r *repository) Atomic(ctx context.Context, fn func(repo Repository) error) (err error) {
-	return r.db.WithTx(ctx, func(tx database.Database) error {
+	return r.session.WithTx(ctx, func(tx database.Database) error {
 		newRepo := &repository{
 			db: r.db,
 			// injecting the new trx handle
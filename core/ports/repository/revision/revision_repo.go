@@ -0,0 +1,128 @@
+package revisionrepo
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	"costly/core/ports/database"
+	"database/sql"
+	"time"
+)
+
+type RecipeRevisionRepository interface {
+	// Add stores the revision as the next version of its recipe.
+	Add(ctx context.Context, revision *model.RecipeRevision) error
+	Find(ctx context.Context, recipeID int64, version int) (model.RecipeRevision, error)
+	FindAll(ctx context.Context, recipeID int64) ([]model.RecipeRevision, error)
+	// FindAsOf gets the revision of the recipe that was active at the given moment.
+	FindAsOf(ctx context.Context, recipeID int64, asOf time.Time) (model.RecipeRevision, error)
+}
+
+type repository struct {
+	db database.Database
+}
+
+func New(db database.Database) RecipeRevisionRepository {
+	return &repository{db}
+}
+
+func (r *repository) Add(ctx context.Context, revision *model.RecipeRevision) error {
+	return r.db.WithTx(ctx, func(tx database.Database) error {
+		var version int
+		if err := tx.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) + 1 FROM recipe_revision WHERE recipe_id = ?", revision.RecipeID).Scan(&version); err != nil {
+			return err
+		}
+		result, err := tx.ExecContext(ctx, "INSERT INTO recipe_revision (recipe_id, version, name, portions, cost, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+			revision.RecipeID, version, revision.Name, revision.Portions, revision.Cost, revision.CreatedAt)
+		if err != nil {
+			return err
+		}
+		revisionID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		for _, ingredient := range revision.Ingredients {
+			if _, err := tx.ExecContext(ctx, "INSERT INTO recipe_revision_ingredient (revision_id, ingredient_id, name, price, units) VALUES (?, ?, ?, ?, ?)",
+				revisionID, ingredient.ID, ingredient.Name, ingredient.Price, ingredient.Units); err != nil {
+				return err
+			}
+		}
+		revision.ID = revisionID
+		revision.Version = version
+		return nil
+	})
+}
+
+func (r *repository) Find(ctx context.Context, recipeID int64, version int) (model.RecipeRevision, error) {
+	return r.find(ctx, "WHERE recipe_id = ? AND version = ?", recipeID, version)
+}
+
+func (r *repository) FindAsOf(ctx context.Context, recipeID int64, asOf time.Time) (model.RecipeRevision, error) {
+	return r.find(ctx, "WHERE recipe_id = ? AND created_at <= ? ORDER BY version DESC LIMIT 1", recipeID, asOf)
+}
+
+func (r *repository) find(ctx context.Context, where string, args ...any) (model.RecipeRevision, error) {
+	revision, err := database.QueryRowAndMap(ctx, r.db, mapToRecipeRevision, "SELECT * FROM recipe_revision "+where, args...)
+	if err == sql.ErrNoRows {
+		return model.RecipeRevision{}, errs.ErrNotFound
+	} else if err != nil {
+		return model.RecipeRevision{}, err
+	}
+	ingredients, err := r.findIngredients(ctx, "WHERE revision_id = ?", revision.ID)
+	if err != nil {
+		return model.RecipeRevision{}, err
+	}
+	revision.Ingredients = ingredientsOf(ingredients, revision.ID)
+	return revision, nil
+}
+
+func (r *repository) FindAll(ctx context.Context, recipeID int64) ([]model.RecipeRevision, error) {
+	revisions, err := database.QueryAndMap(ctx, r.db, mapToRecipeRevision, "SELECT * FROM recipe_revision WHERE recipe_id = ? ORDER BY version", recipeID)
+	if err != nil {
+		return nil, err
+	}
+	ingredients, err := r.findIngredients(ctx, "WHERE revision_id IN (SELECT id FROM recipe_revision WHERE recipe_id = ?)", recipeID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range revisions {
+		revisions[i].Ingredients = ingredientsOf(ingredients, revisions[i].ID)
+	}
+	return revisions, nil
+}
+
+type revisionIngredient struct {
+	revisionID int64
+	model.RecipeRevisionIngredient
+}
+
+func (r *repository) findIngredients(ctx context.Context, where string, args ...any) (map[int64][]model.RecipeRevisionIngredient, error) {
+	revisionIngredients, err := database.QueryAndMap(ctx, r.db, mapToRevisionIngredient, "SELECT revision_id, ingredient_id, name, price, units FROM recipe_revision_ingredient "+where+" ORDER BY ingredient_id", args...)
+	if err != nil {
+		return nil, err
+	}
+	ingredients := map[int64][]model.RecipeRevisionIngredient{}
+	for _, ri := range revisionIngredients {
+		ingredients[ri.revisionID] = append(ingredients[ri.revisionID], ri.RecipeRevisionIngredient)
+	}
+	return ingredients, nil
+}
+
+func ingredientsOf(ingredients map[int64][]model.RecipeRevisionIngredient, revisionID int64) []model.RecipeRevisionIngredient {
+	if revisionIngredients, ok := ingredients[revisionID]; ok {
+		return revisionIngredients
+	}
+	return []model.RecipeRevisionIngredient{}
+}
+
+func mapToRecipeRevision(rowScanner database.RowScanner) (model.RecipeRevision, error) {
+	var revision model.RecipeRevision
+	err := rowScanner.Scan(&revision.ID, &revision.RecipeID, &revision.Version, &revision.Name, &revision.Portions, &revision.Cost, &revision.CreatedAt)
+	return revision, err
+}
+
+func mapToRevisionIngredient(rowScanner database.RowScanner) (revisionIngredient, error) {
+	var ri revisionIngredient
+	err := rowScanner.Scan(&ri.revisionID, &ri.ID, &ri.Name, &ri.Price, &ri.Units)
+	return ri, err
+}
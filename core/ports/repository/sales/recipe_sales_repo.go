@@ -2,12 +2,18 @@ package salesrepo
 
 import (
 	"context"
+	"costly/core/errs"
 	"costly/core/model"
 	"costly/core/ports/database"
+	"database/sql"
+	"time"
 )
 
 type RecipeSalesRepository interface {
 	Add(ctx context.Context, recipeSales *model.RecipeSales) error
+	// SoldUnits gets the units sold since the given moment of every recipe with
+	// any sales then.
+	SoldUnits(ctx context.Context, since time.Time) (map[int64]int, error)
 }
 
 type repository struct {
@@ -18,11 +24,21 @@ func New(db database.Database) RecipeSalesRepository {
 	return &repository{db}
 }
 
+// Add stores the sales against the revision of the recipe active at the time
+// they were made.
 func (r *repository) Add(ctx context.Context, recipeSales *model.RecipeSales) error {
-	result, err := r.db.ExecContext(ctx, "INSERT INTO sold_recipes_history (recipe_id, units, created_at) VALUES (?, ?, ?)", recipeSales.RecipeID, recipeSales.Units, recipeSales.CreatedAt)
+	var revisionID int64
+	err := r.db.QueryRowContext(ctx, "SELECT id FROM recipe_revision WHERE recipe_id = ? AND created_at <= ? ORDER BY version DESC LIMIT 1", recipeSales.RecipeID, recipeSales.CreatedAt).Scan(&revisionID)
+	if err == sql.ErrNoRows {
+		return errs.ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx, "INSERT INTO sold_recipes_history (recipe_id, revision_id, units, created_at) VALUES (?, ?, ?, ?)", recipeSales.RecipeID, revisionID, recipeSales.Units, recipeSales.CreatedAt)
 	if err != nil {
 		return err
 	}
+	recipeSales.RevisionID = revisionID
 	recipeSalesID, err := result.LastInsertId()
 	if err != nil {
 		return err
@@ -30,3 +46,26 @@ func (r *repository) Add(ctx context.Context, recipeSales *model.RecipeSales) er
 	recipeSales.ID = recipeSalesID
 	return nil
 }
+
+type recipeSoldUnits struct {
+	recipeID int64
+	units    int
+}
+
+func (r *repository) SoldUnits(ctx context.Context, since time.Time) (map[int64]int, error) {
+	sales, err := database.QueryAndMap(ctx, r.db, mapToRecipeSoldUnits, "SELECT recipe_id, SUM(units) FROM sold_recipes_history WHERE created_at >= ? GROUP BY recipe_id", since)
+	if err != nil {
+		return nil, err
+	}
+	soldUnits := map[int64]int{}
+	for _, sale := range sales {
+		soldUnits[sale.recipeID] = sale.units
+	}
+	return soldUnits, nil
+}
+
+func mapToRecipeSoldUnits(rowScanner database.RowScanner) (recipeSoldUnits, error) {
+	var sale recipeSoldUnits
+	err := rowScanner.Scan(&sale.recipeID, &sale.units)
+	return sale, err
+}
@@ -0,0 +1,63 @@
+package searchrepo
+
+import (
+	"context"
+	"costly/core/model"
+	"costly/core/ports/database"
+	"strings"
+)
+
+// SearchRepository searches the full-text index of ingredients and recipes.
+// The index is an SQLite FTS5 table per entity whose tokenizer folds case and
+// diacritics, so go-sqlite3 must be built with the sqlite_fts5 tag.
+type SearchRepository interface {
+	// Search gets the ingredients and recipes matching all the terms, each one
+	// as a prefix of any word in them.
+	Search(ctx context.Context, terms []string) ([]model.SearchResult, error)
+}
+
+type repository struct {
+	db database.Database
+}
+
+func New(db database.Database) SearchRepository {
+	return &repository{db}
+}
+
+func (r *repository) Search(ctx context.Context, terms []string) ([]model.SearchResult, error) {
+	prefixes := []string{}
+	for _, term := range terms {
+		prefixes = append(prefixes, `"`+term+`"*`)
+	}
+	match := strings.Join(prefixes, " ")
+	return database.QueryAndMap(ctx, r.db, mapToSearchResult,
+		"SELECT 'ingredient', i.id, i.name FROM ingredient_search s JOIN ingredient i ON i.id = s.rowid WHERE ingredient_search MATCH ? "+
+			"UNION ALL SELECT 'recipe', r.id, r.name FROM recipe_search s JOIN recipe r ON r.id = s.rowid WHERE recipe_search MATCH ? "+
+			"ORDER BY 1, 3, 2", match, match)
+}
+
+// IndexIngredient indexes the ingredient as it is stored, replacing any
+// previous entry. It must be called whenever its name or tags change.
+func IndexIngredient(ctx context.Context, db database.Database, ingredientID int64) error {
+	if _, err := db.ExecContext(ctx, "DELETE FROM ingredient_search WHERE rowid = ?", ingredientID); err != nil {
+		return err
+	}
+	_, err := db.ExecContext(ctx, "INSERT INTO ingredient_search (rowid, name, tags) SELECT i.id, i.name, COALESCE((SELECT group_concat(it.tag, ' ') FROM ingredient_tag it WHERE it.ingredient_id = i.id), '') FROM ingredient i WHERE i.id = ?", ingredientID)
+	return err
+}
+
+// IndexRecipe indexes the recipe as it is stored, replacing any previous
+// entry. It must be called whenever its name, tags or steps change.
+func IndexRecipe(ctx context.Context, db database.Database, recipeID int64) error {
+	if _, err := db.ExecContext(ctx, "DELETE FROM recipe_search WHERE rowid = ?", recipeID); err != nil {
+		return err
+	}
+	_, err := db.ExecContext(ctx, "INSERT INTO recipe_search (rowid, name, tags, steps) SELECT r.id, r.name, COALESCE((SELECT group_concat(rt.tag, ' ') FROM recipe_tag rt WHERE rt.recipe_id = r.id), ''), COALESCE((SELECT group_concat(rs.description, ' ') FROM recipe_step rs WHERE rs.recipe_id = r.id), '') FROM recipe r WHERE r.id = ?", recipeID)
+	return err
+}
+
+func mapToSearchResult(rowScanner database.RowScanner) (model.SearchResult, error) {
+	var result model.SearchResult
+	err := rowScanner.Scan(&result.Kind, &result.ID, &result.Name)
+	return result, err
+}
@@ -6,8 +6,7 @@ import (
 	"costly/core/model"
 	"costly/core/ports/database"
 	"database/sql"
-
-	"github.com/mattn/go-sqlite3"
+	"errors"
 )
 
 type IngredientStockRepository interface {
@@ -26,10 +25,8 @@ func New(db database.Database) IngredientStockRepository {
 func (r *repository) Add(ctx context.Context, ingredientStock *model.IngredientStock) error {
 	result, err := r.db.ExecContext(ctx, "INSERT INTO stock_history (ingredient_id, units, price, created_at) VALUES (?, ?, ?, ?)", ingredientStock.IngredientID, ingredientStock.Units, ingredientStock.Price, ingredientStock.CreatedAt)
 	if err != nil {
-		if sqlError, ok := err.(sqlite3.Error); ok {
-			if sqlError.ExtendedCode == sqlite3.ErrConstraintForeignKey {
-				return errs.ErrNotFound
-			}
+		if errors.Is(err, errs.ErrBadReference) {
+			return errs.ErrNotFound
 		}
 		return err
 	}
@@ -21,23 +21,25 @@ func TestAddIngredientStock(t *testing.T) {
 	clock := clock.New()
 
 	t.Run("should return error if unexistent ingredient", func(t *testing.T) {
-		db, _ := database.NewFromDatasource(":memory:", logger)
+		db, err := database.NewFromDatasource(":memory:", logger)
+		require.NoError(t, err)
 		stockRepository := stockrepo.New(db)
 		ctx := context.Background()
 		stock, _ := model.NewIngredientStock(1, 5, 1.0, clock.Now())
-		err := stockRepository.Add(ctx, stock)
+		err = stockRepository.Add(ctx, stock)
 		require.Error(t, err)
 	})
 
 	t.Run("should add findable entity", func(t *testing.T) {
-		db, _ := database.NewFromDatasource(":memory:", logger)
+		db, err := database.NewFromDatasource(":memory:", logger)
+		require.NoError(t, err)
 		ingredientRepository := ingredientrepo.New(db)
 		stockRepository := stockrepo.New(db)
 		ctx := context.Background()
 		now := clock.Now()
 
 		ingredient, _ := model.NewIngredient("first", model.Gram, 1.0, now)
-		err := ingredientRepository.Add(ctx, ingredient)
+		err = ingredientRepository.Add(ctx, ingredient)
 		require.NoError(t, err)
 		ingredient2, _ := model.NewIngredient("second", model.Gram, 1.0, now)
 		err = ingredientRepository.Add(ctx, ingredient2)
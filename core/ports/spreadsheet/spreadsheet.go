@@ -0,0 +1,146 @@
+package spreadsheet
+
+import (
+	"bufio"
+	"bytes"
+	"costly/core/errs"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Table is the first sheet of a spreadsheet, with its first row as the header.
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// Record is a row of a table keyed by field. Line is the line of the row in
+// the file, the header being the first one.
+type Record struct {
+	Line   int
+	Values map[string]string
+}
+
+// Read reads the spreadsheet in content, as CSV or XLSX depending on the
+// extension of the file name.
+func Read(fileName string, content io.Reader) (*Table, error) {
+	var rows [][]string
+	var err error
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		rows, err = readCSV(content)
+	case ".xlsx":
+		rows, err = readXLSX(content)
+	default:
+		return &Table{}, errs.ErrBadSpreadsheet
+	}
+	if err != nil {
+		return &Table{}, fmt.Errorf("%w: %s", errs.ErrBadSpreadsheet, err)
+	}
+	if len(rows) == 0 {
+		return &Table{}, errs.ErrBadSpreadsheet
+	}
+	return &Table{Header: rows[0], Rows: rows[1:]}, nil
+}
+
+// readCSV reads comma or semicolon separated values, whichever the header has
+// more of, since spreadsheets using decimal commas export with semicolons.
+func readCSV(content io.Reader) ([][]string, error) {
+	buffered := bufio.NewReader(content)
+	header, err := buffered.Peek(4096)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	header, _, _ = bytes.Cut(header, []byte("\n"))
+	reader := csv.NewReader(buffered)
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		reader.Comma = ';'
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	return reader.ReadAll()
+}
+
+// Records maps every row that is not blank to its fields. Columns maps a field
+// to the header of its column, and fields not in it are read from the column
+// with the same header as the field, ignoring case. Fields without a column
+// are left out of the records.
+func (t *Table) Records(columns map[string]string, fields []string) ([]Record, error) {
+	var validation errs.ValidationErrors
+	unknown := []string{}
+	for field := range columns {
+		if !slices.Contains(fields, field) {
+			unknown = append(unknown, field)
+		}
+	}
+	slices.Sort(unknown)
+	for _, field := range unknown {
+		validation.Add(errs.ErrBadColumn.At("columns." + field))
+	}
+	indexes := map[string]int{}
+	for _, field := range fields {
+		header, mapped := columns[field]
+		if !mapped {
+			header = field
+		}
+		index := t.column(header)
+		switch {
+		case index >= 0:
+			indexes[field] = index
+		case mapped:
+			validation.Add(errs.ErrMissingColumn.At("columns." + field))
+		}
+	}
+	if err := validation.Err(); err != nil {
+		return []Record{}, err
+	}
+	records := []Record{}
+	for i, row := range t.Rows {
+		record := Record{Line: i + 2, Values: map[string]string{}}
+		blank := true
+		for field, index := range indexes {
+			if index < len(row) {
+				record.Values[field] = strings.TrimSpace(row[index])
+			}
+			blank = blank && record.Values[field] == ""
+		}
+		if !blank {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// Has tells whether the field was read from some column.
+func (r Record) Has(field string) bool {
+	_, ok := r.Values[field]
+	return ok
+}
+
+// List splits the value of the field in the items of a list, separated by
+// commas, semicolons or bars.
+func (r Record) List(field string) []string {
+	items := strings.FieldsFunc(r.Values[field], func(c rune) bool {
+		return c == ',' || c == ';' || c == '|'
+	})
+	list := []string{}
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func (t *Table) column(header string) int {
+	for i, h := range t.Header {
+		if strings.EqualFold(strings.TrimSpace(h), strings.TrimSpace(header)) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,108 @@
+package spreadsheet_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"costly/core/errs"
+	"costly/core/ports/spreadsheet"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// xlsxFile is a workbook whose first sheet is not named sheet1.xml, with a
+// shared string, a rich text string, an inline string and a number.
+func xlsxFile(t *testing.T) []byte {
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+			<sheets><sheet name="Catalog" sheetId="1" r:id="rId3"/></sheets>
+		</workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="sharedStrings.xml"/>
+			<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/catalog.xml"/>
+		</Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+			<si><t>name</t></si>
+			<si><t>price</t></si>
+			<si><r><t>Brown </t></r><r><t>sugar</t></r></si>
+		</sst>`,
+		"xl/worksheets/catalog.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>
+			<row r="2"><c r="A2" t="s"><v>2</v></c><c r="C2"><v>1.25</v></c></row>
+			<row r="3"><c r="A3" t="inlineStr"><is><t>flour</t></is></c><c r="C3"><v>0.8</v></c></row>
+		</sheetData></worksheet>`,
+	}
+	buffer := &bytes.Buffer{}
+	writer := zip.NewWriter(buffer)
+	for name, content := range parts {
+		part, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func TestRead(t *testing.T) {
+	t.Run("should read semicolon separated values", func(t *testing.T) {
+		table, err := spreadsheet.Read("catalog.CSV", strings.NewReader("name; price\nflour; 1,20\n"))
+		require.NoError(t, err)
+		assert.Equal(t, &spreadsheet.Table{Header: []string{"name", "price"}, Rows: [][]string{{"flour", "1,20"}}}, table)
+	})
+
+	t.Run("should read comma separated values", func(t *testing.T) {
+		table, err := spreadsheet.Read("catalog.csv", strings.NewReader("name,price\n\"sugar, brown\",1.20\n"))
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"sugar, brown", "1.20"}}, table.Rows)
+	})
+
+	t.Run("should read the first sheet of a workbook", func(t *testing.T) {
+		table, err := spreadsheet.Read("catalog.xlsx", bytes.NewReader(xlsxFile(t)))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"name", "", "price"}, table.Header)
+		assert.Equal(t, [][]string{{"Brown sugar", "", "1.25"}, {"flour", "", "0.8"}}, table.Rows)
+	})
+
+	t.Run("should return error if the file is not a spreadsheet", func(t *testing.T) {
+		_, err := spreadsheet.Read("catalog.pdf", strings.NewReader("name,price\n"))
+		assert.Equal(t, errs.ErrBadSpreadsheet, err)
+		_, err = spreadsheet.Read("catalog.xlsx", strings.NewReader("name,price\n"))
+		assert.ErrorIs(t, err, errs.ErrBadSpreadsheet)
+	})
+}
+
+func TestTableRecords(t *testing.T) {
+	table := &spreadsheet.Table{
+		Header: []string{"Producto", "Price", "Notes"},
+		Rows:   [][]string{{" flour ", "1,20"}, {"", ""}, {"salt", "0,5", "fine"}},
+	}
+
+	t.Run("should read the fields from their columns", func(t *testing.T) {
+		records, err := table.Records(map[string]string{"name": "producto"}, []string{"name", "price", "tags"})
+		require.NoError(t, err)
+		assert.Equal(t, []spreadsheet.Record{
+			{Line: 2, Values: map[string]string{"name": "flour", "price": "1,20"}},
+			{Line: 4, Values: map[string]string{"name": "salt", "price": "0,5"}},
+		}, records)
+		assert.True(t, records[0].Has("price"))
+		assert.False(t, records[0].Has("tags"))
+	})
+
+	t.Run("should return error if columns are not in the file or not fields", func(t *testing.T) {
+		_, err := table.Records(map[string]string{"name": "Nombre", "colour": "Notes"}, []string{"name", "price"})
+		require.Error(t, err)
+		assert.ElementsMatch(t, errs.ValidationErrors{
+			errs.ErrMissingColumn.At("columns.name"),
+			errs.ErrBadColumn.At("columns.colour"),
+		}, err)
+	})
+
+	t.Run("should split lists", func(t *testing.T) {
+		record := spreadsheet.Record{Values: map[string]string{"allergens": "gluten, milk;; eggs |nuts"}}
+		assert.Equal(t, []string{"gluten", "milk", "eggs", "nuts"}, record.List("allergens"))
+		assert.Equal(t, []string{}, record.List("tags"))
+	})
+}
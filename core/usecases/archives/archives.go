@@ -0,0 +1,24 @@
+package archives
+
+import (
+	"costly/core/ports/clock"
+	"costly/core/ports/database"
+	repo "costly/core/ports/repository"
+)
+
+type ArchiveUseCases interface {
+	Exporter
+	Restorer
+}
+
+type archiveUseCases struct {
+	clock      clock.Clock
+	repository repo.Repository
+}
+
+func New(database database.Database, clock clock.Clock) ArchiveUseCases {
+	return &archiveUseCases{
+		clock:      clock,
+		repository: repo.New(database),
+	}
+}
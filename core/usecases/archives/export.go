@@ -0,0 +1,27 @@
+package archives
+
+import (
+	"context"
+	"costly/core/model"
+	repo "costly/core/ports/repository"
+)
+
+type Exporter interface {
+	Export(ctx context.Context) (*model.Archive, error)
+}
+
+// Export dumps the whole database within a transaction, so that the archive is
+// consistent even if it is being changed meanwhile.
+func (uc *archiveUseCases) Export(ctx context.Context) (*model.Archive, error) {
+	var archive model.Archive
+	if err := uc.repository.Atomic(ctx, func(repo repo.Repository) error {
+		var err error
+		archive, err = repo.Archives().Dump(ctx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	archive.Version = model.ArchiveVersion
+	archive.ExportedAt = uc.clock.Now()
+	return &archive, nil
+}
@@ -0,0 +1,37 @@
+package archives
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	repo "costly/core/ports/repository"
+)
+
+var ErrNotEmpty = errs.NewConflictError("database", "", "database should have no ingredients, recipes nor menus to restore an archive")
+
+type Restorer interface {
+	Restore(ctx context.Context, archive model.Archive) (*model.ArchiveSummary, error)
+}
+
+// Restore stores everything in the archive keeping its ids and timestamps. The
+// database must be empty but for its categories, which are replaced by the
+// archived ones.
+func (uc *archiveUseCases) Restore(ctx context.Context, archive model.Archive) (*model.ArchiveSummary, error) {
+	if archive.Version < 1 || archive.Version > model.ArchiveVersion {
+		return nil, errs.ErrBadArchiveVersion
+	}
+	if err := uc.repository.Atomic(ctx, func(repo repo.Repository) error {
+		empty, err := repo.Archives().IsEmpty(ctx)
+		if err != nil {
+			return err
+		}
+		if !empty {
+			return ErrNotEmpty
+		}
+		return repo.Archives().Restore(ctx, archive)
+	}); err != nil {
+		return nil, err
+	}
+	summary := archive.Summary()
+	return &summary, nil
+}
@@ -0,0 +1,28 @@
+package attachments
+
+import (
+	"costly/core/ports/clock"
+	"costly/core/ports/database"
+	repo "costly/core/ports/repository"
+	"costly/core/ports/storage"
+)
+
+type AttachmentUseCases interface {
+	AttachmentCreator
+	AttachmentOpener
+	AttachmentDeleter
+}
+
+type attachmentUseCases struct {
+	clock      clock.Clock
+	repository repo.Repository
+	storage    storage.Storage
+}
+
+func New(database database.Database, clock clock.Clock, storage storage.Storage) AttachmentUseCases {
+	return &attachmentUseCases{
+		clock:      clock,
+		repository: repo.New(database),
+		storage:    storage,
+	}
+}
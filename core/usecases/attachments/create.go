@@ -0,0 +1,59 @@
+package attachments
+
+import (
+	"context"
+	"costly/core/model"
+	repo "costly/core/ports/repository"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+type AttachmentOptions struct {
+	FileName    string
+	ContentType string
+	Content     io.Reader
+}
+
+type AttachmentCreator interface {
+	Create(ctx context.Context, recipeID int64, attachmentOpts AttachmentOptions) (*model.RecipeAttachment, error)
+}
+
+func (ac *attachmentUseCases) Create(ctx context.Context, recipeID int64, attachmentOpts AttachmentOptions) (*model.RecipeAttachment, error) {
+	attachment, err := model.NewRecipeAttachment(recipeID, attachmentOpts.FileName, attachmentOpts.ContentType, ac.clock.Now())
+	if err != nil {
+		return &model.RecipeAttachment{}, err
+	}
+	storageKey, err := newStorageKey(recipeID)
+	if err != nil {
+		return &model.RecipeAttachment{}, err
+	}
+	attachment.StorageKey = storageKey
+	if err := ac.repository.Atomic(ctx, func(repo repo.Repository) error {
+		if _, err := repo.Recipes().Find(ctx, recipeID); err != nil {
+			return err
+		}
+		size, err := ac.storage.Save(ctx, attachment.StorageKey, attachmentOpts.Content)
+		if err != nil {
+			return err
+		}
+		attachment.Size = size
+		if err := repo.RecipeAttachments().Add(ctx, attachment); err != nil {
+			ac.storage.Delete(ctx, attachment.StorageKey)
+			return err
+		}
+		return nil
+	}); err != nil {
+		return &model.RecipeAttachment{}, err
+	}
+	return attachment, nil
+}
+
+func newStorageKey(recipeID int64) (string, error) {
+	name := make([]byte, 16)
+	if _, err := rand.Read(name); err != nil {
+		return "", fmt.Errorf("failed to generate attachment name: %w", err)
+	}
+	return fmt.Sprintf("recipes/%d/%s", recipeID, hex.EncodeToString(name)), nil
+}
@@ -0,0 +1,23 @@
+package attachments
+
+import (
+	"context"
+	repo "costly/core/ports/repository"
+)
+
+type AttachmentDeleter interface {
+	Delete(ctx context.Context, recipeID int64, attachmentID int64) error
+}
+
+func (ac *attachmentUseCases) Delete(ctx context.Context, recipeID int64, attachmentID int64) error {
+	return ac.repository.Atomic(ctx, func(repo repo.Repository) error {
+		attachment, err := repo.RecipeAttachments().Find(ctx, recipeID, attachmentID)
+		if err != nil {
+			return err
+		}
+		if err := repo.RecipeAttachments().Delete(ctx, recipeID, attachmentID); err != nil {
+			return err
+		}
+		return ac.storage.Delete(ctx, attachment.StorageKey)
+	})
+}
@@ -0,0 +1,23 @@
+package attachments
+
+import (
+	"context"
+	"costly/core/model"
+	"io"
+)
+
+type AttachmentOpener interface {
+	Open(ctx context.Context, recipeID int64, attachmentID int64) (model.RecipeAttachment, io.ReadCloser, error)
+}
+
+func (ac *attachmentUseCases) Open(ctx context.Context, recipeID int64, attachmentID int64) (model.RecipeAttachment, io.ReadCloser, error) {
+	attachment, err := ac.repository.RecipeAttachments().Find(ctx, recipeID, attachmentID)
+	if err != nil {
+		return model.RecipeAttachment{}, nil, err
+	}
+	content, err := ac.storage.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return model.RecipeAttachment{}, nil, err
+	}
+	return attachment, content, nil
+}
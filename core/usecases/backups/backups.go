@@ -0,0 +1,27 @@
+package backups
+
+import (
+	"costly/core/ports/backup"
+	"costly/core/ports/clock"
+	"costly/core/ports/logger"
+)
+
+type BackupUseCases interface {
+	BackupCreator
+	BackupsFinder
+	BackupScheduler
+}
+
+type backupUseCases struct {
+	clock   clock.Clock
+	logger  logger.Logger
+	backups backup.Backups
+}
+
+func New(backups backup.Backups, clock clock.Clock, logger logger.Logger) BackupUseCases {
+	return &backupUseCases{
+		clock:   clock,
+		logger:  logger,
+		backups: backups,
+	}
+}
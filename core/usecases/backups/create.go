@@ -0,0 +1,18 @@
+package backups
+
+import (
+	"context"
+	"costly/core/model"
+)
+
+type BackupCreator interface {
+	Create(ctx context.Context) (*model.Backup, error)
+}
+
+func (uc *backupUseCases) Create(ctx context.Context) (*model.Backup, error) {
+	backup, err := uc.backups.Create(ctx, uc.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &backup, nil
+}
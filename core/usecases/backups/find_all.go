@@ -0,0 +1,14 @@
+package backups
+
+import (
+	"context"
+	"costly/core/model"
+)
+
+type BackupsFinder interface {
+	FindAll(ctx context.Context) ([]model.Backup, error)
+}
+
+func (uc *backupUseCases) FindAll(ctx context.Context) ([]model.Backup, error) {
+	return uc.backups.List(ctx)
+}
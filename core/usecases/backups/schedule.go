@@ -0,0 +1,31 @@
+package backups
+
+import (
+	"context"
+	"costly/core/ports/logger"
+	"time"
+)
+
+type BackupScheduler interface {
+	Schedule(ctx context.Context, interval time.Duration)
+}
+
+// Schedule takes a backup every interval until the context is done. Failed
+// backups are logged, and tried again at the next interval.
+func (uc *backupUseCases) Schedule(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			backup, err := uc.Create(ctx)
+			if err != nil {
+				uc.logger.Error(err, "could not take scheduled backup")
+				continue
+			}
+			uc.logger.Info("took scheduled backup", logger.Field{Key: "name", Value: backup.Name})
+		}
+	}
+}
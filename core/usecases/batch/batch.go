@@ -0,0 +1,133 @@
+package batch
+
+import (
+	"context"
+	"costly/core/errs"
+	repo "costly/core/ports/repository"
+	"errors"
+	"io"
+)
+
+// MaxItems is the most items a single batch may have.
+const MaxItems = 1000
+
+// Mode tells what happens to the rest of a batch when one of its items fails.
+type Mode string
+
+const (
+	// AllOrNothing rolls back the whole batch when any item fails.
+	AllOrNothing Mode = "all_or_nothing"
+	// BestEffort keeps every item that succeeds and only rolls back the ones
+	// that fail.
+	BestEffort Mode = "best_effort"
+)
+
+// ErrRolledBack is returned when an all-or-nothing batch is rolled back
+// because some of its items failed.
+var ErrRolledBack = errors.New("batch rolled back")
+
+// Result is the outcome of an item of the batch, at the same index.
+type Result struct {
+	// ID of the created or updated entity.
+	ID int64
+	// Err is why the item failed, nil if it did not.
+	Err error
+}
+
+// Run runs fn for each of the n items of the batch within a single
+// transaction. Every item runs in its own savepoint, so a failed item leaves
+// nothing behind. Every item is run even when some fail, so that the results
+// tell all the failures at once. When some item fails in an all-or-nothing
+// batch the results are returned along with ErrRolledBack. The mode is
+// all-or-nothing when not given.
+func Run(ctx context.Context, repository repo.Repository, mode Mode, n int, fn func(repo repo.Repository, i int) (int64, error)) ([]Result, error) {
+	if mode == "" {
+		mode = AllOrNothing
+	}
+	if err := validate(mode, n); err != nil {
+		return nil, err
+	}
+	results, err := run(ctx, repository, n, fn, func(failed bool) error {
+		if failed && mode == AllOrNothing {
+			return ErrRolledBack
+		}
+		return nil
+	})
+	if errors.Is(err, ErrRolledBack) {
+		return results, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ImportOptions are the options of importing a spreadsheet.
+type ImportOptions struct {
+	FileName string
+	Content  io.Reader
+	// Columns maps a field to the header of the column it is read from, when
+	// it is not the name of the field.
+	Columns map[string]string
+	// DryRun reports what the import would do without doing it.
+	DryRun bool
+}
+
+// Rejected tells whether err rejects an item because of what it says, rather
+// than being an unexpected error that should stop the whole batch.
+func Rejected(err error) bool {
+	return errors.Is(err, errs.ErrBadOpts) || errors.Is(err, errs.ErrNotFound) || errors.Is(err, errs.ErrConflict) ||
+		errors.Is(err, errs.ErrBadReference) || errors.Is(err, errs.ErrStale)
+}
+
+// errDryRun rolls back a batch that was only a dry run.
+var errDryRun = errors.New("dry run")
+
+// Import runs the items read from a file in best effort mode, without limit on
+// their number. When it is a dry run everything is rolled back afterwards, so
+// that the results tell what the import would do without doing it.
+func Import(ctx context.Context, repository repo.Repository, dryRun bool, n int, fn func(repo repo.Repository, i int) (int64, error)) ([]Result, error) {
+	results, err := run(ctx, repository, n, fn, func(failed bool) error {
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return results, nil
+}
+
+// run runs every item of the batch in its own savepoint of a transaction, and
+// then ends the transaction with the error returned by end, if any.
+func run(ctx context.Context, repository repo.Repository, n int, fn func(repo repo.Repository, i int) (int64, error), end func(failed bool) error) ([]Result, error) {
+	results := make([]Result, n)
+	err := repository.Atomic(ctx, func(txRepo repo.Repository) error {
+		failed := false
+		for i := range results {
+			err := txRepo.Atomic(ctx, func(itemRepo repo.Repository) error {
+				id, err := fn(itemRepo, i)
+				results[i].ID = id
+				return err
+			})
+			if err != nil {
+				results[i] = Result{Err: err}
+				failed = true
+			}
+		}
+		return end(failed)
+	})
+	return results, err
+}
+
+func validate(mode Mode, n int) error {
+	var validation errs.ValidationErrors
+	if mode != AllOrNothing && mode != BestEffort {
+		validation.Add(errs.ErrBadBatchMode)
+	}
+	if n < 1 || n > MaxItems {
+		validation.Add(errs.ErrBadBatchItems)
+	}
+	return validation.Err()
+}
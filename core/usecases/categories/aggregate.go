@@ -0,0 +1,36 @@
+package categories
+
+import (
+	"context"
+	"costly/core/model"
+	repo "costly/core/ports/repository"
+	recipeviewrepo "costly/core/ports/repository/recipe_view"
+	"time"
+)
+
+type CategoryAggregator interface {
+	// Aggregate summarizes the food cost and sales of the recipes in every
+	// category, including the ones in its subcategories.
+	Aggregate(ctx context.Context) ([]model.CategoryAggregate, error)
+}
+
+func (cc *categoryUseCases) Aggregate(ctx context.Context) ([]model.CategoryAggregate, error) {
+	var aggregates []model.CategoryAggregate
+	err := cc.repository.Atomic(ctx, func(repo repo.Repository) error {
+		categories, err := repo.Categories().FindAll(ctx)
+		if err != nil {
+			return err
+		}
+		recipes, err := repo.RecipeViews().FindAll(ctx, recipeviewrepo.Filter{})
+		if err != nil {
+			return err
+		}
+		soldUnits, err := repo.RecipeSales().SoldUnits(ctx, time.Time{})
+		if err != nil {
+			return err
+		}
+		aggregates = model.NewCategoryAggregates(categories, recipes, soldUnits)
+		return nil
+	})
+	return aggregates, err
+}
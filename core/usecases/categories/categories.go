@@ -0,0 +1,45 @@
+package categories
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	"costly/core/ports/clock"
+	"costly/core/ports/database"
+	repo "costly/core/ports/repository"
+)
+
+type CategoryUseCases interface {
+	CategoryCreator
+	CategoriesFinder
+	CategoryAggregator
+}
+
+type categoryUseCases struct {
+	clock      clock.Clock
+	repository repo.Repository
+}
+
+func New(database database.Database, clock clock.Clock) CategoryUseCases {
+	return &categoryUseCases{
+		clock:      clock,
+		repository: repo.New(database),
+	}
+}
+
+// Classify validates the tags and that the category, if any, exists, so that
+// recipes and ingredients can be classified.
+func Classify(ctx context.Context, repo repo.Repository, categoryID *int64, tags []string) (model.Classification, error) {
+	classification, err := model.NewClassification(categoryID, tags)
+	if err != nil {
+		return model.Classification{}, err
+	}
+	if categoryID != nil {
+		if _, err := repo.Categories().Find(ctx, *categoryID); err == errs.ErrNotFound {
+			return model.Classification{}, errs.ErrBadCategory
+		} else if err != nil {
+			return model.Classification{}, err
+		}
+	}
+	return classification, nil
+}
@@ -0,0 +1,38 @@
+package categories
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	repo "costly/core/ports/repository"
+)
+
+type CategoryCreator interface {
+	Create(ctx context.Context, categoryOpts CreateCategoryOptions) (*model.Category, error)
+}
+
+type CreateCategoryOptions struct {
+	Name string
+	// ParentID is the category this one is a subcategory of, if any.
+	ParentID *int64 `json:"parent_id"`
+}
+
+func (cc *categoryUseCases) Create(ctx context.Context, opts CreateCategoryOptions) (*model.Category, error) {
+	newCategory, err := model.NewCategory(opts.Name, opts.ParentID, cc.clock.Now())
+	if err != nil {
+		return &model.Category{}, err
+	}
+	if err := cc.repository.Atomic(ctx, func(repo repo.Repository) error {
+		if opts.ParentID != nil {
+			if _, err := repo.Categories().Find(ctx, *opts.ParentID); err == errs.ErrNotFound {
+				return errs.ErrBadParent
+			} else if err != nil {
+				return err
+			}
+		}
+		return repo.Categories().Add(ctx, newCategory)
+	}); err != nil {
+		return &model.Category{}, err
+	}
+	return newCategory, nil
+}
@@ -0,0 +1,14 @@
+package categories
+
+import (
+	"context"
+	"costly/core/model"
+)
+
+type CategoriesFinder interface {
+	FindAll(ctx context.Context) ([]model.Category, error)
+}
+
+func (cc *categoryUseCases) FindAll(ctx context.Context) ([]model.Category, error) {
+	return cc.repository.Categories().FindAll(ctx)
+}
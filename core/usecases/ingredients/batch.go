@@ -0,0 +1,44 @@
+package ingredients
+
+import (
+	"context"
+	"costly/core/errs"
+	repo "costly/core/ports/repository"
+	"costly/core/usecases/batch"
+)
+
+type IngredientBatcher interface {
+	// CreateBatch creates every ingredient within a single transaction and
+	// tells the result of each, at the same index.
+	CreateBatch(ctx context.Context, mode batch.Mode, ingredientOpts []CreateIngredientOptions) ([]batch.Result, error)
+	// UpdateBatch updates every ingredient within a single transaction and
+	// tells the result of each, at the same index.
+	UpdateBatch(ctx context.Context, mode batch.Mode, ingredientOpts []UpdateIngredientOptions) ([]batch.Result, error)
+}
+
+type UpdateIngredientOptions struct {
+	ID int64
+	// Version is the version of the ingredient the edit is based on, as told by
+	// its ETag, so that the edit is refused if it was changed meanwhile.
+	Version int
+	CreateIngredientOptions
+}
+
+func (ic *ingredientUseCases) CreateBatch(ctx context.Context, mode batch.Mode, ingredientOpts []CreateIngredientOptions) ([]batch.Result, error) {
+	return batch.Run(ctx, ic.repository, mode, len(ingredientOpts), func(repo repo.Repository, i int) (int64, error) {
+		ingredient, err := ic.create(ctx, repo, ingredientOpts[i])
+		if err != nil {
+			return 0, err
+		}
+		return ingredient.ID, nil
+	})
+}
+
+func (ic *ingredientUseCases) UpdateBatch(ctx context.Context, mode batch.Mode, ingredientOpts []UpdateIngredientOptions) ([]batch.Result, error) {
+	return batch.Run(ctx, ic.repository, mode, len(ingredientOpts), func(repo repo.Repository, i int) (int64, error) {
+		if ingredientOpts[i].Version <= 0 {
+			return ingredientOpts[i].ID, errs.ErrBadVersion
+		}
+		return ingredientOpts[i].ID, ic.update(ctx, repo, ingredientOpts[i].ID, ingredientOpts[i].Version, ingredientOpts[i].CreateIngredientOptions)
+	})
+}
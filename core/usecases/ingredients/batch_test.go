@@ -0,0 +1,107 @@
+package ingredients_test
+
+import (
+	"costly/core/errs"
+	"costly/core/model"
+	"costly/core/usecases/batch"
+	"costly/core/usecases/ingredients"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateIngredientBatch(t *testing.T) {
+	batchOpts := []ingredients.CreateIngredientOptions{
+		{Name: "flour", Price: 1, Unit: model.Gram},
+		{Name: "", Price: 1, Unit: model.Gram},
+		{Name: "sugar", Price: 2, Unit: model.Gram},
+	}
+
+	t.Run("should roll back every ingredient if one fails", func(t *testing.T) {
+		ingredientComponent, ctx := setupTest(t)
+		results, err := ingredientComponent.CreateBatch(ctx, batch.AllOrNothing, batchOpts)
+		assert.ErrorIs(t, err, batch.ErrRolledBack)
+		require.Len(t, results, 3)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, errs.ErrBadName, results[1].Err)
+		assert.NoError(t, results[2].Err)
+		page, err := ingredientComponent.FindAll(ctx, ingredients.FindAllOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, page.Data)
+	})
+
+	t.Run("should keep the ingredients that succeed in best effort mode", func(t *testing.T) {
+		ingredientComponent, ctx := setupTest(t)
+		results, err := ingredientComponent.CreateBatch(ctx, batch.BestEffort, batchOpts)
+		require.NoError(t, err)
+		assert.Equal(t, []batch.Result{{ID: 1}, {Err: errs.ErrBadName}, {ID: 2}}, results)
+		page, err := ingredientComponent.FindAll(ctx, ingredients.FindAllOptions{})
+		require.NoError(t, err)
+		require.Len(t, page.Data, 2)
+		assert.Equal(t, "flour", page.Data[0].Name)
+		assert.Equal(t, "sugar", page.Data[1].Name)
+	})
+
+	t.Run("should undo only the failed item when it fails after writing", func(t *testing.T) {
+		ingredientComponent, ctx := setupTest(t)
+		results, err := ingredientComponent.CreateBatch(ctx, batch.BestEffort, []ingredients.CreateIngredientOptions{
+			{Name: "flour", Price: 1, Unit: model.Gram},
+			{Name: "flour", Price: 2, Unit: model.Gram},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.NoError(t, results[0].Err)
+		assert.Error(t, results[1].Err)
+		found, err := ingredientComponent.Find(ctx, results[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, found.Price)
+	})
+
+	t.Run("should return error if mode or items are invalid", func(t *testing.T) {
+		ingredientComponent, ctx := setupTest(t)
+		_, err := ingredientComponent.CreateBatch(ctx, "sometimes", nil)
+		assert.Equal(t, errs.ValidationErrors{errs.ErrBadBatchMode, errs.ErrBadBatchItems}, err)
+	})
+}
+
+func TestUpdateIngredientBatch(t *testing.T) {
+	t.Run("should update every ingredient", func(t *testing.T) {
+		ingredientComponent, ctx := setupTest(t)
+		_, err := ingredientComponent.CreateBatch(ctx, batch.AllOrNothing, []ingredients.CreateIngredientOptions{
+			{Name: "flour", Price: 1, Unit: model.Gram},
+			{Name: "sugar", Price: 2, Unit: model.Gram},
+		})
+		require.NoError(t, err)
+		results, err := ingredientComponent.UpdateBatch(ctx, batch.AllOrNothing, []ingredients.UpdateIngredientOptions{
+			{ID: 2, Version: 1, CreateIngredientOptions: ingredients.CreateIngredientOptions{Name: "brown sugar", Price: 3, Unit: model.Gram}},
+			{ID: 1, Version: 1, CreateIngredientOptions: ingredients.CreateIngredientOptions{Name: "flour", Price: 1.5, Unit: model.Gram}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []batch.Result{{ID: 2}, {ID: 1}}, results)
+		found, err := ingredientComponent.Find(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "brown sugar", found.Name)
+		assert.Equal(t, 3.0, found.Price)
+	})
+
+	t.Run("should refuse to update an ingredient changed since its version", func(t *testing.T) {
+		ingredientComponent, ctx := setupTest(t)
+		_, err := ingredientComponent.CreateBatch(ctx, batch.AllOrNothing, []ingredients.CreateIngredientOptions{
+			{Name: "flour", Price: 1, Unit: model.Gram},
+			{Name: "sugar", Price: 2, Unit: model.Gram},
+		})
+		require.NoError(t, err)
+		require.NoError(t, ingredientComponent.Update(ctx, 1, 1, ingredients.CreateIngredientOptions{Name: "flour", Price: 1.2, Unit: model.Gram}))
+
+		results, err := ingredientComponent.UpdateBatch(ctx, batch.BestEffort, []ingredients.UpdateIngredientOptions{
+			{ID: 1, Version: 1, CreateIngredientOptions: ingredients.CreateIngredientOptions{Name: "flour", Price: 1.5, Unit: model.Gram}},
+			{ID: 2, CreateIngredientOptions: ingredients.CreateIngredientOptions{Name: "brown sugar", Price: 3, Unit: model.Gram}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []batch.Result{{Err: errs.NewStaleError("ingredient")}, {Err: errs.ErrBadVersion}}, results)
+		found, err := ingredientComponent.Find(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1.2, found.Price)
+	})
+}
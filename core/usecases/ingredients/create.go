@@ -2,7 +2,10 @@ package ingredients
 
 import (
 	"context"
+	"costly/core/errs"
 	"costly/core/model"
+	repo "costly/core/ports/repository"
+	"costly/core/usecases/categories"
 )
 
 type IngredientCreator interface {
@@ -10,17 +13,56 @@ type IngredientCreator interface {
 }
 
 type CreateIngredientOptions struct {
-	Name  string
-	Price float64
-	Unit  model.Unit
+	Name      string
+	Price     float64
+	Unit      model.Unit
+	Allergens []model.Allergen
+	Nutrition model.Nutrition
+	// CategoryID is the category of the ingredient, if any.
+	CategoryID *int64 `json:"category_id"`
+	Tags       []string
+}
+
+// validate reports every invalid field of the options at once, and returns
+// their allergens normalized.
+func (opts CreateIngredientOptions) validate() ([]model.Allergen, error) {
+	var validation errs.ValidationErrors
+	if opts.Name == "" {
+		validation.Add(errs.ErrBadName)
+	}
+	if opts.Unit != "gr" {
+		validation.Add(errs.ErrBadUnit)
+	}
+	if opts.Price <= 0 {
+		validation.Add(errs.ErrBadPrice)
+	}
+	allergens, err := model.NewAllergens(opts.Allergens)
+	validation.Add(err)
+	validation.Add(opts.Nutrition.Validate())
+	return allergens, validation.Err()
 }
 
 func (ic *ingredientUseCases) Create(ctx context.Context, opts CreateIngredientOptions) (*model.Ingredient, error) {
+	return ic.create(ctx, ic.repository, opts)
+}
+
+func (ic *ingredientUseCases) create(ctx context.Context, repo repo.Repository, opts CreateIngredientOptions) (*model.Ingredient, error) {
+	allergens, err := opts.validate()
+	if err != nil {
+		return &model.Ingredient{}, err
+	}
 	newIngredient, err := model.NewIngredient(opts.Name, opts.Unit, opts.Price, ic.clock.Now())
 	if err != nil {
 		return &model.Ingredient{}, err
 	}
-	if err := ic.repository.Ingredients().Add(ctx, newIngredient); err != nil {
+	classification, err := categories.Classify(ctx, repo, opts.CategoryID, opts.Tags)
+	if err != nil {
+		return &model.Ingredient{}, err
+	}
+	newIngredient.Allergens = allergens
+	newIngredient.Nutrition = opts.Nutrition
+	newIngredient.Classification = classification
+	if err := repo.Ingredients().Add(ctx, newIngredient); err != nil {
 		return nil, err
 	}
 
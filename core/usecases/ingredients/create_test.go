@@ -30,7 +30,8 @@ func TestCreateIngredient(t *testing.T) {
 
 	t.Run("should create an ingredient if non existent", func(t *testing.T) {
 		logger, _ := logger.New("debug")
-		db, _ := database.NewFromDatasource(":memory:", logger)
+		db, err := database.NewFromDatasource(":memory:", logger)
+		require.NoError(t, err)
 		clockMock := new(mocks.ClockMock)
 		now := time.UnixMilli(12345).UTC()
 		clockMock.On("Now").Return(now)
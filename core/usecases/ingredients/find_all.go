@@ -3,12 +3,37 @@ package ingredients
 import (
 	"context"
 	"costly/core/model"
+	ingredientrepo "costly/core/ports/repository/ingredient"
 )
 
+var sortableFields = []model.SortField{model.SortByName, model.SortByPrice, model.SortByLastModified}
+
+type FindAllOptions struct {
+	// Name keeps only ingredients whose name contains it.
+	Name string
+	// CategoryID keeps only ingredients in the category or its subcategories.
+	CategoryID *int64
+	// Tags keeps only ingredients having all of them.
+	Tags []string
+	Page model.PageOptions
+}
+
 type IngredientsFinder interface {
-	FindAll(ctx context.Context) ([]model.Ingredient, error)
+	FindAll(ctx context.Context, opts FindAllOptions) (model.Page[model.Ingredient], error)
 }
 
-func (ic *ingredientUseCases) FindAll(ctx context.Context) ([]model.Ingredient, error) {
-	return ic.repository.Ingredients().FindAll(ctx)
+func (ic *ingredientUseCases) FindAll(ctx context.Context, opts FindAllOptions) (model.Page[model.Ingredient], error) {
+	classification, err := model.NewClassification(opts.CategoryID, opts.Tags)
+	if err != nil {
+		return model.Page[model.Ingredient]{}, err
+	}
+	pageOpts, err := model.NewPageOptions(opts.Page.Limit, opts.Page.Cursor, opts.Page.SortBy, opts.Page.Order, sortableFields)
+	if err != nil {
+		return model.Page[model.Ingredient]{}, err
+	}
+	return ic.repository.Ingredients().FindPage(ctx, ingredientrepo.Filter{
+		Name:       opts.Name,
+		CategoryID: classification.CategoryID,
+		Tags:       classification.Tags,
+	}, pageOpts)
 }
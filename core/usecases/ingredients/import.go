@@ -0,0 +1,126 @@
+package ingredients
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	repo "costly/core/ports/repository"
+	ingredientrepo "costly/core/ports/repository/ingredient"
+	"costly/core/ports/spreadsheet"
+	"costly/core/usecases/batch"
+	"strings"
+)
+
+type IngredientImporter interface {
+	// Import creates the ingredients of a catalog spreadsheet, or updates the
+	// ones with the same name as an ingredient that already exists.
+	Import(ctx context.Context, importOpts batch.ImportOptions) (*model.ImportReport, error)
+}
+
+// IngredientImportFields are the fields read from every row of a catalog. The
+// price is for the given quantity, one gram if not given.
+var IngredientImportFields = []string{"name", "price", "quantity", "allergens", "tags"}
+
+func (ic *ingredientUseCases) Import(ctx context.Context, importOpts batch.ImportOptions) (*model.ImportReport, error) {
+	table, err := spreadsheet.Read(importOpts.FileName, importOpts.Content)
+	if err != nil {
+		return &model.ImportReport{}, err
+	}
+	records, err := table.Records(importOpts.Columns, IngredientImportFields)
+	if err != nil {
+		return &model.ImportReport{}, err
+	}
+	existing, err := ic.repository.Ingredients().FindAll(ctx, ingredientrepo.Filter{})
+	if err != nil {
+		return &model.ImportReport{}, err
+	}
+	ingredientIDs := map[string]int64{}
+	for _, ingredient := range existing {
+		ingredientIDs[strings.ToLower(ingredient.Name)] = ingredient.ID
+	}
+
+	actions := make([]model.ImportAction, len(records))
+	results, err := batch.Import(ctx, ic.repository, importOpts.DryRun, len(records), func(repo repo.Repository, i int) (int64, error) {
+		ingredientOpts, err := ingredientOptionsOf(records[i])
+		if err != nil {
+			return 0, err
+		}
+		name := strings.ToLower(ingredientOpts.Name)
+		if ingredientID, ok := ingredientIDs[name]; ok {
+			// the ingredient is found again within the import, so that it is
+			// updated from the version it is merged with
+			found, err := repo.Ingredients().Find(ctx, ingredientID)
+			if err != nil {
+				return 0, err
+			}
+			// what the catalog does not say is kept as it is
+			if !records[i].Has("allergens") {
+				ingredientOpts.Allergens = found.Allergens
+			}
+			if !records[i].Has("tags") {
+				ingredientOpts.Tags = found.Tags
+			}
+			ingredientOpts.Nutrition = found.Nutrition
+			ingredientOpts.CategoryID = found.CategoryID
+			actions[i] = model.ImportUpdated
+			return found.ID, ic.update(ctx, repo, found.ID, found.Version, ingredientOpts)
+		}
+		ingredient, err := ic.create(ctx, repo, ingredientOpts)
+		if err != nil {
+			return 0, err
+		}
+		ingredientIDs[name] = ingredient.ID
+		actions[i] = model.ImportCreated
+		return ingredient.ID, nil
+	})
+	if err != nil {
+		return &model.ImportReport{}, err
+	}
+
+	report := &model.ImportReport{DryRun: importOpts.DryRun, Rows: []model.ImportRow{}}
+	for i, result := range results {
+		row := model.ImportRow{Line: records[i].Line, Name: records[i].Values["name"], Action: actions[i], ID: result.ID}
+		if result.Err != nil {
+			if !batch.Rejected(result.Err) {
+				return &model.ImportReport{}, result.Err
+			}
+			row.Action = model.ImportRejected
+			row.Errors = model.NewImportErrors(records[i].Line, result.Err)
+		}
+		report.Add(row)
+	}
+	return report, nil
+}
+
+// ingredientOptionsOf reads the ingredient of a row of a catalog, with its
+// price per gram.
+func ingredientOptionsOf(record spreadsheet.Record) (CreateIngredientOptions, error) {
+	var validation errs.ValidationErrors
+	price, err := model.ParseDecimal(record.Values["price"])
+	if err != nil {
+		validation.Add(errs.ErrBadPrice)
+	}
+	grams := 1.0
+	if record.Values["quantity"] != "" {
+		quantity, err := model.ParseQuantity(record.Values["quantity"])
+		if err != nil {
+			validation.Add(err)
+		} else if grams, err = quantity.Grams(); err != nil {
+			validation.Add(errs.ErrBadUnit.At("quantity"))
+		}
+	}
+	if err := validation.Err(); err != nil {
+		return CreateIngredientOptions{}, err
+	}
+	allergens := []model.Allergen{}
+	for _, allergen := range record.List("allergens") {
+		allergens = append(allergens, model.Allergen(allergen))
+	}
+	return CreateIngredientOptions{
+		Name:      record.Values["name"],
+		Price:     price / grams,
+		Unit:      model.Gram,
+		Allergens: allergens,
+		Tags:      record.List("tags"),
+	}, nil
+}
@@ -9,6 +9,8 @@ import (
 type IngredientUseCases interface {
 	IngredientCreator
 	IngredientEditor
+	IngredientBatcher
+	IngredientImporter
 	IngredientStockAdder
 	IngredientFinder
 	IngredientsFinder
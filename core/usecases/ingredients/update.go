@@ -4,33 +4,39 @@ import (
 	"context"
 	"costly/core/errs"
 	"costly/core/model"
+	repo "costly/core/ports/repository"
+	"costly/core/usecases/categories"
 )
 
 type IngredientEditor interface {
-	Update(ctx context.Context, ingredientID int64, ingredientOpts CreateIngredientOptions) error
+	// Update replaces the ingredient if it still has the given version, or
+	// whatever version it has if zero.
+	Update(ctx context.Context, ingredientID int64, version int, ingredientOpts CreateIngredientOptions) error
 }
 
-func (opts CreateIngredientOptions) validate() error {
-	if opts.Name == "" {
-		return errs.ErrBadName
-	}
-	if opts.Unit != "gr" {
-		return errs.ErrBadUnit
-	}
-	if opts.Price <= 0 {
-		return errs.ErrBadPrice
-	}
-	return nil
+func (ic *ingredientUseCases) Update(ctx context.Context, ingredientID int64, version int, ingredientOpts CreateIngredientOptions) error {
+	return ic.update(ctx, ic.repository, ingredientID, version, ingredientOpts)
 }
 
-func (ic *ingredientUseCases) Update(ctx context.Context, ingredientID int64, ingredientOpts CreateIngredientOptions) error {
-	if err := ingredientOpts.validate(); err != nil {
+func (ic *ingredientUseCases) update(ctx context.Context, repo repo.Repository, ingredientID int64, version int, ingredientOpts CreateIngredientOptions) error {
+	allergens, err := ingredientOpts.validate()
+	if err != nil {
+		return err
+	}
+	classification, err := categories.Classify(ctx, repo, ingredientOpts.CategoryID, ingredientOpts.Tags)
+	if err != nil {
 		return err
 	}
-	err := ic.repository.Ingredients().Update(ctx, ingredientID, func(ingredient *model.Ingredient) error {
+	err = repo.Ingredients().Update(ctx, ingredientID, func(ingredient *model.Ingredient) error {
+		if version != 0 && ingredient.Version != version {
+			return errs.NewStaleError("ingredient")
+		}
 		ingredient.Name = ingredientOpts.Name
 		ingredient.Price = ingredientOpts.Price
 		ingredient.Unit = ingredientOpts.Unit
+		ingredient.Allergens = allergens
+		ingredient.Nutrition = ingredientOpts.Nutrition
+		ingredient.Classification = classification
 		ingredient.LastModified = ic.clock.Now()
 		return nil
 	})
@@ -1,6 +1,7 @@
 package ingredients_test
 
 import (
+	"costly/core/errs"
 	"costly/core/model"
 	"costly/core/usecases/ingredients"
 	"testing"
@@ -26,7 +27,7 @@ func TestUpdate(t *testing.T) {
 			Price: ing1.Price + 10.0,
 			Unit:  model.Gram,
 		}
-		err = ingredientComponent.Update(ctx, ing1.ID, newIngredientOpts)
+		err = ingredientComponent.Update(ctx, ing1.ID, 0, newIngredientOpts)
 		require.NoError(t, err)
 
 		modifiedIngredient, err := ingredientComponent.Find(ctx, ing1.ID)
@@ -36,4 +37,19 @@ func TestUpdate(t *testing.T) {
 		assert.Equal(t, modifiedIngredient.Price, newIngredientOpts.Price)
 		assert.Equal(t, modifiedIngredient.Unit, newIngredientOpts.Unit)
 	})
+
+	t.Run("should not edit ingredient edited since the version expected", func(t *testing.T) {
+		ingredientComponent, ctx := setupTest(t)
+		ing1, err := ingredientComponent.Create(ctx, ingredients.CreateIngredientOptions{Name: "ing1", Price: 10.0, Unit: model.Gram})
+		require.NoError(t, err)
+		require.NoError(t, ingredientComponent.Update(ctx, ing1.ID, ing1.Version, ingredients.CreateIngredientOptions{Name: "ing1", Price: 12.0, Unit: model.Gram}))
+
+		err = ingredientComponent.Update(ctx, ing1.ID, ing1.Version, ingredients.CreateIngredientOptions{Name: "ing1", Price: 11.0, Unit: model.Gram})
+
+		assert.ErrorIs(t, err, errs.ErrStale)
+		found, err := ingredientComponent.Find(ctx, ing1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 12.0, found.Price)
+		assert.Equal(t, 2, found.Version)
+	})
 }
@@ -0,0 +1,36 @@
+package menus
+
+import (
+	"context"
+	"costly/core/model"
+	repo "costly/core/ports/repository"
+)
+
+type MenuCoster interface {
+	// Cost gets the food cost of every item in the menu with the current
+	// ingredient prices, and the blended food cost of the whole menu.
+	Cost(ctx context.Context, id int64) (model.MenuCosting, error)
+}
+
+func (mc *menuUseCases) Cost(ctx context.Context, id int64) (model.MenuCosting, error) {
+	var costing model.MenuCosting
+	err := mc.repository.Atomic(ctx, func(repo repo.Repository) error {
+		menu, err := repo.Menus().Find(ctx, id)
+		if err != nil {
+			return err
+		}
+		recipeIDs := []int64{}
+		for _, item := range menu.Items {
+			for _, recipe := range item.Recipes {
+				recipeIDs = append(recipeIDs, recipe.ID)
+			}
+		}
+		recipes, err := findRecipes(ctx, repo, recipeIDs)
+		if err != nil {
+			return err
+		}
+		costing = model.NewMenuCosting(menu, recipes)
+		return nil
+	})
+	return costing, err
+}
@@ -0,0 +1,80 @@
+package menus
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	repo "costly/core/ports/repository"
+	recipeviewrepo "costly/core/ports/repository/recipe_view"
+	"fmt"
+	"time"
+)
+
+type MenuCreator interface {
+	Create(ctx context.Context, menuOpts CreateMenuOptions) (*model.Menu, error)
+}
+
+type CreateMenuOptions struct {
+	Name       string
+	Channel    model.Channel
+	ValidFrom  *time.Time `json:"valid_from"`
+	ValidUntil *time.Time `json:"valid_until"`
+	Items      []MenuItemOptions
+}
+
+// MenuItemOptions is a single recipe or, when several recipes or units are
+// given, a combo priced as a bundle.
+type MenuItemOptions struct {
+	Name    string
+	Price   float64
+	Recipes []model.MenuItemRecipe
+}
+
+func (mc *menuUseCases) Create(ctx context.Context, opts CreateMenuOptions) (*model.Menu, error) {
+	var validation errs.ValidationErrors
+	items := []model.MenuItem{}
+	recipeIDs := []int64{}
+	for i, itemOpts := range opts.Items {
+		item, err := model.NewMenuItem(itemOpts.Name, itemOpts.Price, itemOpts.Recipes)
+		var itemValidation errs.ValidationErrors
+		itemValidation.Add(err)
+		validation.Add(itemValidation.In(fmt.Sprintf("items[%d]", i)).Err())
+		items = append(items, item)
+		for _, recipe := range item.Recipes {
+			recipeIDs = append(recipeIDs, recipe.ID)
+		}
+	}
+	newMenu, err := model.NewMenu(opts.Name, opts.Channel, opts.ValidFrom, opts.ValidUntil, items, mc.clock.Now())
+	validation.Add(err)
+	if err := validation.Err(); err != nil {
+		return &model.Menu{}, err
+	}
+	if err := mc.repository.Atomic(ctx, func(repo repo.Repository) error {
+		recipes, err := findRecipes(ctx, repo, recipeIDs)
+		if err != nil {
+			return err
+		}
+		for _, id := range recipeIDs {
+			if _, ok := recipes[id]; !ok {
+				return errs.ErrBadRecipe
+			}
+		}
+		return repo.Menus().Add(ctx, newMenu)
+	}); err != nil {
+		return &model.Menu{}, err
+	}
+	return newMenu, nil
+}
+
+// findRecipes gets the recipes with the given ids indexed by id.
+func findRecipes(ctx context.Context, repo repo.Repository, ids []int64) (map[int64]model.RecipeView, error) {
+	recipes, err := repo.RecipeViews().FindAll(ctx, recipeviewrepo.Filter{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	recipesByID := map[int64]model.RecipeView{}
+	for _, recipe := range recipes {
+		recipesByID[recipe.ID] = recipe
+	}
+	return recipesByID, nil
+}
@@ -0,0 +1,19 @@
+package menus
+
+import (
+	"context"
+	"costly/core/model"
+)
+
+type MenuFinder interface {
+	Find(ctx context.Context, id int64) (model.Menu, error)
+	FindAll(ctx context.Context) ([]model.Menu, error)
+}
+
+func (mc *menuUseCases) Find(ctx context.Context, id int64) (model.Menu, error) {
+	return mc.repository.Menus().Find(ctx, id)
+}
+
+func (mc *menuUseCases) FindAll(ctx context.Context) ([]model.Menu, error) {
+	return mc.repository.Menus().FindAll(ctx)
+}
@@ -0,0 +1,25 @@
+package menus
+
+import (
+	"costly/core/ports/clock"
+	"costly/core/ports/database"
+	repo "costly/core/ports/repository"
+)
+
+type MenuUseCases interface {
+	MenuCreator
+	MenuFinder
+	MenuCoster
+}
+
+type menuUseCases struct {
+	clock      clock.Clock
+	repository repo.Repository
+}
+
+func New(database database.Database, clock clock.Clock) MenuUseCases {
+	return &menuUseCases{
+		clock:      clock,
+		repository: repo.New(database),
+	}
+}
@@ -16,7 +16,7 @@ func (cr *recipeUseCases) AddSales(ctx context.Context, recipeID int64, soldUnit
 		return &model.RecipeSales{}, errs.ErrBadStockUnits
 	}
 	recipeSales := model.NewRecipeSales(recipeID, soldUnits, cr.clock.Now())
-	cr.repository.Atomic(ctx, func(repo repo.Repository) error {
+	if err := cr.repository.Atomic(ctx, func(repo repo.Repository) error {
 		if err := repo.RecipeSales().Add(ctx, recipeSales); err != nil {
 			return err
 		}
@@ -30,6 +30,8 @@ func (cr *recipeUseCases) AddSales(ctx context.Context, recipeID int64, soldUnit
 			}
 		}
 		return nil
-	})
+	}); err != nil {
+		return &model.RecipeSales{}, err
+	}
 	return recipeSales, nil
 }
@@ -0,0 +1,44 @@
+package recipes
+
+import (
+	"context"
+	"costly/core/errs"
+	repo "costly/core/ports/repository"
+	"costly/core/usecases/batch"
+)
+
+type RecipeBatcher interface {
+	// CreateBatch creates every recipe within a single transaction and tells
+	// the result of each, at the same index.
+	CreateBatch(ctx context.Context, mode batch.Mode, recipeOpts []CreateRecipeOptions) ([]batch.Result, error)
+	// UpdateBatch updates every recipe within a single transaction, making a
+	// new revision of each, and tells the result of each, at the same index.
+	UpdateBatch(ctx context.Context, mode batch.Mode, recipeOpts []UpdateRecipeOptions) ([]batch.Result, error)
+}
+
+type UpdateRecipeOptions struct {
+	ID int64
+	// Version is the version of the recipe the edit is based on, as told by
+	// its ETag, so that the edit is refused if it was changed meanwhile.
+	Version int
+	CreateRecipeOptions
+}
+
+func (cr *recipeUseCases) CreateBatch(ctx context.Context, mode batch.Mode, recipeOpts []CreateRecipeOptions) ([]batch.Result, error) {
+	return batch.Run(ctx, cr.repository, mode, len(recipeOpts), func(repo repo.Repository, i int) (int64, error) {
+		recipe, err := cr.create(ctx, repo, recipeOpts[i])
+		if err != nil {
+			return 0, err
+		}
+		return recipe.ID, nil
+	})
+}
+
+func (cr *recipeUseCases) UpdateBatch(ctx context.Context, mode batch.Mode, recipeOpts []UpdateRecipeOptions) ([]batch.Result, error) {
+	return batch.Run(ctx, cr.repository, mode, len(recipeOpts), func(repo repo.Repository, i int) (int64, error) {
+		if recipeOpts[i].Version <= 0 {
+			return recipeOpts[i].ID, errs.ErrBadVersion
+		}
+		return recipeOpts[i].ID, cr.update(ctx, repo, recipeOpts[i].ID, recipeOpts[i].Version, recipeOpts[i].CreateRecipeOptions)
+	})
+}
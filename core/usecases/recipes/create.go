@@ -2,8 +2,13 @@ package recipes
 
 import (
 	"context"
+	"costly/core/errs"
 	"costly/core/model"
+	repo "costly/core/ports/repository"
+	"costly/core/usecases/categories"
+	"errors"
 	"fmt"
+	"time"
 )
 
 type RecipeCreator interface {
@@ -11,19 +16,98 @@ type RecipeCreator interface {
 }
 
 type CreateRecipeOptions struct {
-	Name        string
+	Name string
+	// Price the recipe is sold for, if it is sold.
+	Price float64
+	// Portions the recipe yields, one if not given.
+	Portions    int
 	Ingredients []model.RecipeIngredient
+	model.RecipeMethod
+	// CategoryID is the category of the recipe, if any.
+	CategoryID *int64 `json:"category_id"`
+	Tags       []string
+}
+
+// newRecipe validates the options, reporting every invalid field at once.
+func (opts CreateRecipeOptions) newRecipe(now time.Time) (*model.Recipe, error) {
+	var validation errs.ValidationErrors
+	newRecipe, err := model.NewRecipe(opts.Name, opts.Ingredients, now)
+	validation.Add(err)
+	method, err := model.NewRecipeMethod(opts.Steps, opts.PlatingNotes, opts.PrepMinutes, opts.CookMinutes)
+	validation.Add(err)
+	if opts.Price < 0 {
+		validation.Add(errs.ErrBadPrice)
+	}
+	if opts.Portions < 0 {
+		validation.Add(errs.ErrBadPortions)
+	}
+	if err := validation.Err(); err != nil {
+		return &model.Recipe{}, err
+	}
+	newRecipe.RecipeMethod = method
+	newRecipe.Price = opts.Price
+	if opts.Portions > 0 {
+		newRecipe.Portions = opts.Portions
+	}
+	return newRecipe, nil
 }
 
 func (cr *recipeUseCases) Create(ctx context.Context, recipeOpts CreateRecipeOptions) (*model.Recipe, error) {
-	newRecipe, err := model.NewRecipe(recipeOpts.Name, recipeOpts.Ingredients, cr.clock.Now())
+	return cr.create(ctx, cr.repository, recipeOpts)
+}
+
+func (cr *recipeUseCases) create(ctx context.Context, repository repo.Repository, recipeOpts CreateRecipeOptions) (*model.Recipe, error) {
+	newRecipe, err := recipeOpts.newRecipe(cr.clock.Now())
 	if err != nil {
 		return &model.Recipe{}, err
 	}
 
-	if err := cr.repository.Recipes().Add(ctx, newRecipe); err != nil {
-		return &model.Recipe{}, fmt.Errorf("failed to create recipe: %s", err)
+	if err := repository.Atomic(ctx, func(repo repo.Repository) error {
+		classification, err := categories.Classify(ctx, repo, recipeOpts.CategoryID, recipeOpts.Tags)
+		if err != nil {
+			return err
+		}
+		newRecipe.Classification = classification
+		if err := checkIngredients(ctx, repo, newRecipe.Ingredients); err != nil {
+			return err
+		}
+		if err := repo.Recipes().Add(ctx, newRecipe); err != nil {
+			return err
+		}
+		return addRevision(ctx, repo, *newRecipe)
+	}); errors.Is(err, errs.ErrBadOpts) {
+		return &model.Recipe{}, err
+	} else if err != nil {
+		return &model.Recipe{}, fmt.Errorf("failed to create recipe: %w", err)
 	}
 
 	return newRecipe, nil
 }
+
+// checkIngredients reports the first ingredient of the recipe that does not
+// exist at its path in the request.
+func checkIngredients(ctx context.Context, repo repo.Repository, ingredients []model.RecipeIngredient) error {
+	for i, ingredient := range ingredients {
+		if _, err := repo.Ingredients().Find(ctx, ingredient.ID); errors.Is(err, errs.ErrNotFound) {
+			return errs.NewReferenceError("ingredient", fmt.Sprintf("ingredients[%d].id", i), "ingredient does not exist")
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addRevision snapshots the recipe with the current prices of its ingredients.
+func addRevision(ctx context.Context, repo repo.Repository, recipe model.Recipe) error {
+	recipeIngredients, err := repo.RecipeViews().FindIngredients(ctx, recipe.ID)
+	if err != nil {
+		return err
+	}
+	revision := model.NewRecipeRevision(model.RecipeView{
+		ID:          recipe.ID,
+		Name:        recipe.Name,
+		Portions:    recipe.Portions,
+		Ingredients: recipeIngredients,
+	}, recipe.LastModified)
+	return repo.RecipeRevisions().Add(ctx, revision)
+}
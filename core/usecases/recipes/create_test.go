@@ -35,8 +35,9 @@ var pepper = ingredients.CreateIngredientOptions{
 	Unit:  model.Gram,
 }
 
-func setupTest(logger logger.Logger, clock clock.Clock) ([]model.Ingredient, recipes.RecipeUseCases, context.Context) {
-	db, _ := database.NewFromDatasource(":memory:", logger)
+func setupTest(t *testing.T, logger logger.Logger, clock clock.Clock) ([]model.Ingredient, recipes.RecipeUseCases, context.Context) {
+	db, err := database.NewFromDatasource(":memory:", logger)
+	require.NoError(t, err)
 	ingredientUseCases := ingredients.New(db, clock)
 	ctx := context.Background()
 	var createdIngredients = []model.Ingredient{}
@@ -60,7 +61,7 @@ func TestCreate(t *testing.T) {
 		clockMock := new(mocks.ClockMock)
 		now := time.UnixMilli(12345).UTC()
 		clockMock.On("Now").Return(now)
-		ingredients, recipeComponent, ctx := setupTest(logger, clockMock)
+		ingredients, recipeComponent, ctx := setupTest(t, logger, clockMock)
 
 		recipe, err := recipeComponent.Create(ctx, recipes.CreateRecipeOptions{
 			Name: "recipeName",
@@ -91,7 +92,7 @@ func TestCreate(t *testing.T) {
 	})
 
 	t.Run("should fail to create a recipe if existent", func(t *testing.T) {
-		ingredients, recipeComponent, ctx := setupTest(logger, clock)
+		ingredients, recipeComponent, ctx := setupTest(t, logger, clock)
 		existentRecipeName := "name"
 
 		recipeComponent.Create(ctx, recipes.CreateRecipeOptions{
@@ -114,11 +115,14 @@ func TestCreate(t *testing.T) {
 			},
 		})
 		require.Error(t, err)
-		assert.EqualError(t, err, "failed to create recipe: UNIQUE constraint failed: recipe.name")
+		assert.EqualError(t, err, "failed to create recipe: a recipe with this name already exists")
+		var conflictErr *errs.ConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, errs.NewConflictError("recipe", "name", "a recipe with this name already exists"), conflictErr)
 	})
 
 	t.Run("should return an error when creating a recipe with unexistent ingredient", func(t *testing.T) {
-		ingredients, recipeComponent, ctx := setupTest(logger, clock)
+		ingredients, recipeComponent, ctx := setupTest(t, logger, clock)
 		existentRecipeName := "name"
 		var unexistentIngredientID int64
 		for _, i := range ingredients {
@@ -133,11 +137,14 @@ func TestCreate(t *testing.T) {
 				},
 			},
 		})
-		assert.EqualError(t, err, "failed to create recipe: FOREIGN KEY constraint failed")
+		assert.EqualError(t, err, "failed to create recipe: ingredient does not exist")
+		var referenceErr *errs.ReferenceError
+		require.ErrorAs(t, err, &referenceErr)
+		assert.Equal(t, errs.NewReferenceError("ingredient", "ingredients[0].id", "ingredient does not exist"), referenceErr)
 	})
 
 	t.Run("should assign different IDs to different recipes", func(t *testing.T) {
-		ingredients, recipeComponent, ctx := setupTest(logger, clock)
+		ingredients, recipeComponent, ctx := setupTest(t, logger, clock)
 		recipe1, err := recipeComponent.Create(ctx, recipes.CreateRecipeOptions{
 			Name: "recipe1",
 			Ingredients: []model.RecipeIngredient{
@@ -163,7 +170,7 @@ func TestCreate(t *testing.T) {
 	})
 
 	t.Run("should return error when creating a recipe without ingredients", func(t *testing.T) {
-		_, recipeComponent, ctx := setupTest(logger, clock)
+		_, recipeComponent, ctx := setupTest(t, logger, clock)
 		_, err := recipeComponent.Create(ctx, recipes.CreateRecipeOptions{
 			Name:        "recipe1",
 			Ingredients: []model.RecipeIngredient{},
@@ -4,15 +4,20 @@ import (
 	"context"
 	"costly/core/model"
 	repo "costly/core/ports/repository"
+	"time"
 )
 
 type RecipeFinder interface {
 	Find(ctx context.Context, id int64) (model.RecipeView, error)
+	// FindAsOf gets the recipe as it was at the given moment: the revision active
+	// then, costed with the ingredient prices effective at that time.
+	FindAsOf(ctx context.Context, id int64, asOf time.Time) (model.RecipeView, error)
 }
 
 func (cr *recipeUseCases) Find(ctx context.Context, id int64) (model.RecipeView, error) {
 	var recipe model.Recipe
 	var recipeIngredientsView []model.RecipeIngredientView
+	var attachments []model.RecipeAttachment
 	if err := cr.repository.Atomic(ctx, func(repo repo.Repository) error {
 		recipeFound, err := repo.Recipes().Find(ctx, id)
 		if err != nil {
@@ -24,15 +29,50 @@ func (cr *recipeUseCases) Find(ctx context.Context, id int64) (model.RecipeView,
 			return err
 		}
 		recipeIngredientsView = recipeIngredients
-		return nil
+		attachments, err = repo.RecipeAttachments().FindByRecipe(ctx, id)
+		return err
 	}); err != nil {
 		return model.RecipeView{}, err
 	}
 	return model.RecipeView{
-		ID:           recipe.ID,
-		Name:         recipe.Name,
-		Ingredients:  recipeIngredientsView,
-		CreatedAt:    recipe.CreatedAt,
-		LastModified: recipe.LastModified,
+		ID:             recipe.ID,
+		Name:           recipe.Name,
+		Price:          recipe.Price,
+		Portions:       recipe.Portions,
+		Ingredients:    recipeIngredientsView,
+		RecipeMethod:   recipe.RecipeMethod,
+		Classification: recipe.Classification,
+		Attachments:    attachments,
+		CreatedAt:      recipe.CreatedAt,
+		LastModified:   recipe.LastModified,
+		Version:        recipe.Version,
 	}, nil
 }
+
+func (cr *recipeUseCases) FindAsOf(ctx context.Context, id int64, asOf time.Time) (model.RecipeView, error) {
+	recipe, err := cr.Find(ctx, id)
+	if err != nil {
+		return model.RecipeView{}, err
+	}
+	if err := cr.repository.Atomic(ctx, func(repo repo.Repository) error {
+		return recipeAsOf(ctx, repo, &recipe, asOf)
+	}); err != nil {
+		return model.RecipeView{}, err
+	}
+	return recipe, nil
+}
+
+func recipeAsOf(ctx context.Context, repo repo.Repository, recipe *model.RecipeView, asOf time.Time) error {
+	revision, err := repo.RecipeRevisions().FindAsOf(ctx, recipe.ID, asOf)
+	if err != nil {
+		return err
+	}
+	recipeIngredients, err := repo.RecipeViews().FindRevisionIngredients(ctx, revision.ID, asOf)
+	if err != nil {
+		return err
+	}
+	recipe.Name = revision.Name
+	recipe.Portions = revision.Portions
+	recipe.Ingredients = recipeIngredients
+	return nil
+}
@@ -2,13 +2,72 @@ package recipes
 
 import (
 	"context"
+	"costly/core/errs"
 	"costly/core/model"
+	repo "costly/core/ports/repository"
+	recipeviewrepo "costly/core/ports/repository/recipe_view"
+	"time"
 )
 
+var sortableFields = []model.SortField{model.SortByName, model.SortByPrice, model.SortByCost, model.SortByLastModified}
+
+type FindAllOptions struct {
+	// Name keeps only recipes whose name contains it.
+	Name              string
+	ExcludedAllergens []model.Allergen
+	// CategoryID keeps only recipes in the category or its subcategories.
+	CategoryID *int64
+	// Tags keeps only recipes having all of them.
+	Tags []string
+	// AsOf gets the recipes as they were at that moment if given.
+	AsOf time.Time
+	Page model.PageOptions
+}
+
 type RecipesFinder interface {
-	FindAll(ctx context.Context) ([]model.RecipeView, error)
+	FindAll(ctx context.Context, opts FindAllOptions) (model.Page[model.RecipeView], error)
 }
 
-func (cr *recipeUseCases) FindAll(ctx context.Context) ([]model.RecipeView, error) {
-	return cr.repository.RecipeViews().FindAll(ctx)
+func (cr *recipeUseCases) FindAll(ctx context.Context, opts FindAllOptions) (model.Page[model.RecipeView], error) {
+	excludedAllergens, err := model.NewAllergens(opts.ExcludedAllergens)
+	if err != nil {
+		// Excluded allergens are query parameters rather than a JSON array,
+		// so there is no index to report.
+		return model.Page[model.RecipeView]{}, errs.ErrBadAllergen
+	}
+	classification, err := model.NewClassification(opts.CategoryID, opts.Tags)
+	if err != nil {
+		return model.Page[model.RecipeView]{}, err
+	}
+	pageOpts, err := model.NewPageOptions(opts.Page.Limit, opts.Page.Cursor, opts.Page.SortBy, opts.Page.Order, sortableFields)
+	if err != nil {
+		return model.Page[model.RecipeView]{}, err
+	}
+	filter := recipeviewrepo.Filter{
+		Name:              opts.Name,
+		ExcludedAllergens: excludedAllergens,
+		CategoryID:        classification.CategoryID,
+		Tags:              classification.Tags,
+		AsOf:              opts.AsOf,
+	}
+	if opts.AsOf.IsZero() {
+		return cr.repository.RecipeViews().FindPage(ctx, filter, pageOpts)
+	}
+	var page model.Page[model.RecipeView]
+	err = cr.repository.Atomic(ctx, func(repo repo.Repository) error {
+		page, err = repo.RecipeViews().FindPage(ctx, filter, pageOpts)
+		if err != nil {
+			return err
+		}
+		for i := range page.Data {
+			if err := recipeAsOf(ctx, repo, &page.Data[i], opts.AsOf); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Page[model.RecipeView]{}, err
+	}
+	return page, nil
 }
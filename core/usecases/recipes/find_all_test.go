@@ -17,7 +17,7 @@ func TestFindAll(t *testing.T) {
 	clock := clock.New()
 
 	t.Run("should get correct recipes if existent", func(t *testing.T) {
-		ingredients, recipeComponent, ctx := setupTest(logger, clock)
+		ingredients, recipeComponent, ctx := setupTest(t, logger, clock)
 		recipe1, err := recipeComponent.Create(ctx, recipes.CreateRecipeOptions{
 			Name: "recipe1",
 			Ingredients: []model.RecipeIngredient{
@@ -39,8 +39,9 @@ func TestFindAll(t *testing.T) {
 		})
 		require.NoError(t, err)
 
-		recipes, err := recipeComponent.FindAll(ctx)
+		page, err := recipeComponent.FindAll(ctx, recipes.FindAllOptions{})
 		require.NoError(t, err)
+		recipes := page.Data
 		assert.Equal(t, recipe1.Name, recipes[0].Name)
 		assert.Equal(t, recipe1.ID, recipes[0].ID)
 		assert.Equal(t, recipe1.CreatedAt, recipes[0].CreatedAt)
@@ -0,0 +1,108 @@
+package recipes_test
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	"costly/core/ports/database"
+	"costly/core/ports/logger"
+	"costly/core/usecases/ingredients"
+	"costly/core/usecases/recipes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// manualClock is a clock whose time is moved by hand between operations.
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
+
+func TestFindAsOf(t *testing.T) {
+	logger, _ := logger.New("debug")
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 12, 0, 0, 0, time.UTC) }
+
+	setup := func(t *testing.T) (recipes.RecipeUseCases, context.Context) {
+		clock := &manualClock{now: day(1)}
+		db, err := database.NewFromDatasource(":memory:", logger)
+		require.NoError(t, err)
+		ingredientComponent := ingredients.New(db, clock)
+		recipeComponent := recipes.New(db, clock, logger, ingredientComponent)
+		ctx := context.Background()
+
+		meat, err := ingredientComponent.Create(ctx, ingredients.CreateIngredientOptions{Name: "meat", Price: 1.0, Unit: model.Gram})
+		require.NoError(t, err)
+		milk, err := ingredientComponent.Create(ctx, ingredients.CreateIngredientOptions{Name: "butter", Price: 2.0, Unit: model.Gram, Allergens: []model.Allergen{model.Milk}})
+		require.NoError(t, err)
+		_, err = recipeComponent.Create(ctx, recipes.CreateRecipeOptions{Name: "steak", Ingredients: []model.RecipeIngredient{{ID: meat.ID, Units: 200}}})
+		require.NoError(t, err)
+
+		clock.now = day(10)
+		require.NoError(t, ingredientComponent.Update(ctx, meat.ID, 0, ingredients.CreateIngredientOptions{Name: "meat", Price: 1.5, Unit: model.Gram}))
+		clock.now = day(20)
+		require.NoError(t, recipeComponent.Update(ctx, 1, 0, recipes.CreateRecipeOptions{Name: "buttered steak", Ingredients: []model.RecipeIngredient{{ID: meat.ID, Units: 200}, {ID: milk.ID, Units: 10}}}))
+		clock.now = day(25)
+		_, err = ingredientComponent.AddStock(ctx, meat.ID, ingredients.IngredientStockOptions{Units: 200, Price: 2.5})
+		require.NoError(t, err)
+		return recipeComponent, ctx
+	}
+
+	t.Run("should cost the revision active then with the prices effective then", func(t *testing.T) {
+		recipeComponent, ctx := setup(t)
+
+		recipe, err := recipeComponent.FindAsOf(ctx, 1, day(5))
+		require.NoError(t, err)
+		assert.Equal(t, "steak", recipe.Name)
+		assert.Equal(t, 200.0, recipe.Cost())
+
+		recipe, err = recipeComponent.FindAsOf(ctx, 1, day(15))
+		require.NoError(t, err)
+		assert.Equal(t, "steak", recipe.Name)
+		assert.Equal(t, 300.0, recipe.Cost())
+
+		recipe, err = recipeComponent.FindAsOf(ctx, 1, day(22))
+		require.NoError(t, err)
+		assert.Equal(t, "buttered steak", recipe.Name)
+		assert.Equal(t, 320.0, recipe.Cost())
+
+		current, err := recipeComponent.Find(ctx, 1)
+		require.NoError(t, err)
+		asOfNow, err := recipeComponent.FindAsOf(ctx, 1, day(30))
+		require.NoError(t, err)
+		assert.Equal(t, current.Cost(), asOfNow.Cost())
+	})
+
+	t.Run("should return error if the recipe did not exist then", func(t *testing.T) {
+		recipeComponent, ctx := setup(t)
+		_, err := recipeComponent.FindAsOf(ctx, 1, day(1).Add(-time.Hour))
+		assert.Equal(t, errs.ErrNotFound, err)
+	})
+
+	t.Run("should filter allergens of the recipes as they were", func(t *testing.T) {
+		recipeComponent, ctx := setup(t)
+		found, err := recipeComponent.FindAll(ctx, recipes.FindAllOptions{ExcludedAllergens: []model.Allergen{model.Milk}, AsOf: day(15)})
+		require.NoError(t, err)
+		require.Len(t, found.Data, 1)
+		assert.Equal(t, 300.0, found.Data[0].Cost())
+
+		found, err = recipeComponent.FindAll(ctx, recipes.FindAllOptions{ExcludedAllergens: []model.Allergen{model.Milk}, AsOf: day(22)})
+		require.NoError(t, err)
+		assert.Empty(t, found.Data)
+	})
+	t.Run("should fill pages with the recipes that existed then", func(t *testing.T) {
+		recipeComponent, ctx := setup(t)
+		_, err := recipeComponent.Create(ctx, recipes.CreateRecipeOptions{Name: "apple pie", Ingredients: []model.RecipeIngredient{{ID: 1, Units: 100}}})
+		require.NoError(t, err)
+
+		found, err := recipeComponent.FindAll(ctx, recipes.FindAllOptions{AsOf: day(22), Page: model.PageOptions{Limit: 1, SortBy: model.SortByName}})
+		require.NoError(t, err)
+		require.Len(t, found.Data, 1)
+		assert.Equal(t, "buttered steak", found.Data[0].Name)
+	})
+}
@@ -17,7 +17,7 @@ func TestFind(t *testing.T) {
 	clock := clock.New()
 
 	t.Run("should get correct recipe if existent", func(t *testing.T) {
-		ingredients, recipeComponent, ctx := setupTest(logger, clock)
+		ingredients, recipeComponent, ctx := setupTest(t, logger, clock)
 		recipe1, err := recipeComponent.Create(ctx, recipes.CreateRecipeOptions{
 			Name: "recipe1",
 			Ingredients: []model.RecipeIngredient{
@@ -51,7 +51,7 @@ func TestFind(t *testing.T) {
 	})
 
 	t.Run("should return error when requesting an inexistent ingredient", func(t *testing.T) {
-		_, recipeComponent, ctx := setupTest(logger, clock)
+		_, recipeComponent, ctx := setupTest(t, logger, clock)
 		_, err := recipeComponent.Find(ctx, 123)
 
 		require.Error(t, err)
@@ -0,0 +1,205 @@
+package recipes
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	repo "costly/core/ports/repository"
+	ingredientrepo "costly/core/ports/repository/ingredient"
+	recipeviewrepo "costly/core/ports/repository/recipe_view"
+	"costly/core/ports/spreadsheet"
+	"costly/core/usecases/batch"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type RecipeImporter interface {
+	// Import creates the recipes of a recipe book spreadsheet, or updates the
+	// ones with the same name as a recipe that already exists. Ingredients are
+	// matched by name to the most similar one in the catalog.
+	Import(ctx context.Context, importOpts batch.ImportOptions) (*model.ImportReport, error)
+}
+
+// RecipeImportFields are the fields read from every row of a recipe book,
+// which has a row for every ingredient of a recipe. The price and portions of
+// the recipe are read from the first row having them.
+var RecipeImportFields = []string{"recipe", "ingredient", "quantity", "price", "portions"}
+
+// recipeImport is a recipe read from the rows of a recipe book.
+type recipeImport struct {
+	line     int
+	name     string
+	price    *float64
+	portions *int
+	units    map[int64]int
+	order    []int64
+	matches  []model.ImportMatch
+	errors   []model.ImportError
+}
+
+func (cr *recipeUseCases) Import(ctx context.Context, importOpts batch.ImportOptions) (*model.ImportReport, error) {
+	table, err := spreadsheet.Read(importOpts.FileName, importOpts.Content)
+	if err != nil {
+		return &model.ImportReport{}, err
+	}
+	records, err := table.Records(importOpts.Columns, RecipeImportFields)
+	if err != nil {
+		return &model.ImportReport{}, err
+	}
+	catalog, err := cr.repository.Ingredients().FindAll(ctx, ingredientrepo.Filter{})
+	if err != nil {
+		return &model.ImportReport{}, err
+	}
+	existing, err := cr.repository.RecipeViews().FindAll(ctx, recipeviewrepo.Filter{})
+	if err != nil {
+		return &model.ImportReport{}, err
+	}
+	recipeIDs := map[string]int64{}
+	for _, recipe := range existing {
+		recipeIDs[strings.ToLower(recipe.Name)] = recipe.ID
+	}
+	imports := readRecipeImports(records, catalog)
+
+	actions := make([]model.ImportAction, len(imports))
+	results, err := batch.Import(ctx, cr.repository, importOpts.DryRun, len(imports), func(repo repo.Repository, i int) (int64, error) {
+		imported := imports[i]
+		if len(imported.errors) > 0 {
+			return 0, errs.ErrBadOpts
+		}
+		name := strings.ToLower(imported.name)
+		if recipeID, ok := recipeIDs[name]; ok {
+			found, err := repo.Recipes().Find(ctx, recipeID)
+			if err != nil {
+				return 0, err
+			}
+			// what the recipe book does not say is kept as it is
+			recipeOpts := imported.options(CreateRecipeOptions{
+				Price:        found.Price,
+				Portions:     found.Portions,
+				RecipeMethod: found.RecipeMethod,
+				CategoryID:   found.CategoryID,
+				Tags:         found.Tags,
+			})
+			actions[i] = model.ImportUpdated
+			return recipeID, cr.update(ctx, repo, recipeID, found.Version, recipeOpts)
+		}
+		recipe, err := cr.create(ctx, repo, imported.options(CreateRecipeOptions{}))
+		if err != nil {
+			return 0, err
+		}
+		recipeIDs[name] = recipe.ID
+		actions[i] = model.ImportCreated
+		return recipe.ID, nil
+	})
+	if err != nil {
+		return &model.ImportReport{}, err
+	}
+
+	report := &model.ImportReport{DryRun: importOpts.DryRun, Rows: []model.ImportRow{}}
+	for i, result := range results {
+		row := model.ImportRow{Line: imports[i].line, Name: imports[i].name, Action: actions[i], ID: result.ID, Matches: imports[i].matches}
+		if result.Err != nil {
+			if !batch.Rejected(result.Err) {
+				return &model.ImportReport{}, result.Err
+			}
+			row.Action = model.ImportRejected
+			row.Errors = imports[i].errors
+			if len(row.Errors) == 0 {
+				row.Errors = model.NewImportErrors(imports[i].line, result.Err)
+			}
+		}
+		report.Add(row)
+	}
+	return report, nil
+}
+
+// readRecipeImports groups the rows of a recipe book by recipe, in the order
+// they first appear, matching their ingredients to the catalog.
+func readRecipeImports(records []spreadsheet.Record, catalog []model.Ingredient) []*recipeImport {
+	names := make([]string, len(catalog))
+	for i, ingredient := range catalog {
+		names[i] = ingredient.Name
+	}
+	imports := []*recipeImport{}
+	byName := map[string]*recipeImport{}
+	for _, record := range records {
+		name := record.Values["recipe"]
+		imported, ok := byName[strings.ToLower(name)]
+		if !ok {
+			imported = &recipeImport{line: record.Line, name: name, units: map[int64]int{}}
+			byName[strings.ToLower(name)] = imported
+			imports = append(imports, imported)
+		}
+		imported.read(record, catalog, names)
+	}
+	return imports
+}
+
+func (ri *recipeImport) read(record spreadsheet.Record, catalog []model.Ingredient, names []string) {
+	reject := func(err *errs.ValidationError) {
+		ri.errors = append(ri.errors, model.ImportError{Line: record.Line, Field: err.Field, Message: err.Message})
+	}
+	if ri.name == "" {
+		reject(errs.ErrBadName.At("recipe"))
+	}
+	if value := record.Values["price"]; value != "" && ri.price == nil {
+		if price, err := model.ParseDecimal(value); err == nil {
+			ri.price = &price
+		} else {
+			reject(errs.ErrBadPrice)
+		}
+	}
+	if value := record.Values["portions"]; value != "" && ri.portions == nil {
+		if portions, err := strconv.Atoi(value); err == nil {
+			ri.portions = &portions
+		} else {
+			reject(errs.ErrBadPortions)
+		}
+	}
+	index, err := model.MatchName(record.Values["ingredient"], names)
+	if err != nil {
+		reject(errs.ErrUnmatchedIngr)
+		return
+	}
+	ingredient := catalog[index]
+	if !strings.EqualFold(strings.Join(strings.Fields(record.Values["ingredient"]), " "), ingredient.Name) {
+		ri.matches = append(ri.matches, model.ImportMatch{
+			Line:         record.Line,
+			Name:         record.Values["ingredient"],
+			IngredientID: ingredient.ID,
+			MatchedName:  ingredient.Name,
+		})
+	}
+	quantity, err := model.ParseQuantity(record.Values["quantity"])
+	if err != nil {
+		reject(errs.ErrBadQuantity)
+		return
+	}
+	grams, err := quantity.Grams()
+	if err != nil {
+		reject(errs.ErrBadUnit.At("quantity"))
+		return
+	}
+	if _, ok := ri.units[ingredient.ID]; !ok {
+		ri.order = append(ri.order, ingredient.ID)
+	}
+	// an ingredient listed twice in a recipe is used as much as both rows say
+	ri.units[ingredient.ID] += int(math.Round(grams))
+}
+
+// options are the options of the recipe read, on top of the given ones.
+func (ri *recipeImport) options(recipeOpts CreateRecipeOptions) CreateRecipeOptions {
+	recipeOpts.Name = ri.name
+	if ri.price != nil {
+		recipeOpts.Price = *ri.price
+	}
+	if ri.portions != nil {
+		recipeOpts.Portions = *ri.portions
+	}
+	recipeOpts.Ingredients = []model.RecipeIngredient{}
+	for _, ingredientID := range ri.order {
+		recipeOpts.Ingredients = append(recipeOpts.Ingredients, model.RecipeIngredient{ID: ingredientID, Units: ri.units[ingredientID]})
+	}
+	return recipeOpts
+}
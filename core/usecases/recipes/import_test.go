@@ -0,0 +1,73 @@
+package recipes_test
+
+import (
+	"costly/core/model"
+	"costly/core/ports/clock"
+	"costly/core/ports/logger"
+	"costly/core/usecases/batch"
+	"costly/core/usecases/recipes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImport(t *testing.T) {
+	logger, _ := logger.New("debug")
+	clock := clock.New()
+
+	t.Run("should create recipes matching their ingredients to the catalog", func(t *testing.T) {
+		_, recipeUseCases, ctx := setupTest(t, logger, clock)
+		report, err := recipeUseCases.Import(ctx, batch.ImportOptions{
+			FileName: "book.csv",
+			Content: strings.NewReader("Plato;Ingrediente;Cantidad;Raciones\n" +
+				"burger;Meat;0,2 kg;2\n" +
+				"burger;salt;5 gr;\n" +
+				"burger;SALT;1g;\n" +
+				"burger;peper;2;\n"),
+			Columns: map[string]string{"recipe": "Plato", "ingredient": "Ingrediente", "quantity": "Cantidad", "portions": "Raciones"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &model.ImportReport{
+			Created: 1,
+			Rows: []model.ImportRow{{
+				Line:    2,
+				Name:    "burger",
+				Action:  model.ImportCreated,
+				ID:      1,
+				Matches: []model.ImportMatch{{Line: 5, Name: "peper", IngredientID: 3, MatchedName: "pepper"}},
+			}},
+		}, report)
+		recipe, err := recipeUseCases.Find(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 2, recipe.Portions)
+		assert.Equal(t, []model.RecipeIngredientView{
+			{ID: 1, Name: "meat", Unit: model.Gram, Price: 1, Units: 200, Allergens: []model.Allergen{}},
+			{ID: 2, Name: "salt", Unit: model.Gram, Price: 10, Units: 6, Allergens: []model.Allergen{}},
+			{ID: 3, Name: "pepper", Unit: model.Gram, Price: 13, Units: 2, Allergens: []model.Allergen{}},
+		}, recipe.Ingredients)
+	})
+
+	t.Run("should update recipes keeping what the book does not say", func(t *testing.T) {
+		_, recipeUseCases, ctx := setupTest(t, logger, clock)
+		_, err := recipeUseCases.Create(ctx, recipes.CreateRecipeOptions{
+			Name:         "Burger",
+			Price:        9,
+			Ingredients:  []model.RecipeIngredient{{ID: 1, Units: 150}},
+			RecipeMethod: model.RecipeMethod{Steps: []string{"grill"}},
+		})
+		require.NoError(t, err)
+		report, err := recipeUseCases.Import(ctx, batch.ImportOptions{
+			FileName: "book.csv",
+			Content:  strings.NewReader("recipe,ingredient,quantity\nburger,meat,180g\n"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Updated)
+		recipe, err := recipeUseCases.Find(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "burger", recipe.Name)
+		assert.Equal(t, 9.0, recipe.Price)
+		assert.Equal(t, 180, recipe.Ingredients[0].Units)
+	})
+}
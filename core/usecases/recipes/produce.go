@@ -0,0 +1,66 @@
+package recipes
+
+import (
+	"context"
+	"costly/core/model"
+	repo "costly/core/ports/repository"
+)
+
+type ProductionOptions struct {
+	IngredientID int64 `json:"ingredient_id"`
+	Batches      int   `json:"batches"`
+	Units        int   `json:"units"`
+}
+
+type RecipeProducer interface {
+	Produce(ctx context.Context, recipeID int64, productionOpts ProductionOptions) (*model.Production, error)
+}
+
+// Produce consumes the ingredients of a prep recipe and adds the produced units to
+// the stock of the prep ingredient, so that sales of dishes using it decrement the
+// prep ingredient instead of the raw ones.
+func (cr *recipeUseCases) Produce(ctx context.Context, recipeID int64, productionOpts ProductionOptions) (*model.Production, error) {
+	var production *model.Production
+	now := cr.clock.Now()
+	if err := cr.repository.Atomic(ctx, func(repo repo.Repository) error {
+		recipe, err := repo.Recipes().Find(ctx, recipeID)
+		if err != nil {
+			return err
+		}
+		if _, err := repo.Ingredients().Find(ctx, productionOpts.IngredientID); err != nil {
+			return err
+		}
+		recipeIngredients, err := repo.RecipeViews().FindIngredients(ctx, recipeID)
+		if err != nil {
+			return err
+		}
+		production, err = model.NewProduction(model.RecipeView{
+			ID:          recipe.ID,
+			Name:        recipe.Name,
+			Portions:    recipe.Portions,
+			Ingredients: recipeIngredients,
+		}, productionOpts.IngredientID, productionOpts.Batches, productionOpts.Units, now)
+		if err != nil {
+			return err
+		}
+		for _, recipeIngredient := range recipeIngredients {
+			if err := repo.Ingredients().ConsumeStock(ctx, recipeIngredient.ID, production.Batches*recipeIngredient.Units, now); err != nil {
+				return err
+			}
+		}
+		ingredientStock, err := model.NewIngredientStock(production.IngredientID, production.Units, production.Price, now)
+		if err != nil {
+			return err
+		}
+		if err := repo.IngredientStocks().Add(ctx, ingredientStock); err != nil {
+			return err
+		}
+		if err := repo.Ingredients().IncreaseStockAndUpdatePrice(ctx, production.IngredientID, production.Units, production.Price, now); err != nil {
+			return err
+		}
+		return repo.Productions().Add(ctx, production)
+	}); err != nil {
+		return &model.Production{}, err
+	}
+	return production, nil
+}
@@ -0,0 +1,163 @@
+package recipes_test
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	"costly/core/ports/clock"
+	"costly/core/ports/database"
+	"costly/core/ports/logger"
+	"costly/core/usecases/ingredients"
+	"costly/core/usecases/recipes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupProductionTest(t *testing.T) (ingredients.IngredientUseCases, recipes.RecipeUseCases, context.Context) {
+	logger, _ := logger.New("debug")
+	clock := clock.New()
+	db, err := database.NewFromDatasource(":memory:", logger)
+	require.NoError(t, err)
+	ingredientUseCases := ingredients.New(db, clock)
+	return ingredientUseCases, recipes.New(db, clock, logger, ingredientUseCases), context.Background()
+}
+
+func TestProduce(t *testing.T) {
+
+	t.Run("should consume components and add stock of the prep ingredient at rolled-up cost", func(t *testing.T) {
+		ingredientComponent, recipeComponent, ctx := setupProductionTest(t)
+		tomato, err := ingredientComponent.Create(ctx, ingredients.CreateIngredientOptions{Name: "tomato", Price: 0.5, Unit: model.Gram})
+		require.NoError(t, err)
+		salt, err := ingredientComponent.Create(ctx, ingredients.CreateIngredientOptions{Name: "salt", Price: 2.0, Unit: model.Gram})
+		require.NoError(t, err)
+		sauce, err := ingredientComponent.Create(ctx, ingredients.CreateIngredientOptions{Name: "tomato sauce", Price: 1.0, Unit: model.Gram})
+		require.NoError(t, err)
+		_, err = ingredientComponent.AddStock(ctx, tomato.ID, ingredients.IngredientStockOptions{Units: 2500, Price: 0.5})
+		require.NoError(t, err)
+		_, err = ingredientComponent.AddStock(ctx, salt.ID, ingredients.IngredientStockOptions{Units: 50, Price: 2.0})
+		require.NoError(t, err)
+		sauceRecipe, err := recipeComponent.Create(ctx, recipes.CreateRecipeOptions{
+			Name:        "tomato sauce batch",
+			Ingredients: []model.RecipeIngredient{{ID: tomato.ID, Units: 1000}, {ID: salt.ID, Units: 10}},
+		})
+		require.NoError(t, err)
+
+		production, err := recipeComponent.Produce(ctx, sauceRecipe.ID, recipes.ProductionOptions{
+			IngredientID: sauce.ID,
+			Batches:      2,
+			Units:        1600,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), production.ID)
+		assert.Equal(t, sauceRecipe.ID, production.RecipeID)
+		assert.Equal(t, sauce.ID, production.IngredientID)
+		assert.Equal(t, (1000*0.5+10*2.0)*2/1600, production.Price)
+
+		tomatoGet, err := ingredientComponent.Find(ctx, tomato.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 500, tomatoGet.UnitsInStock)
+		saltGet, err := ingredientComponent.Find(ctx, salt.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 30, saltGet.UnitsInStock)
+		sauceGet, err := ingredientComponent.Find(ctx, sauce.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1600, sauceGet.UnitsInStock)
+		assert.Equal(t, production.Price, sauceGet.Price)
+	})
+
+	t.Run("sales of dishes should decrement the prep ingredient instead of raw ingredients", func(t *testing.T) {
+		ingredientComponent, recipeComponent, ctx := setupProductionTest(t)
+		tomato, err := ingredientComponent.Create(ctx, ingredients.CreateIngredientOptions{Name: "tomato", Price: 0.5, Unit: model.Gram})
+		require.NoError(t, err)
+		sauce, err := ingredientComponent.Create(ctx, ingredients.CreateIngredientOptions{Name: "tomato sauce", Price: 1.0, Unit: model.Gram})
+		require.NoError(t, err)
+		pasta, err := ingredientComponent.Create(ctx, ingredients.CreateIngredientOptions{Name: "pasta", Price: 0.2, Unit: model.Gram})
+		require.NoError(t, err)
+		_, err = ingredientComponent.AddStock(ctx, tomato.ID, ingredients.IngredientStockOptions{Units: 1000, Price: 0.5})
+		require.NoError(t, err)
+		_, err = ingredientComponent.AddStock(ctx, pasta.ID, ingredients.IngredientStockOptions{Units: 300, Price: 0.2})
+		require.NoError(t, err)
+		sauceRecipe, err := recipeComponent.Create(ctx, recipes.CreateRecipeOptions{
+			Name:        "tomato sauce batch",
+			Ingredients: []model.RecipeIngredient{{ID: tomato.ID, Units: 1000}},
+		})
+		require.NoError(t, err)
+		dish, err := recipeComponent.Create(ctx, recipes.CreateRecipeOptions{
+			Name:        "pasta al pomodoro",
+			Ingredients: []model.RecipeIngredient{{ID: pasta.ID, Units: 100}, {ID: sauce.ID, Units: 80}},
+		})
+		require.NoError(t, err)
+		_, err = recipeComponent.Produce(ctx, sauceRecipe.ID, recipes.ProductionOptions{IngredientID: sauce.ID, Batches: 1, Units: 800})
+		require.NoError(t, err)
+
+		_, err = recipeComponent.AddSales(ctx, dish.ID, 3)
+		require.NoError(t, err)
+
+		tomatoGet, err := ingredientComponent.Find(ctx, tomato.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, tomatoGet.UnitsInStock)
+		sauceGet, err := ingredientComponent.Find(ctx, sauce.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 800-3*80, sauceGet.UnitsInStock)
+	})
+
+	t.Run("should return error if recipe consumes the produced ingredient", func(t *testing.T) {
+		ingredientComponent, recipeComponent, ctx := setupProductionTest(t)
+		tomato, err := ingredientComponent.Create(ctx, ingredients.CreateIngredientOptions{Name: "tomato", Price: 0.5, Unit: model.Gram})
+		require.NoError(t, err)
+		recipe, err := recipeComponent.Create(ctx, recipes.CreateRecipeOptions{
+			Name:        "tomato sauce batch",
+			Ingredients: []model.RecipeIngredient{{ID: tomato.ID, Units: 1000}},
+		})
+		require.NoError(t, err)
+
+		_, err = recipeComponent.Produce(ctx, recipe.ID, recipes.ProductionOptions{IngredientID: tomato.ID, Batches: 1, Units: 800})
+		assert.Equal(t, errs.ErrBadProducedIngr, err)
+		tomatoGet, err := ingredientComponent.Find(ctx, tomato.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, tomatoGet.UnitsInStock)
+	})
+
+	t.Run("should return error and consume nothing if there is not enough stock", func(t *testing.T) {
+		ingredientComponent, recipeComponent, ctx := setupProductionTest(t)
+		tomato, err := ingredientComponent.Create(ctx, ingredients.CreateIngredientOptions{Name: "tomato", Price: 0.5, Unit: model.Gram})
+		require.NoError(t, err)
+		salt, err := ingredientComponent.Create(ctx, ingredients.CreateIngredientOptions{Name: "salt", Price: 2.0, Unit: model.Gram})
+		require.NoError(t, err)
+		sauce, err := ingredientComponent.Create(ctx, ingredients.CreateIngredientOptions{Name: "tomato sauce", Price: 1.0, Unit: model.Gram})
+		require.NoError(t, err)
+		_, err = ingredientComponent.AddStock(ctx, tomato.ID, ingredients.IngredientStockOptions{Units: 1000, Price: 0.5})
+		require.NoError(t, err)
+		_, err = ingredientComponent.AddStock(ctx, salt.ID, ingredients.IngredientStockOptions{Units: 5, Price: 2.0})
+		require.NoError(t, err)
+		recipe, err := recipeComponent.Create(ctx, recipes.CreateRecipeOptions{
+			Name:        "tomato sauce batch",
+			Ingredients: []model.RecipeIngredient{{ID: tomato.ID, Units: 1000}, {ID: salt.ID, Units: 10}},
+		})
+		require.NoError(t, err)
+
+		_, err = recipeComponent.Produce(ctx, recipe.ID, recipes.ProductionOptions{IngredientID: sauce.ID, Batches: 1, Units: 800})
+		assert.Equal(t, &errs.InsufficientStockError{IngredientID: salt.ID, Available: 5, Required: 10}, err)
+		tomatoGet, err := ingredientComponent.Find(ctx, tomato.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1000, tomatoGet.UnitsInStock)
+	})
+
+	t.Run("should return error if unexistent recipe or ingredient", func(t *testing.T) {
+		ingredientComponent, recipeComponent, ctx := setupProductionTest(t)
+		tomato, err := ingredientComponent.Create(ctx, ingredients.CreateIngredientOptions{Name: "tomato", Price: 0.5, Unit: model.Gram})
+		require.NoError(t, err)
+		recipe, err := recipeComponent.Create(ctx, recipes.CreateRecipeOptions{
+			Name:        "tomato sauce batch",
+			Ingredients: []model.RecipeIngredient{{ID: tomato.ID, Units: 1000}},
+		})
+		require.NoError(t, err)
+
+		_, err = recipeComponent.Produce(ctx, 123, recipes.ProductionOptions{IngredientID: tomato.ID, Batches: 1, Units: 800})
+		assert.Equal(t, errs.ErrNotFound, err)
+		_, err = recipeComponent.Produce(ctx, recipe.ID, recipes.ProductionOptions{IngredientID: 123, Batches: 1, Units: 800})
+		assert.Equal(t, errs.ErrNotFound, err)
+	})
+}
@@ -10,9 +10,15 @@ import (
 
 type RecipeUseCases interface {
 	RecipeCreator
+	RecipeEditor
+	RecipeBatcher
+	RecipeImporter
 	RecipeSalesAdder
+	RecipeProducer
 	RecipeFinder
 	RecipesFinder
+	RecipeVersionsFinder
+	RecipeSimulator
 }
 
 type recipeUseCases struct {
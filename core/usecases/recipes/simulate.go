@@ -0,0 +1,44 @@
+package recipes
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	repo "costly/core/ports/repository"
+)
+
+type RecipeSimulator interface {
+	// Simulate costs the recipe with some hypothetical changes and compares it
+	// with its current cost, without persisting anything.
+	Simulate(ctx context.Context, id int64, changes model.RecipeChanges) (model.RecipeSimulation, error)
+}
+
+func (cr *recipeUseCases) Simulate(ctx context.Context, id int64, changes model.RecipeChanges) (model.RecipeSimulation, error) {
+	recipe, err := cr.Find(ctx, id)
+	if err != nil {
+		return model.RecipeSimulation{}, err
+	}
+	substitutes := map[int64]model.RecipeIngredientView{}
+	if err := cr.repository.Atomic(ctx, func(repo repo.Repository) error {
+		for _, substitution := range changes.Substitutions {
+			ingredient, err := repo.Ingredients().Find(ctx, substitution.SubstituteID)
+			if err == errs.ErrNotFound {
+				return errs.ErrBadIngredient
+			} else if err != nil {
+				return err
+			}
+			substitutes[ingredient.ID] = model.RecipeIngredientView{
+				ID:        ingredient.ID,
+				Name:      ingredient.Name,
+				Unit:      ingredient.Unit,
+				Price:     ingredient.Price,
+				Allergens: ingredient.Allergens,
+				Nutrition: ingredient.Nutrition,
+			}
+		}
+		return nil
+	}); err != nil {
+		return model.RecipeSimulation{}, err
+	}
+	return model.NewRecipeSimulation(recipe, changes, substitutes)
+}
@@ -0,0 +1,53 @@
+package recipes
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	repo "costly/core/ports/repository"
+	"costly/core/usecases/categories"
+)
+
+type RecipeEditor interface {
+	// Update replaces the recipe and makes a new revision of it. The recipe must
+	// still have the given version, or any if zero.
+	Update(ctx context.Context, recipeID int64, version int, recipeOpts CreateRecipeOptions) error
+}
+
+func (cr *recipeUseCases) Update(ctx context.Context, recipeID int64, version int, recipeOpts CreateRecipeOptions) error {
+	return cr.update(ctx, cr.repository, recipeID, version, recipeOpts)
+}
+
+func (cr *recipeUseCases) update(ctx context.Context, repository repo.Repository, recipeID int64, version int, recipeOpts CreateRecipeOptions) error {
+	updatedRecipe, err := recipeOpts.newRecipe(cr.clock.Now())
+	if err != nil {
+		return err
+	}
+	return repository.Atomic(ctx, func(repo repo.Repository) error {
+		classification, err := categories.Classify(ctx, repo, recipeOpts.CategoryID, recipeOpts.Tags)
+		if err != nil {
+			return err
+		}
+		if err := checkIngredients(ctx, repo, updatedRecipe.Ingredients); err != nil {
+			return err
+		}
+		var recipe model.Recipe
+		if err := repo.Recipes().Update(ctx, recipeID, func(found *model.Recipe) error {
+			if version != 0 && found.Version != version {
+				return errs.NewStaleError("recipe")
+			}
+			found.Name = updatedRecipe.Name
+			found.Price = updatedRecipe.Price
+			found.Portions = updatedRecipe.Portions
+			found.Ingredients = updatedRecipe.Ingredients
+			found.RecipeMethod = updatedRecipe.RecipeMethod
+			found.Classification = classification
+			found.LastModified = updatedRecipe.LastModified
+			recipe = *found
+			return nil
+		}); err != nil {
+			return err
+		}
+		return addRevision(ctx, repo, recipe)
+	})
+}
@@ -0,0 +1,104 @@
+package recipes_test
+
+import (
+	"costly/core/errs"
+	"costly/core/model"
+	"costly/core/ports/clock"
+	"costly/core/ports/logger"
+	"costly/core/usecases/ingredients"
+	"costly/core/usecases/recipes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdate(t *testing.T) {
+	logger, _ := logger.New("debug")
+	clock := clock.New()
+
+	t.Run("should update the recipe and make a new version of it", func(t *testing.T) {
+		ingrs, recipeComponent, ctx := setupTest(t, logger, clock)
+		recipe, err := recipeComponent.Create(ctx, recipes.CreateRecipeOptions{
+			Name:        "steak",
+			Ingredients: []model.RecipeIngredient{{ID: ingrs[0].ID, Units: 200}, {ID: ingrs[1].ID, Units: 2}},
+		})
+		require.NoError(t, err)
+
+		err = recipeComponent.Update(ctx, recipe.ID, 0, recipes.CreateRecipeOptions{
+			Name:         "peppered steak",
+			Portions:     2,
+			Ingredients:  []model.RecipeIngredient{{ID: ingrs[0].ID, Units: 300}, {ID: ingrs[2].ID, Units: 1}},
+			RecipeMethod: model.RecipeMethod{Steps: []string{"grill"}},
+		})
+		require.NoError(t, err)
+
+		updated, err := recipeComponent.Find(ctx, recipe.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "peppered steak", updated.Name)
+		assert.Equal(t, 2, updated.Portions)
+		assert.Equal(t, []string{"grill"}, updated.Steps)
+		assert.Equal(t, 313.0, updated.Cost())
+
+		versions, err := recipeComponent.FindVersions(ctx, recipe.ID)
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		assert.Equal(t, 1, versions[0].Version)
+		assert.Equal(t, 220.0, versions[0].Cost)
+		assert.Equal(t, 2, versions[1].Version)
+		assert.Equal(t, 313.0, versions[1].Cost)
+	})
+
+	t.Run("should keep the cost of old versions when prices change", func(t *testing.T) {
+		ingredientComponent, recipeComponent, ctx := setupProductionTest(t)
+		meat, err := ingredientComponent.Create(ctx, ingredients.CreateIngredientOptions{Name: "meat", Price: 1.0, Unit: model.Gram})
+		require.NoError(t, err)
+		recipe, err := recipeComponent.Create(ctx, recipes.CreateRecipeOptions{Name: "steak", Ingredients: []model.RecipeIngredient{{ID: meat.ID, Units: 200}}})
+		require.NoError(t, err)
+		sold, err := recipeComponent.AddSales(ctx, recipe.ID, 1)
+		require.NoError(t, err)
+
+		require.NoError(t, ingredientComponent.Update(ctx, meat.ID, 0, ingredients.CreateIngredientOptions{Name: "meat", Price: 2.0, Unit: model.Gram}))
+		require.NoError(t, recipeComponent.Update(ctx, recipe.ID, 0, recipes.CreateRecipeOptions{Name: "steak", Ingredients: []model.RecipeIngredient{{ID: meat.ID, Units: 250}}}))
+		soldAfterUpdate, err := recipeComponent.AddSales(ctx, recipe.ID, 1)
+		require.NoError(t, err)
+		assert.NotEqual(t, sold.RevisionID, soldAfterUpdate.RevisionID)
+
+		diff, err := recipeComponent.DiffVersions(ctx, recipe.ID, 1, 2)
+		require.NoError(t, err)
+		assert.Equal(t, model.Change[float64]{From: 200, To: 500}, diff.Cost)
+		require.Len(t, diff.Changed, 1)
+		assert.Equal(t, model.Change[int]{From: 200, To: 250}, diff.Changed[0].Units)
+		assert.Equal(t, model.Change[float64]{From: 1, To: 2}, diff.Changed[0].Price)
+	})
+
+	t.Run("should return error if recipe does not exist", func(t *testing.T) {
+		ingrs, recipeComponent, ctx := setupTest(t, logger, clock)
+		err := recipeComponent.Update(ctx, 123, 0, recipes.CreateRecipeOptions{
+			Name:        "steak",
+			Ingredients: []model.RecipeIngredient{{ID: ingrs[0].ID, Units: 200}},
+		})
+		assert.Equal(t, errs.ErrNotFound, err)
+	})
+
+	t.Run("should return error if recipe was edited since the version expected", func(t *testing.T) {
+		ingrs, recipeComponent, ctx := setupTest(t, logger, clock)
+		opts := recipes.CreateRecipeOptions{Name: "steak", Ingredients: []model.RecipeIngredient{{ID: ingrs[0].ID, Units: 200}}}
+		recipe, err := recipeComponent.Create(ctx, opts)
+		require.NoError(t, err)
+		require.NoError(t, recipeComponent.Update(ctx, recipe.ID, 1, opts))
+
+		err = recipeComponent.Update(ctx, recipe.ID, 1, opts)
+
+		assert.Equal(t, errs.NewStaleError("recipe"), err)
+		versions, err := recipeComponent.FindVersions(ctx, recipe.ID)
+		require.NoError(t, err)
+		assert.Len(t, versions, 2)
+	})
+
+	t.Run("should return error if options are invalid", func(t *testing.T) {
+		_, recipeComponent, ctx := setupTest(t, logger, clock)
+		err := recipeComponent.Update(ctx, 1, 0, recipes.CreateRecipeOptions{Name: "steak"})
+		assert.Equal(t, errs.ErrBadIngrs, err)
+	})
+}
@@ -0,0 +1,39 @@
+package recipes
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	repo "costly/core/ports/repository"
+)
+
+type RecipeVersionsFinder interface {
+	FindVersions(ctx context.Context, recipeID int64) ([]model.RecipeRevision, error)
+	DiffVersions(ctx context.Context, recipeID int64, from int, to int) (model.RecipeRevisionDiff, error)
+}
+
+func (cr *recipeUseCases) FindVersions(ctx context.Context, recipeID int64) ([]model.RecipeRevision, error) {
+	revisions, err := cr.repository.RecipeRevisions().FindAll(ctx, recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, errs.ErrNotFound
+	}
+	return revisions, nil
+}
+
+func (cr *recipeUseCases) DiffVersions(ctx context.Context, recipeID int64, from int, to int) (model.RecipeRevisionDiff, error) {
+	var fromRevision, toRevision model.RecipeRevision
+	if err := cr.repository.Atomic(ctx, func(repo repo.Repository) error {
+		var err error
+		if fromRevision, err = repo.RecipeRevisions().Find(ctx, recipeID, from); err != nil {
+			return err
+		}
+		toRevision, err = repo.RecipeRevisions().Find(ctx, recipeID, to)
+		return err
+	}); err != nil {
+		return model.RecipeRevisionDiff{}, err
+	}
+	return model.NewRecipeRevisionDiff(fromRevision, toRevision), nil
+}
@@ -0,0 +1,59 @@
+package scenarios
+
+import (
+	"context"
+	"costly/core/errs"
+	"costly/core/model"
+	repo "costly/core/ports/repository"
+	recipeviewrepo "costly/core/ports/repository/recipe_view"
+)
+
+const defaultSalesDays = 30
+
+type PriceShockOptions struct {
+	Changes []model.PriceChange
+	// Days are how many of the last days of sales weight the impact, 30 if not given.
+	Days *int
+}
+
+type PriceShocker interface {
+	// PriceShock simulates the impact of some ingredient price changes on every
+	// recipe and menu without persisting anything.
+	PriceShock(ctx context.Context, opts PriceShockOptions) (model.PriceShock, error)
+}
+
+func (sc *scenarioUseCases) PriceShock(ctx context.Context, opts PriceShockOptions) (model.PriceShock, error) {
+	days := defaultSalesDays
+	if opts.Days != nil {
+		days = *opts.Days
+	}
+	if days <= 0 {
+		return model.PriceShock{}, errs.ErrBadDays
+	}
+	since := sc.clock.Now().AddDate(0, 0, -days)
+	var shock model.PriceShock
+	err := sc.repository.Atomic(ctx, func(repo repo.Repository) error {
+		for _, change := range opts.Changes {
+			if _, err := repo.Ingredients().Find(ctx, change.ID); err == errs.ErrNotFound {
+				return errs.ErrBadIngredient
+			} else if err != nil {
+				return err
+			}
+		}
+		recipes, err := repo.RecipeViews().FindAll(ctx, recipeviewrepo.Filter{})
+		if err != nil {
+			return err
+		}
+		menus, err := repo.Menus().FindAll(ctx)
+		if err != nil {
+			return err
+		}
+		soldUnits, err := repo.RecipeSales().SoldUnits(ctx, since)
+		if err != nil {
+			return err
+		}
+		shock, err = model.NewPriceShock(recipes, menus, opts.Changes, soldUnits)
+		return err
+	})
+	return shock, err
+}
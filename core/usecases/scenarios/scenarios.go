@@ -0,0 +1,23 @@
+package scenarios
+
+import (
+	"costly/core/ports/clock"
+	"costly/core/ports/database"
+	repo "costly/core/ports/repository"
+)
+
+type ScenarioUseCases interface {
+	PriceShocker
+}
+
+type scenarioUseCases struct {
+	clock      clock.Clock
+	repository repo.Repository
+}
+
+func New(database database.Database, clock clock.Clock) ScenarioUseCases {
+	return &scenarioUseCases{
+		clock:      clock,
+		repository: repo.New(database),
+	}
+}
@@ -0,0 +1,36 @@
+package search
+
+import (
+	"context"
+	"costly/core/model"
+	"costly/core/ports/database"
+	repo "costly/core/ports/repository"
+)
+
+type SearchUseCases interface {
+	Searcher
+}
+
+type Searcher interface {
+	// Search gets the ingredients and recipes whose name, tags or steps have
+	// words starting with every word in the query, ignoring case and accents.
+	Search(ctx context.Context, query string) ([]model.SearchResult, error)
+}
+
+type searchUseCases struct {
+	repository repo.Repository
+}
+
+func New(database database.Database) SearchUseCases {
+	return &searchUseCases{
+		repository: repo.New(database),
+	}
+}
+
+func (sc *searchUseCases) Search(ctx context.Context, query string) ([]model.SearchResult, error) {
+	terms, err := model.NewSearchTerms(query)
+	if err != nil {
+		return nil, err
+	}
+	return sc.repository.Search().Search(ctx, terms)
+}
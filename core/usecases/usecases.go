@@ -2,13 +2,27 @@ package usecases
 
 import (
 	"costly/core/ports"
+	"costly/core/usecases/archives"
+	"costly/core/usecases/attachments"
+	"costly/core/usecases/backups"
+	"costly/core/usecases/categories"
 	"costly/core/usecases/ingredients"
+	"costly/core/usecases/menus"
 	"costly/core/usecases/recipes"
+	"costly/core/usecases/scenarios"
+	"costly/core/usecases/search"
 )
 
 type UseCases struct {
 	Ingredients ingredients.IngredientUseCases
 	Recipes     recipes.RecipeUseCases
+	Attachments attachments.AttachmentUseCases
+	Categories  categories.CategoryUseCases
+	Menus       menus.MenuUseCases
+	Scenarios   scenarios.ScenarioUseCases
+	Search      search.SearchUseCases
+	Archives    archives.ArchiveUseCases
+	Backups     backups.BackupUseCases
 }
 
 func New(ports *ports.Ports) (*UseCases, error) {
@@ -16,5 +30,12 @@ func New(ports *ports.Ports) (*UseCases, error) {
 	return &UseCases{
 		Ingredients: ingredientUseCases,
 		Recipes:     recipes.New(ports.Database, ports.Clock, ports.Logger, ingredientUseCases),
+		Attachments: attachments.New(ports.Database, ports.Clock, ports.Storage),
+		Categories:  categories.New(ports.Database, ports.Clock),
+		Menus:       menus.New(ports.Database, ports.Clock),
+		Scenarios:   scenarios.New(ports.Database, ports.Clock),
+		Search:      search.New(ports.Database),
+		Archives:    archives.New(ports.Database, ports.Clock),
+		Backups:     backups.New(ports.Backups, ports.Clock, ports.Logger),
 	}, nil
 }
@@ -16,7 +16,7 @@ export const RecipeTable = () => {
 			header: "Name"
     }),
     columnHelper.accessor("ingredients", {
-				cell: ({ row }) => `${row.original.ingredients.map(i => `${i.units}${i.ingredient.unit} of ${i.ingredient.name}`).join(', ')}`,
+				cell: ({ row }) => `${row.original.ingredients.map(i => `${i.units}${i.unit} of ${i.name}`).join(', ')}`,
         header: "Ingredients",
 				enableSorting: false
     }),
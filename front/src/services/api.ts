@@ -1,26 +1,62 @@
-import { createApi, fetchBaseQuery } from '@reduxjs/toolkit/query/react'
+import { BaseQueryFn, createApi, fetchBaseQuery, FetchArgs, FetchBaseQueryError } from '@reduxjs/toolkit/query/react'
 
 export interface Ingredient {
   id: number
   name: string
   unit: string
   price: number
+  units_in_stock: number
+  allergens: string[]
+  category_id: number | null
+  tags: string[]
   created_at: string
   last_modified: string
 }
 
 export interface RecipeIngredient {
-  ingredient: Ingredient
+  id: number
+  name: string
+  unit: string
+  price: number
   units: number
+  allergens: string[]
 }
 
 export interface Recipe {
   id: number
   name: string
+  price: number
+  portions: number
   ingredients: RecipeIngredient[]
+  category_id: number | null
+  tags: string[]
   created_at: string
   last_modified: string
   cost: number
+  food_cost_percentage: number
+  allergens: string[]
+}
+
+export interface Page<T> {
+  data: T[]
+  next_cursor: string | null
+}
+
+// fetchAllPages follows the cursors of a list until its last page, so that
+// tables show every entity rather than the first page alone.
+async function fetchAllPages<T>(path: string, baseQuery: BaseQueryFn<string | FetchArgs, unknown, FetchBaseQueryError>): Promise<{ data: T[] } | { error: FetchBaseQueryError }> {
+  const data: T[] = []
+  let cursor: string | null = null
+  do {
+    const result = await baseQuery(cursor ? `${path}?cursor=${encodeURIComponent(cursor)}` : path)
+    if (result.error) {
+      return { error: result.error }
+    }
+    const page = result.data as Page<T>
+    data.push(...page.data)
+    cursor = page.next_cursor
+  } while (cursor)
+  return { data }
 }
 
 export const costlyAPI = createApi({
@@ -34,11 +70,11 @@ export const costlyAPI = createApi({
   tagTypes: ['Ingredients', 'Recipes'],
   endpoints: (builder) => ({
     getIngredients: builder.query<Ingredient[], void>({
-      query: () => `ingredients`,
+      queryFn: (_arg, _api, _extraOptions, baseQuery) => fetchAllPages<Ingredient>('ingredients', baseQuery),
       providesTags: ['Ingredients'],
     }),
     getRecipes: builder.query<Recipe[], void>({
-      query: () => `recipes`,
+      queryFn: (_arg, _api, _extraOptions, baseQuery) => fetchAllPages<Recipe>('recipes', baseQuery),
       providesTags: ['Recipes'],
     }),
   }),
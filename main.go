@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"os"
 
@@ -17,7 +19,15 @@ func main() {
 		fmt.Printf("Could not load configuration. Err: %s\n", err)
 		os.Exit(1)
 	}
-	ports, err := ports.New(config.LogLevel, config.Database.ConnectionString)
+	ran, err := runDatabaseCommand(flag.Args(), config, os.Stdout)
+	if err != nil {
+		fmt.Printf("Could not run command. Err: %s\n", err)
+		os.Exit(1)
+	}
+	if ran {
+		return
+	}
+	ports, err := ports.New(config.LogLevel, config.Database.ConnectionString, config.Storage.Dir, config.Backup.Dir, config.Backup.Retention)
 	if err != nil {
 		fmt.Printf("Could not initialize adapters. Err: %s\n", err)
 		os.Exit(1)
@@ -27,5 +37,16 @@ func main() {
 		fmt.Printf("Could not initialize components. Err: %s\n", err)
 		os.Exit(1)
 	}
+	ran, err = runCommand(flag.Args(), components, os.Stdout)
+	if err != nil {
+		fmt.Printf("Could not run command. Err: %s\n", err)
+		os.Exit(1)
+	}
+	if ran {
+		return
+	}
+	if config.Backup.Interval > 0 {
+		go components.Backups.Schedule(context.Background(), config.Backup.Interval)
+	}
 	api.NewServer(config.ListenAddress, config.AuthSecret, components, ports.Logger).Start()
 }
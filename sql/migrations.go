@@ -19,29 +19,38 @@ var fs embed.FS
 func RunMigrations(db *sql.DB, logger logger.Logger) (string, error) {
 	logger.Info("running migrations")
 
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return "", err
+	}
+
+	if err = migrator.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return "", fmt.Errorf("error executing migrations: %w", err)
+	}
+
+	version, _, _ := migrator.Version()
+
+	logger.Info("migrations run")
+	return fmt.Sprint(version), nil
+}
+
+// newMigrator creates a migrator running the embedded migrations on db.
+func newMigrator(db *sql.DB) (*migrate.Migrate, error) {
 	driver, err := sqlite3.WithInstance(db, &sqlite3.Config{
 		MigrationsTable: "migrations",
 	})
 	if err != nil {
-		return "", fmt.Errorf("error creating migrations driver: %w", err)
+		return nil, fmt.Errorf("error creating migrations driver: %w", err)
 	}
 
 	d, err := iofs.New(fs, "migrations")
 	if err != nil {
-		return "", fmt.Errorf("error loading migration files: %w", err)
+		return nil, fmt.Errorf("error loading migration files: %w", err)
 	}
 
 	migrator, err := migrate.NewWithInstance("iofs", d, "mysql", driver)
 	if err != nil {
-		return "", fmt.Errorf("error creating migrator: %w", err)
-	}
-
-	if err = migrator.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return "", fmt.Errorf("error executing migrations: %w", err)
+		return nil, fmt.Errorf("error creating migrator: %w", err)
 	}
-
-	version, _, _ := migrator.Version()
-
-	logger.Info("migrations run")
-	return fmt.Sprint(version), nil
+	return migrator, nil
 }
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS production_history;
\ No newline at end of file
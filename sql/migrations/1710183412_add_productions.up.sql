@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS production_history (
+    id INTEGER PRIMARY KEY,
+    recipe_id INTEGER NOT NULL,
+    ingredient_id INTEGER NOT NULL,
+    batches INTEGER NOT NULL,
+    units INTEGER NOT NULL,
+    price FLOAT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY(recipe_id) REFERENCES recipe(id),
+    FOREIGN KEY(ingredient_id) REFERENCES ingredient(id)
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS ingredient_allergen;
\ No newline at end of file
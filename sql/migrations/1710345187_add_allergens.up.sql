@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS ingredient_allergen (
+    ingredient_id INTEGER NOT NULL,
+    allergen TEXT NOT NULL,
+    PRIMARY KEY (ingredient_id, allergen),
+    FOREIGN KEY(ingredient_id) REFERENCES ingredient(id)
+);
@@ -0,0 +1,10 @@
+ALTER TABLE recipe DROP COLUMN portions;
+
+ALTER TABLE ingredient DROP COLUMN fibre;
+ALTER TABLE ingredient DROP COLUMN salt;
+ALTER TABLE ingredient DROP COLUMN sugar;
+ALTER TABLE ingredient DROP COLUMN carbohydrate;
+ALTER TABLE ingredient DROP COLUMN saturated_fat;
+ALTER TABLE ingredient DROP COLUMN fat;
+ALTER TABLE ingredient DROP COLUMN protein;
+ALTER TABLE ingredient DROP COLUMN energy;
\ No newline at end of file
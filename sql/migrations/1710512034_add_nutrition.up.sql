@@ -0,0 +1,10 @@
+ALTER TABLE ingredient ADD energy FLOAT NOT NULL DEFAULT 0;
+ALTER TABLE ingredient ADD protein FLOAT NOT NULL DEFAULT 0;
+ALTER TABLE ingredient ADD fat FLOAT NOT NULL DEFAULT 0;
+ALTER TABLE ingredient ADD saturated_fat FLOAT NOT NULL DEFAULT 0;
+ALTER TABLE ingredient ADD carbohydrate FLOAT NOT NULL DEFAULT 0;
+ALTER TABLE ingredient ADD sugar FLOAT NOT NULL DEFAULT 0;
+ALTER TABLE ingredient ADD salt FLOAT NOT NULL DEFAULT 0;
+ALTER TABLE ingredient ADD fibre FLOAT NOT NULL DEFAULT 0;
+
+ALTER TABLE recipe ADD portions INTEGER NOT NULL DEFAULT 1;
\ No newline at end of file
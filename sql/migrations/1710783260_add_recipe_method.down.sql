@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS recipe_attachment;
+DROP TABLE IF EXISTS recipe_step;
+
+ALTER TABLE recipe DROP COLUMN cook_minutes;
+ALTER TABLE recipe DROP COLUMN prep_minutes;
+ALTER TABLE recipe DROP COLUMN plating_notes;
\ No newline at end of file
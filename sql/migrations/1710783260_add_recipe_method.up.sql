@@ -0,0 +1,22 @@
+ALTER TABLE recipe ADD plating_notes TEXT NOT NULL DEFAULT '';
+ALTER TABLE recipe ADD prep_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE recipe ADD cook_minutes INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS recipe_step (
+    recipe_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    description TEXT NOT NULL,
+    PRIMARY KEY (recipe_id, position),
+    FOREIGN KEY(recipe_id) REFERENCES recipe(id)
+);
+
+CREATE TABLE IF NOT EXISTS recipe_attachment (
+    id INTEGER PRIMARY KEY,
+    recipe_id INTEGER NOT NULL,
+    file_name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size INTEGER NOT NULL,
+    storage_key TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY(recipe_id) REFERENCES recipe(id)
+);
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE sold_recipes_history DROP COLUMN revision_id;
+
+DROP TABLE IF EXISTS recipe_revision_ingredient;
+DROP TABLE IF EXISTS recipe_revision;
\ No newline at end of file
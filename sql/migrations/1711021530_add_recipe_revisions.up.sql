@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS recipe_revision (
+    id INTEGER PRIMARY KEY,
+    recipe_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    portions INTEGER NOT NULL,
+    cost FLOAT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE (recipe_id, version),
+    FOREIGN KEY(recipe_id) REFERENCES recipe(id)
+);
+
+CREATE TABLE IF NOT EXISTS recipe_revision_ingredient (
+    revision_id INTEGER NOT NULL,
+    ingredient_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    price FLOAT NOT NULL,
+    units INTEGER NOT NULL,
+    PRIMARY KEY (revision_id, ingredient_id),
+    FOREIGN KEY(revision_id) REFERENCES recipe_revision(id),
+    FOREIGN KEY(ingredient_id) REFERENCES ingredient(id)
+);
+
+INSERT INTO recipe_revision (recipe_id, version, name, portions, cost, created_at)
+SELECT r.id, 1, r.name, r.portions, (SELECT COALESCE(SUM(i.price * ri.units), 0) FROM recipe_ingredient ri JOIN ingredient i ON i.id = ri.ingredient_id WHERE ri.recipe_id = r.id), r.created_at
+FROM recipe r;
+
+INSERT INTO recipe_revision_ingredient (revision_id, ingredient_id, name, price, units)
+SELECT rr.id, i.id, i.name, i.price, ri.units
+FROM recipe_revision rr JOIN recipe_ingredient ri ON ri.recipe_id = rr.recipe_id JOIN ingredient i ON i.id = ri.ingredient_id;
+
+ALTER TABLE sold_recipes_history ADD revision_id INTEGER;
+
+UPDATE sold_recipes_history SET revision_id = (SELECT id FROM recipe_revision WHERE recipe_id = sold_recipes_history.recipe_id);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS ingredient_price_history;
\ No newline at end of file
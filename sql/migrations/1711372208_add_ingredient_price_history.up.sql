@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS ingredient_price_history (
+    id INTEGER PRIMARY KEY,
+    ingredient_id INTEGER NOT NULL,
+    price FLOAT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY(ingredient_id) REFERENCES ingredient(id)
+);
+
+CREATE INDEX IF NOT EXISTS ingredient_price_history_ingredient_id_created_at ON ingredient_price_history (ingredient_id, created_at);
+
+-- The price an ingredient was created with is not kept. Ingredients without stock are assumed to have had their current price since they were created.
+-- The history of the others starts at their first stock added instead, as their price before it can not be known.
+INSERT INTO ingredient_price_history (ingredient_id, price, created_at)
+SELECT i.id, i.price, i.created_at FROM ingredient i
+WHERE NOT EXISTS (SELECT 1 FROM stock_history s WHERE s.ingredient_id = i.id);
+
+-- Adding stock sets the price of its ingredient.
+INSERT INTO ingredient_price_history (ingredient_id, price, created_at)
+SELECT ingredient_id, price, created_at FROM stock_history;
+
+-- A price edited after the last stock added was set at most when its ingredient was last modified.
+INSERT INTO ingredient_price_history (ingredient_id, price, created_at)
+SELECT i.id, i.price, i.last_modified FROM ingredient i
+WHERE i.price != (SELECT h.price FROM ingredient_price_history h WHERE h.ingredient_id = i.id ORDER BY h.created_at DESC, h.id DESC LIMIT 1);
\ No newline at end of file
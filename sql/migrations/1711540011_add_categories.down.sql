@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS ingredient_tag;
+DROP TABLE IF EXISTS recipe_tag;
+
+ALTER TABLE ingredient DROP COLUMN category_id;
+ALTER TABLE recipe DROP COLUMN category_id;
+ALTER TABLE recipe DROP COLUMN price;
+
+DROP TABLE IF EXISTS category;
\ No newline at end of file
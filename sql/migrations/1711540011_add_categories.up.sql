@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS category (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    parent_id INTEGER,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY(parent_id) REFERENCES category(id)
+);
+
+INSERT INTO category (name, parent_id, created_at) VALUES
+    ('starters', NULL, CURRENT_TIMESTAMP),
+    ('mains', NULL, CURRENT_TIMESTAMP),
+    ('desserts', NULL, CURRENT_TIMESTAMP),
+    ('drinks', NULL, CURRENT_TIMESTAMP);
+
+ALTER TABLE recipe ADD price FLOAT NOT NULL DEFAULT 0;
+ALTER TABLE recipe ADD category_id INTEGER;
+ALTER TABLE ingredient ADD category_id INTEGER;
+
+CREATE TABLE IF NOT EXISTS recipe_tag (
+    recipe_id INTEGER NOT NULL,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (recipe_id, tag),
+    FOREIGN KEY(recipe_id) REFERENCES recipe(id)
+);
+
+CREATE TABLE IF NOT EXISTS ingredient_tag (
+    ingredient_id INTEGER NOT NULL,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (ingredient_id, tag),
+    FOREIGN KEY(ingredient_id) REFERENCES ingredient(id)
+);
\ No newline at end of file
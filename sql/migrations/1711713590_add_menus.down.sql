@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS menu_item_recipe;
+DROP TABLE IF EXISTS menu_item;
+DROP TABLE IF EXISTS menu;
\ No newline at end of file
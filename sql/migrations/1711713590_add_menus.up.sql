@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS menu (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    channel TEXT NOT NULL,
+    valid_from TIMESTAMP,
+    valid_until TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    last_modified TIMESTAMP NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS menu_item (
+    id INTEGER PRIMARY KEY,
+    menu_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    price FLOAT NOT NULL,
+    FOREIGN KEY(menu_id) REFERENCES menu(id)
+);
+
+CREATE TABLE IF NOT EXISTS menu_item_recipe (
+    menu_item_id INTEGER NOT NULL,
+    recipe_id INTEGER NOT NULL,
+    units INTEGER NOT NULL,
+    PRIMARY KEY (menu_item_id, recipe_id),
+    FOREIGN KEY(menu_item_id) REFERENCES menu_item(id),
+    FOREIGN KEY(recipe_id) REFERENCES recipe(id)
+);
\ No newline at end of file
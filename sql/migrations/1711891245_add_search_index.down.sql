@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS recipe_search;
+DROP TABLE IF EXISTS ingredient_search;
\ No newline at end of file
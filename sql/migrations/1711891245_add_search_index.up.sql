@@ -0,0 +1,11 @@
+CREATE VIRTUAL TABLE IF NOT EXISTS ingredient_search USING fts5(name, tags, tokenize="unicode61 remove_diacritics 2");
+
+CREATE VIRTUAL TABLE IF NOT EXISTS recipe_search USING fts5(name, tags, steps, tokenize="unicode61 remove_diacritics 2");
+
+INSERT INTO ingredient_search (rowid, name, tags)
+SELECT i.id, i.name, COALESCE((SELECT group_concat(it.tag, ' ') FROM ingredient_tag it WHERE it.ingredient_id = i.id), '')
+FROM ingredient i;
+
+INSERT INTO recipe_search (rowid, name, tags, steps)
+SELECT r.id, r.name, COALESCE((SELECT group_concat(rt.tag, ' ') FROM recipe_tag rt WHERE rt.recipe_id = r.id), ''), COALESCE((SELECT group_concat(rs.description, ' ') FROM recipe_step rs WHERE rs.recipe_id = r.id), '')
+FROM recipe r;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE recipe DROP COLUMN version;
+ALTER TABLE ingredient DROP COLUMN version;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE ingredient ADD version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE recipe ADD version INTEGER NOT NULL DEFAULT 1;
\ No newline at end of file
@@ -0,0 +1,65 @@
+package sql
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type priceAt struct {
+	price     float64
+	createdAt time.Time
+}
+
+func TestPriceHistoryBackfill(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "costly.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	migrator, err := newMigrator(db)
+	require.NoError(t, err)
+	require.NoError(t, migrator.Migrate(1711021530))
+
+	day := func(d int) time.Time {
+		return time.Date(2024, time.March, d, 12, 0, 0, 0, time.UTC)
+	}
+	seed := []struct {
+		query string
+		args  []any
+	}{
+		{"INSERT INTO ingredient (id, name, unit, price, created_at, last_modified) VALUES (?, ?, ?, ?, ?, ?)", []any{1, "restocked", "gr", 2.0, day(1), day(3)}},
+		{"INSERT INTO stock_history (ingredient_id, units, price, created_at) VALUES (?, ?, ?, ?)", []any{1, 10, 1.5, day(2)}},
+		{"INSERT INTO stock_history (ingredient_id, units, price, created_at) VALUES (?, ?, ?, ?)", []any{1, 10, 2.0, day(3)}},
+		{"INSERT INTO ingredient (id, name, unit, price, created_at, last_modified) VALUES (?, ?, ?, ?, ?, ?)", []any{2, "edited", "gr", 4.0, day(1), day(5)}},
+		{"INSERT INTO stock_history (ingredient_id, units, price, created_at) VALUES (?, ?, ?, ?)", []any{2, 5, 3.0, day(4)}},
+		{"INSERT INTO ingredient (id, name, unit, price, created_at, last_modified) VALUES (?, ?, ?, ?, ?, ?)", []any{3, "untouched", "gr", 5.0, day(1), day(1)}},
+	}
+	for _, s := range seed {
+		_, err := db.Exec(s.query, s.args...)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, migrator.Migrate(1711372208))
+
+	history := func(ingredientID int) []priceAt {
+		rows, err := db.Query("SELECT price, created_at FROM ingredient_price_history WHERE ingredient_id = ? ORDER BY created_at, id", ingredientID)
+		require.NoError(t, err)
+		defer rows.Close()
+		prices := []priceAt{}
+		for rows.Next() {
+			var p priceAt
+			require.NoError(t, rows.Scan(&p.price, &p.createdAt))
+			prices = append(prices, p)
+		}
+		require.NoError(t, rows.Err())
+		return prices
+	}
+
+	assert.Equal(t, []priceAt{{1.5, day(2)}, {2.0, day(3)}}, history(1))
+	assert.Equal(t, []priceAt{{3.0, day(4)}, {4.0, day(5)}}, history(2))
+	assert.Equal(t, []priceAt{{5.0, day(1)}}, history(3))
+}